@@ -0,0 +1,116 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Префикс персональных токенов доступа, по нему middleware отличает их от JWT
+const AccessTokenPrefix = "todo_pat_"
+
+// Области доступа персональных токенов. Право на запись включает право на чтение
+const (
+	ScopeListsRead       = "lists:read"
+	ScopeListsWrite      = "lists:write"
+	ScopeItemsRead       = "items:read"
+	ScopeItemsWrite      = "items:write"
+	ScopeTagsRead        = "tags:read"
+	ScopeTagsWrite       = "tags:write"
+	ScopeWorkspacesRead  = "workspaces:read"
+	ScopeWorkspacesWrite = "workspaces:write"
+
+	// Управление учетной записью (сессии, токены). Персональному токену не выдается,
+	// такие запросы возможны только с токеном входа
+	ScopeAccount = "account"
+)
+
+// Области, которые можно выдать персональному токену
+var AccessTokenScopes = []string{
+	ScopeListsRead, ScopeListsWrite,
+	ScopeItemsRead, ScopeItemsWrite,
+	ScopeTagsRead, ScopeTagsWrite,
+	ScopeWorkspacesRead, ScopeWorkspacesWrite,
+}
+
+const MaxAccessTokenTTL = 365 * 24 * time.Hour // максимальный срок действия персонального токена
+
+// Персональный токен доступа для скриптов и интеграций. Сам токен показывается
+// только при создании, хранится его хэш
+type AccessToken struct {
+	Id         int            `json:"id" db:"id"`
+	UserId     int            `json:"-" db:"user_id"`
+	Name       string         `json:"name" db:"name"`
+	Scopes     pq.StringArray `json:"scopes" db:"scopes" swaggertype:"array,string"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty" db:"expires_at"`     // nil - бессрочный
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty" db:"last_used_at"` // обновляется не чаще раза в минуту
+}
+
+type CreateAccessTokenInput struct {
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required"`
+	ExpiresIn int      `json:"expires_in"` // срок действия в днях (0 - бессрочный, не более 365)
+}
+
+func (i CreateAccessTokenInput) Validate() error {
+	if len(i.Name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+
+	if len(i.Scopes) == 0 {
+		return errors.New("at least one scope required")
+	}
+
+	seen := make(map[string]bool, len(i.Scopes))
+	for _, scope := range i.Scopes {
+		if !isAccessTokenScope(scope) {
+			return fmt.Errorf("invalid scope %q (allowed: %s)", scope, strings.Join(AccessTokenScopes, ", "))
+		}
+		if seen[scope] {
+			return fmt.Errorf("duplicate scope %q", scope)
+		}
+		seen[scope] = true
+	}
+
+	if i.ExpiresIn < 0 || time.Duration(i.ExpiresIn)*24*time.Hour > MaxAccessTokenTTL {
+		return errors.New("expires_in must be between 0 and 365 days")
+	}
+
+	return nil
+}
+
+// Срок действия токена (0 - бессрочный)
+func (i CreateAccessTokenInput) TTL() time.Duration {
+	return time.Duration(i.ExpiresIn) * 24 * time.Hour
+}
+
+func isAccessTokenScope(scope string) bool {
+	for _, s := range AccessTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Есть ли среди выданных областей required. Область "x:write" включает "x:read"
+func HasScope(granted []string, required string) bool {
+	for _, scope := range granted {
+		if scope == required {
+			return true
+		}
+		if strings.HasSuffix(required, ":read") && scope == strings.TrimSuffix(required, ":read")+":write" {
+			return true
+		}
+	}
+	return false
+}
+
+// Ошибка недостаточной области доступа токена
+func ErrScopeRequired(required string) error {
+	return fmt.Errorf("token scope %s required", required)
+}
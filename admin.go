@@ -0,0 +1,38 @@
+package todo
+
+import "time"
+
+// Пользователь в списке администратора, с числом списков, которыми он владеет, и задач в них
+type AdminUser struct {
+	Id            int        `json:"id" db:"id"`
+	Name          string     `json:"name" db:"name"`
+	Username      string     `json:"username" db:"username"`
+	Email         string     `json:"email,omitempty" db:"email"`
+	EmailVerified bool       `json:"email_verified" db:"email_verified"`
+	IsAdmin       bool       `json:"is_admin" db:"is_admin"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty" db:"disabled_at"` // nil - учетная запись активна
+	Lists         int        `json:"lists" db:"lists"`
+	Items         int        `json:"items" db:"items"`
+}
+
+const (
+	AdminUsersDefaultLimit = 50
+	AdminUsersMaxLimit     = 200
+)
+
+// Поиск пользователей: Query ищется в username, имени и почте без учета регистра
+type AdminUsersQuery struct {
+	Query  string `form:"q"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// Причина действия администратора, записывается в журнал аудита
+type AdminActionInput struct {
+	Reason string `json:"reason"`
+}
+
+// Вход от имени пользователя для поддержки, причина обязательна
+type ImpersonateInput struct {
+	Reason string `json:"reason" binding:"required"`
+}
@@ -0,0 +1,204 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo-app/pkg/recurrence"
+)
+
+// Версия формата архива. Увеличивается при несовместимых изменениях, импорт принимает только известные версии
+const ArchiveVersion = 1
+
+// Архив данных пользователя (экспорт и импорт). Id в архиве - идентификаторы исходной базы,
+// по ним задаются связи внутри архива, при импорте выдаются новые
+type Archive struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Profile    ArchiveProfile `json:"profile"`
+	Tags       []ArchiveTag   `json:"tags"`
+	Lists      []ArchiveList  `json:"lists"` // списки, которыми владеет пользователь, в его порядке сортировки
+}
+
+// Профиль только для сведения, при импорте не применяется
+type ArchiveProfile struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+}
+
+type ArchiveTag struct {
+	Id    int    `json:"id" db:"id"`
+	Name  string `json:"name" db:"name"`
+	Color string `json:"color,omitempty" db:"color"`
+}
+
+type ArchiveList struct {
+	Id          int           `json:"id" db:"id"`
+	Title       string        `json:"title" db:"title"`
+	Description string        `json:"description" db:"description"`
+	Items       []ArchiveItem `json:"items"` // в порядке ручной сортировки
+}
+
+type ArchiveItem struct {
+	Id          int               `json:"id" db:"id"`
+	ListId      int               `json:"-" db:"list_id"`
+	Title       string            `json:"title" db:"title"`
+	Description string            `json:"description" db:"description"`
+	Done        bool              `json:"done" db:"done"`
+	DueDate     *time.Time        `json:"due_date,omitempty" db:"due_date"`
+	DueAllDay   bool              `json:"due_all_day,omitempty" db:"due_all_day"`
+	DueTimezone string            `json:"due_timezone,omitempty" db:"due_timezone"`
+	Priority    Priority          `json:"priority,omitempty" db:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	ParentId    *int              `json:"parent_id,omitempty" db:"parent_id"` // id задачи того же списка в архиве
+	Recurrence  string            `json:"recurrence,omitempty" db:"recurrence"`
+	Occurrence  int               `json:"occurrence,omitempty" db:"occurrence"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	TagIds      []int             `json:"tag_ids,omitempty" db:"-"` // id меток из архива
+	Reminders   []ArchiveReminder `json:"reminders,omitempty" db:"-"`
+}
+
+type ArchiveReminder struct {
+	ItemId        int        `json:"-" db:"item_id"`
+	RemindAt      *time.Time `json:"remind_at,omitempty" db:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty" db:"offset_minutes"`
+	Channel       string     `json:"channel" db:"channel" enums:"webhook,email"`
+	Target        string     `json:"target" db:"target"`
+	FiredAt       *time.Time `json:"fired_at,omitempty" db:"fired_at"`
+}
+
+// Сколько записей создано при импорте
+type ImportResult struct {
+	Lists int `json:"lists"`
+	Items int `json:"items"`
+	Tags  int `json:"tags"` // новые метки (метки с уже существующим именем объединяются)
+}
+
+// Проверка версии и связей внутри архива: id не повторяются, родитель задачи - задача того же списка
+// без циклов, метки задач есть в архиве. Сроки, правила повторения и адреса напоминаний
+// при проверке приводятся к тому виду, в котором их сохраняет API
+func (a *Archive) Validate() error {
+	if a.Version != ArchiveVersion {
+		return fmt.Errorf("unsupported archive version: %d (supported: %d)", a.Version, ArchiveVersion)
+	}
+
+	tags := make(map[int]bool, len(a.Tags))
+	for _, tag := range a.Tags {
+		if tags[tag.Id] {
+			return fmt.Errorf("duplicate tag id: %d", tag.Id)
+		}
+		tags[tag.Id] = true
+		if err := ValidateTagName(tag.Name); err != nil {
+			return fmt.Errorf("tag %d: %s", tag.Id, err.Error())
+		}
+	}
+
+	lists := make(map[int]bool, len(a.Lists))
+	items := make(map[int]int) // id задачи -> id списка
+	for _, list := range a.Lists {
+		if lists[list.Id] {
+			return fmt.Errorf("duplicate list id: %d", list.Id)
+		}
+		lists[list.Id] = true
+		if strings.TrimSpace(list.Title) == "" {
+			return fmt.Errorf("list %d: title is empty", list.Id)
+		}
+
+		for j := range list.Items {
+			item := &list.Items[j]
+			if _, ok := items[item.Id]; ok {
+				return fmt.Errorf("duplicate item id: %d", item.Id)
+			}
+			items[item.Id] = list.Id
+			if err := item.validate(tags); err != nil {
+				return fmt.Errorf("item %d: %s", item.Id, err.Error())
+			}
+		}
+	}
+
+	parents := make(map[int]int)
+	for _, list := range a.Lists {
+		for _, item := range list.Items {
+			if item.ParentId == nil {
+				continue
+			}
+			if listId, ok := items[*item.ParentId]; !ok || listId != list.Id {
+				return fmt.Errorf("item %d: parent is not an item of the same list", item.Id)
+			}
+			parents[item.Id] = *item.ParentId
+		}
+	}
+
+	for id := range parents {
+		seen := map[int]bool{id: true}
+		for parent, ok := parents[id]; ok; parent, ok = parents[parent] {
+			if seen[parent] {
+				return fmt.Errorf("item %d: parent cycle", id)
+			}
+			seen[parent] = true
+		}
+	}
+
+	return nil
+}
+
+func (i *ArchiveItem) validate(tags map[int]bool) error {
+	if strings.TrimSpace(i.Title) == "" {
+		return errors.New("title is empty")
+	}
+	if !i.Priority.IsValid() {
+		return fmt.Errorf("invalid priority: %d", int(i.Priority))
+	}
+	var loc *time.Location
+	if i.DueTimezone != "" {
+		l, err := time.LoadLocation(i.DueTimezone)
+		if err != nil {
+			return fmt.Errorf("invalid due timezone: %s", i.DueTimezone)
+		}
+		loc = l
+	}
+	if i.DueDate != nil {
+		// В архиве срок - момент времени, поэтому для задач "на весь день" дата берется в поясе задачи
+		due := *i.DueDate
+		if loc != nil {
+			due = due.In(loc)
+		}
+		due, err := NormalizeDueDate(due, i.DueAllDay, i.DueTimezone)
+		if err != nil {
+			return err
+		}
+		i.DueDate = &due
+	} else {
+		i.DueAllDay, i.DueTimezone = false, "" // без срока эти поля не имеют смысла
+	}
+	if i.Recurrence != "" {
+		if i.DueDate == nil {
+			return errors.New("recurring item requires due date")
+		}
+		rule, err := recurrence.Parse(i.Recurrence)
+		if err != nil {
+			return fmt.Errorf("invalid recurrence: %s", err.Error())
+		}
+		i.Recurrence = rule.String()
+	}
+	if i.Occurrence < 0 {
+		return errors.New("invalid occurrence")
+	}
+
+	for _, tagId := range i.TagIds {
+		if !tags[tagId] {
+			return fmt.Errorf("unknown tag id: %d", tagId)
+		}
+	}
+
+	for k, reminder := range i.Reminders {
+		r := Reminder{RemindAt: reminder.RemindAt, OffsetMinutes: reminder.OffsetMinutes, Channel: reminder.Channel, Target: reminder.Target}
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		i.Reminders[k].Target = r.Target
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Действия журнала аудита
+const (
+	AuditLoginLockout = "login.lockout" // временная блокировка входа после неудачных попыток
+
+	// Действия администратора, user_id - пользователь, над которым выполнено действие,
+	// id администратора в details.admin_id
+	AuditAdminDisable       = "admin.disable"
+	AuditAdminEnable        = "admin.enable"
+	AuditAdminPasswordReset = "admin.password_reset"
+	AuditAdminImpersonate   = "admin.impersonate"
+)
+
+type AuditRecord struct {
+	Id        int            `json:"id" db:"id"`
+	UserId    *int           `json:"user_id" db:"user_id"` // nil - пользователь неизвестен
+	Action    string         `json:"action" db:"action"`
+	Ip        string         `json:"ip" db:"ip"`
+	Details   types.JSONText `json:"details" db:"details"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
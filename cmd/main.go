@@ -1,10 +1,17 @@
 package main
 
 import (
+	ctx "context"
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // База часовых поясов для сроков задач (в образе distroless ее может не быть)
+	"todo-app"
 	"todo-app/pkg/handler"
+	"todo-app/pkg/jwtkeys"
+	"todo-app/pkg/mailer"
+	"todo-app/pkg/notifier"
+	"todo-app/pkg/oidc"
 	"todo-app/pkg/repository"
 	"todo-app/pkg/service"
 
@@ -68,8 +75,87 @@ func main() {
 		return
 	}
 
+	var jwtKeys []jwtkeys.KeyConfig
+	if err := viper.UnmarshalKey("jwt.keys", &jwtKeys); err != nil {
+		logrus.Fatalf("error reading jwt keys config: %s", err.Error())
+		return
+	}
+
+	keys, err := jwtkeys.New(jwtkeys.Config{ // Ключи подписи токенов, секреты читаются из .env
+		ActiveKey: viper.GetString("jwt.active_key"),
+		Keys:      jwtKeys,
+	})
+	if err != nil {
+		logrus.Fatalf("failed to load jwt keys: %s", err.Error())
+		return
+	}
+
+	mailTemplatesFS, err := handler.MailTemplates()
+	if err != nil {
+		logrus.Fatalf("failed to load mail templates: %s", err.Error())
+		return
+	}
+	mailTemplates, err := mailer.NewTemplates(mailTemplatesFS)
+	if err != nil {
+		logrus.Fatalf("failed to parse mail templates: %s", err.Error())
+		return
+	}
+
+	var mail mailer.Mailer // Письма пользователям (подтверждение почты, сброс пароля)
+	switch viper.GetString("mail.driver") {
+	case "smtp":
+		mail = mailer.NewSMTPMailer(mailer.ConfigSMTP{
+			Host:     viper.GetString("smtp.host"),
+			Port:     viper.GetString("smtp.port"),
+			Username: viper.GetString("smtp.username"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     viper.GetString("smtp.from"),
+		})
+	case "file":
+		mail = mailer.NewFileMailer(viper.GetString("mail.dir"), viper.GetString("smtp.from"))
+	default:
+		logrus.Fatalf("unknown mail driver: %s", viper.GetString("mail.driver"))
+		return
+	}
+
+	var oidcConfigs []oidc.Config
+	if err := viper.UnmarshalKey("oidc.providers", &oidcConfigs); err != nil {
+		logrus.Fatalf("error reading oidc providers config: %s", err.Error())
+		return
+	}
+
+	oidcProviders := make([]*oidc.Provider, 0, len(oidcConfigs)) // Провайдеры входа OpenID Connect
+	for _, cfg := range oidcConfigs {
+		provider, err := oidc.NewProvider(cfg, nil)
+		if err != nil {
+			logrus.Fatalf("failed to configure oidc provider: %s", err.Error())
+			return
+		}
+		oidcProviders = append(oidcProviders, provider)
+	}
+
 	repos := repository.NewRepository(db, context, redisClient) // Создание зависимостей
-	services := service.NewService(repos)
+	services := service.NewService(repos, keys, service.ConfigAuth{
+		AccessTTL:  viper.GetDuration("auth.access_ttl"),
+		RefreshTTL: viper.GetDuration("auth.refresh_ttl"),
+		TOTPIssuer: viper.GetString("auth.totp_issuer"),
+		Login: service.ConfigLogin{
+			Window:          viper.GetDuration("auth.login.window"),
+			BackoffAfter:    viper.GetInt64("auth.login.backoff_after"),
+			IpBackoffAfter:  viper.GetInt64("auth.login.ip_backoff_after"),
+			BackoffBase:     viper.GetDuration("auth.login.backoff_base"),
+			BackoffMax:      viper.GetDuration("auth.login.backoff_max"),
+			LockoutAfter:    viper.GetInt64("auth.login.lockout_after"),
+			LockoutDuration: viper.GetDuration("auth.login.lockout_duration"),
+		},
+	}, service.ConfigMail{
+		Mailer:    mail,
+		Templates: mailTemplates,
+		BaseURL:   viper.GetString("mail.base_url"),
+		ResetURL:  viper.GetString("mail.reset_url"),
+	}, service.ConfigOIDC{
+		Providers: oidcProviders,
+	})
 	handlers := handler.NewHandler(services)
 
 	rsv, err := handlers.InitRoutes()
@@ -78,6 +164,31 @@ func main() {
 		return
 	}
 
+	scheduler := service.NewReminderScheduler(repos.Reminders, map[string]notifier.Notifier{ // Отправка напоминаний
+		todo.ReminderChannelWebhook: notifier.NewWebhookNotifier(
+			viper.GetDuration("reminders.webhook_timeout"),
+			os.Getenv("WEBHOOK_SECRET"), // Ключ подписи вебхуков из файла .env
+		),
+		todo.ReminderChannelEmail: notifier.NewSMTPNotifier(notifier.ConfigSMTP{
+			Host:     viper.GetString("smtp.host"),
+			Port:     viper.GetString("smtp.port"),
+			Username: viper.GetString("smtp.username"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     viper.GetString("smtp.from"),
+		}),
+	}, service.ConfigScheduler{
+		Interval:    viper.GetDuration("reminders.interval"),
+		BatchSize:   viper.GetInt("reminders.batch"),
+		MaxAttempts: viper.GetInt("reminders.max_attempts"),
+	})
+
+	schedulerCtx, stopScheduler := ctx.WithCancel(ctx.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(schedulerCtx)
+		close(schedulerDone)
+	}()
+
 	go func() {
 		if err := rsv.Run(viper.GetString("port")); err != nil {
 			logrus.Fatalf("Error run web serv")
@@ -93,6 +204,9 @@ func main() {
 
 	logrus.Print("TodoApp Stoped")
 
+	stopScheduler()
+	<-schedulerDone
+
 	if err := db.Close(); err != nil {
 		logrus.Errorf("error occured on db connection close: %s", err.Error())
 	}
@@ -0,0 +1,40 @@
+// Локальный провайдер OpenID Connect для разработки: любой вход на странице авторизации
+// сразу успешен для пользователя, заданного флагами. Не использовать в рабочем окружении
+package main
+
+import (
+	"flag"
+	"net/http"
+	"todo-app/pkg/oidc/oidctest"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuerURL := flag.String("issuer", "http://localhost:9000", "issuer URL as seen by the browser and the app")
+	clientId := flag.String("client-id", "todo-app", "client id")
+	clientSecret := flag.String("client-secret", "", "client secret (empty - public client)")
+	subject := flag.String("sub", "dev-user", "subject of the signed in user")
+	email := flag.String("email", "dev@example.com", "email of the signed in user")
+	name := flag.String("name", "Dev User", "name of the signed in user")
+	username := flag.String("username", "dev", "preferred username of the signed in user")
+	flag.Parse()
+
+	issuer, err := oidctest.New(*issuerURL, *clientId, *clientSecret)
+	if err != nil {
+		logrus.Fatalf("failed to create issuer: %s", err.Error())
+	}
+	issuer.SetUser(oidctest.User{
+		Subject:           *subject,
+		Email:             *email,
+		EmailVerified:     true,
+		Name:              *name,
+		PreferredUsername: *username,
+	})
+
+	logrus.Printf("mock oidc issuer %s listening on %s", *issuerURL, *addr)
+	if err := http.ListenAndServe(*addr, issuer); err != nil {
+		logrus.Fatalf("mock oidc issuer: %s", err.Error())
+	}
+}
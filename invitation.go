@@ -0,0 +1,66 @@
+package todo
+
+import (
+	"errors"
+	"time"
+)
+
+// Состояния приглашения. Истекшее приглашение остается в статусе pending,
+// но принять его уже нельзя
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+const (
+	DefaultInvitationTTL = 72 * time.Hour      // срок действия приглашения по умолчанию
+	MaxInvitationTTL     = 30 * 24 * time.Hour // максимальный срок действия приглашения
+)
+
+// Приглашение в список. Адресуется пользователю по username либо выдается ссылкой
+// с токеном, которую может принять любой пользователь (один раз)
+type Invitation struct {
+	Id        int       `json:"id" db:"id"`
+	ListId    int       `json:"list_id" db:"list_id"`
+	ListTitle string    `json:"list_title" db:"list_title"`
+	Inviter   string    `json:"inviter" db:"inviter"`           // username пригласившего
+	Invitee   *string   `json:"invitee,omitempty" db:"invitee"` // username приглашенного (nil - ссылка еще не принята)
+	Role      ListRole  `json:"role" db:"role" swaggertype:"string" enums:"owner,editor,viewer"`
+	Status    string    `json:"status" db:"status" enums:"pending,accepted,declined,revoked"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Создание приглашения. Если Username пустой, создается приглашение по ссылке
+type CreateInvitationInput struct {
+	Username  string   `json:"username"`
+	Role      ListRole `json:"role" binding:"required" swaggertype:"string" enums:"owner,editor,viewer"`
+	ExpiresIn int      `json:"expires_in"` // срок действия в часах (0 - 72 часа, не более 720)
+}
+
+func (i CreateInvitationInput) Validate() error {
+	if !i.Role.IsValid() {
+		return errors.New("invalid role (allowed: owner, editor, viewer)")
+	}
+
+	if i.ExpiresIn < 0 || time.Duration(i.ExpiresIn)*time.Hour > MaxInvitationTTL {
+		return errors.New("expires_in must be between 0 and 720 hours")
+	}
+
+	return nil
+}
+
+// Срок действия приглашения
+func (i CreateInvitationInput) TTL() time.Duration {
+	if i.ExpiresIn == 0 {
+		return DefaultInvitationTTL
+	}
+	return time.Duration(i.ExpiresIn) * time.Hour
+}
+
+// Принятие приглашения по ссылке
+type AcceptInviteLinkInput struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,56 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Роль участника списка. Каждая следующая роль включает права предыдущей
+type ListRole string
+
+const (
+	ListRoleViewer ListRole = "viewer" // чтение списка и задач
+	ListRoleEditor ListRole = "editor" // изменение списка и задач
+	ListRoleOwner  ListRole = "owner"  // удаление списка и управление участниками
+)
+
+var listRoleLevels = map[ListRole]int{
+	ListRoleViewer: 1,
+	ListRoleEditor: 2,
+	ListRoleOwner:  3,
+}
+
+func (r ListRole) IsValid() bool {
+	_, ok := listRoleLevels[r]
+	return ok
+}
+
+// Роль дает права не меньше, чем required
+func (r ListRole) Includes(required ListRole) bool {
+	return r.IsValid() && listRoleLevels[r] >= listRoleLevels[required]
+}
+
+// Ошибка недостаточных прав в списке
+func ErrListRole(required ListRole) error {
+	return fmt.Errorf("list role %s required", required)
+}
+
+type ListMember struct {
+	UserId   int      `json:"user_id" db:"user_id"`
+	Name     string   `json:"name" db:"name"`
+	Username string   `json:"username" db:"username"`
+	Role     ListRole `json:"role" db:"role" swaggertype:"string" enums:"owner,editor,viewer"`
+}
+
+// Добавление участника в список или изменение его роли
+type AddMemberInput struct {
+	Username string   `json:"username" binding:"required"`
+	Role     ListRole `json:"role" binding:"required" swaggertype:"string" enums:"owner,editor,viewer"`
+}
+
+func (i AddMemberInput) Validate() error {
+	if !i.Role.IsValid() {
+		return errors.New("invalid role (allowed: owner, editor, viewer)")
+	}
+	return nil
+}
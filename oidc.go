@@ -0,0 +1,21 @@
+package todo
+
+// Незавершенный вход через провайдера OIDC, хранится до возврата пользователя на callback
+type OIDCLogin struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"` // PKCE
+	DeviceName   string `json:"device_name,omitempty"`
+}
+
+type OIDCLoginInput struct {
+	Provider   string `form:"provider" binding:"required"`
+	DeviceName string `form:"device_name"`
+}
+
+type OIDCCallbackInput struct {
+	State            string `form:"state" binding:"required"`
+	Code             string `form:"code"`
+	Error            string `form:"error"` // провайдер отказал во входе (например, access_denied)
+	ErrorDescription string `form:"error_description"`
+}
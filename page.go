@@ -0,0 +1,118 @@
+package todo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Максимальный размер страницы списков и задач
+const PageMaxLimit = 200
+
+// Направление сортировки. Пустое значение - естественный порядок поля сортировки
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Порядок выдачи списков пользователя
+const (
+	ListSortDefault = ""        // ручная сортировка
+	ListSortTitle   = "title"   // по названию
+	ListSortCreated = "created" // по времени создания
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Курсор страницы: значения полей сортировки последней выданной записи.
+// Клиенту передается в закодированном виде и не разбирается им
+type Cursor struct {
+	Sort   string   `json:"s"`
+	Order  string   `json:"o"`
+	Values []string `json:"v"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Курсор действителен только для той же сортировки, в которой он выдан
+func DecodeCursor(cursor, sort, order string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || len(c.Values) == 0 {
+		return c, ErrInvalidCursor
+	}
+	if c.Sort != sort || c.Order != order {
+		return c, fmt.Errorf("%w: sort or order changed", ErrInvalidCursor)
+	}
+
+	return c, nil
+}
+
+// Параметры выборки списков пользователя. Без Limit выдаются все списки
+type ListsQuery struct {
+	Sort   string `form:"sort"`
+	Order  string `form:"order"`
+	Query  string `form:"q"` // подстрока названия без учета регистра
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor"` // next_cursor предыдущей страницы
+}
+
+func (q ListsQuery) Validate() error {
+	if q.Sort != ListSortDefault && q.Sort != ListSortTitle && q.Sort != ListSortCreated {
+		return fmt.Errorf("invalid sort param: %s", q.Sort)
+	}
+
+	return validatePage(q.Sort, q.Order, q.Limit, q.Cursor)
+}
+
+// Полная выборка в порядке по умолчанию, только она хранится в кэше
+func (q ListsQuery) IsDefault() bool {
+	return q == ListsQuery{}
+}
+
+// Параметры выборки задач списка. Без Limit выдаются все задачи
+type ItemsQuery struct {
+	Sort   string `form:"sort"`
+	Order  string `form:"order"`
+	Done   *bool  `form:"done"`
+	Query  string `form:"q"` // подстрока названия без учета регистра
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor"` // next_cursor предыдущей страницы
+}
+
+func (q ItemsQuery) Validate() error {
+	if err := ValidateItemSort(q.Sort); err != nil {
+		return err
+	}
+
+	return validatePage(q.Sort, q.Order, q.Limit, q.Cursor)
+}
+
+// Полная выборка в порядке по умолчанию, только она хранится в кэше
+func (q ItemsQuery) IsDefault() bool {
+	return q.Sort == ItemSortDefault && q.Order == "" && q.Done == nil && q.Query == "" && q.Limit == 0 && q.Cursor == ""
+}
+
+func validatePage(sort, order string, limit int, cursor string) error {
+	if order != "" && order != SortOrderAsc && order != SortOrderDesc {
+		return fmt.Errorf("invalid order param: %s", order)
+	}
+
+	if cursor == "" {
+		return nil
+	}
+	if limit == 0 {
+		return errors.New("cursor requires limit")
+	}
+	_, err := DecodeCursor(cursor, sort, order)
+
+	return err
+}
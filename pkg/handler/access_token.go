@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createAccessTokenResponse struct {
+	Id    int    `json:"id"`
+	Token string `json:"token"` // показывается один раз
+}
+
+type getAccessTokensResponse struct {
+	Data []todo.AccessToken `json:"data"`
+}
+
+// @Summary Create Access Token
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description create personal access token for scripts (scopes: lists:read, lists:write, items:read, items:write, tags:read, tags:write, workspaces:read, workspaces:write)
+// @ID create-access-token
+// @Accept  json
+// @Produce  json
+// @Param input body todo.CreateAccessTokenInput true "Token name, scopes and lifetime in days"
+// @Success 200 {object} createAccessTokenResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/tokens [post]
+func (h *Handler) createAccessToken(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.CreateAccessTokenInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, token, err := h.services.AccessTokens.Create(userId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, createAccessTokenResponse{
+		Id:    id,
+		Token: token,
+	})
+}
+
+// @Summary Get Access Tokens
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description get personal access tokens of the user
+// @ID get-access-tokens
+// @Produce  json
+// @Success 200 {object} getAccessTokensResponse
+// @Failure 401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/tokens [get]
+func (h *Handler) getAccessTokens(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tokens, err := h.services.AccessTokens.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAccessTokensResponse{
+		Data: tokens,
+	})
+}
+
+// @Summary Delete Access Token
+// @Security ApiKeyAuth
+// @Tags tokens
+// @Description revoke personal access token
+// @ID delete-access-token
+// @Produce  json
+// @Param id path int true "Token Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/tokens/{id} [delete]
+func (h *Handler) deleteAccessToken(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tokenId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid token id param")
+		return
+	}
+
+	if err := h.services.AccessTokens.Revoke(userId, tokenId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
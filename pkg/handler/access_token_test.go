@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createAccessToken(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		input                todo.CreateAccessTokenInput
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"backup","scopes":["lists:read","items:read"],"expires_in":30}`,
+			input:     todo.CreateAccessTokenInput{Name: "backup", Scopes: []string{"lists:read", "items:read"}, ExpiresIn: 30},
+			mockBehavior: func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput) {
+				s.EXPECT().Create(1, input).Return(4, "todo_pat_secret", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":4,"token":"todo_pat_secret"}`,
+		},
+		{
+			name:                 "Unknown Scope",
+			inputBody:            `{"name":"backup","scopes":["lists:delete"]}`,
+			mockBehavior:         func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid scope \"lists:delete\" (allowed: lists:read, lists:write, items:read, items:write, tags:read, tags:write, workspaces:read, workspaces:write)"}`,
+		},
+		{
+			name:                 "Account Scope",
+			inputBody:            `{"name":"backup","scopes":["account"]}`,
+			mockBehavior:         func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid scope \"account\" (allowed: lists:read, lists:write, items:read, items:write, tags:read, tags:write, workspaces:read, workspaces:write)"}`,
+		},
+		{
+			name:                 "Empty Scopes",
+			inputBody:            `{"name":"backup","scopes":[]}`,
+			mockBehavior:         func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"at least one scope required"}`,
+		},
+		{
+			name:                 "Too Long Lifetime",
+			inputBody:            `{"name":"backup","scopes":["lists:read"],"expires_in":400}`,
+			mockBehavior:         func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"expires_in must be between 0 and 365 days"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"name":"backup","scopes":["lists:read"]}`,
+			input:     todo.CreateAccessTokenInput{Name: "backup", Scopes: []string{"lists:read"}},
+			mockBehavior: func(s *mock_service.MockAccessTokens, input todo.CreateAccessTokenInput) {
+				s.EXPECT().Create(1, input).Return(0, "", errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mock_service.NewMockAccessTokens(c)
+			testCase.mockBehavior(tokens, testCase.input)
+
+			services := &service.Service{AccessTokens: tokens}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/me/tokens", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.createAccessToken)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/me/tokens", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getAccessTokens(t *testing.T) {
+	// Init Deps
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	created := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	tokens := mock_service.NewMockAccessTokens(c)
+	tokens.EXPECT().GetAll(1).Return([]todo.AccessToken{
+		{Id: 4, UserId: 1, Name: "backup", Scopes: []string{"lists:read"}, CreatedAt: created},
+	}, nil)
+
+	services := &service.Service{AccessTokens: tokens}
+	handler := NewHandler(services)
+
+	// Test Server
+	r := gin.New()
+	r.GET("/me/tokens", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getAccessTokens)
+
+	// Test Request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/me/tokens", nil)
+
+	// Perform Request
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"data":[{"id":4,"name":"backup","scopes":["lists:read"],"created_at":"2022-06-10T12:00:00Z"}]}`, w.Body.String())
+}
+
+func TestHandler_deleteAccessToken(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccessTokens)
+
+	testTable := []struct {
+		name                 string
+		tokenId              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "OK",
+			tokenId: "4",
+			mockBehavior: func(s *mock_service.MockAccessTokens) {
+				s.EXPECT().Revoke(1, 4).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Token Id",
+			tokenId:              "a",
+			mockBehavior:         func(s *mock_service.MockAccessTokens) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid token id param"}`,
+		},
+		{
+			name:    "Not Found",
+			tokenId: "4",
+			mockBehavior: func(s *mock_service.MockAccessTokens) {
+				s.EXPECT().Revoke(1, 4).Return(errors.New("access token not found"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"access token not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mock_service.NewMockAccessTokens(c)
+			testCase.mockBehavior(tokens)
+
+			services := &service.Service{AccessTokens: tokens}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.DELETE("/me/tokens/:id", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.deleteAccessToken)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/me/tokens/"+testCase.tokenId, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
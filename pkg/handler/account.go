@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// @Summary Get Profile
+// @Security ApiKeyAuth
+// @Tags account
+// @Description get profile of the current user
+// @ID get-profile
+// @Produce  json
+// @Success 200 {object} todo.Profile
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me [get]
+func (h *Handler) getProfile(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	profile, err := h.services.Account.Get(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// @Summary Update Profile
+// @Security ApiKeyAuth
+// @Tags account
+// @Description change name, username or email; a new email has to be verified again
+// @ID update-profile
+// @Accept  json
+// @Produce  json
+// @Param input body todo.UpdateUserInput true "New profile fields"
+// @Success 200 {object} todo.Profile
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me [patch]
+func (h *Handler) updateProfile(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.UpdateUserInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	profile, err := h.services.Account.Update(userId, input)
+	if errors.Is(err, service.ErrUsernameTaken) || errors.Is(err, service.ErrEmailTaken) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if input.Email != nil && profile.Email != "" && !profile.EmailVerified { // Новый адрес нужно подтвердить
+		if err := h.services.AccountEmail.SendVerification(userId); err != nil {
+			logrus.Errorf("failed to send verification email to user %d: %s", userId, err.Error())
+		}
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// @Summary Change Password
+// @Security ApiKeyAuth
+// @Tags account
+// @Description change password; all other sessions of the user are logged out and personal access tokens are revoked
+// @ID change-password
+// @Accept  json
+// @Produce  json
+// @Param input body todo.ChangePasswordInput true "Current and new password"
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/password [post]
+func (h *Handler) changePassword(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sessionId, err := getSessionId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.ChangePasswordInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.Account.ChangePassword(userId, sessionId, input)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		newErrorResponse(c, http.StatusBadRequest, "invalid current password")
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Delete Account
+// @Security ApiKeyAuth
+// @Tags account
+// @Description delete the current user; shared lists stay with the other members, the rest of the user's lists are deleted
+// @ID delete-account
+// @Accept  json
+// @Produce  json
+// @Param input body todo.DeleteUserInput true "Password (not required for users without a password)"
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me [delete]
+func (h *Handler) deleteAccount(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.DeleteUserInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ids, err := h.services.Account.Delete(userId, input.Password)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		newErrorResponse(c, http.StatusBadRequest, "invalid password")
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Удаляем все данные из кэша Redis пользователя и участников его общих списков (ключи user:userId)
+	for _, id := range ids {
+		if err := h.services.TodoListCach.Delete(id); err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,338 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getProfile(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccount)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAccount) {
+				s.EXPECT().Get(1).Return(todo.Profile{Id: 1, Name: "Alex", Username: "alex", Email: "alex@example.com", EmailVerified: true}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"name":"Alex","username":"alex","email":"alex@example.com","email_verified":true}`,
+		},
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockAccount) {
+				s.EXPECT().Get(1).Return(todo.Profile{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mock_service.NewMockAccount(c)
+			testCase.mockBehavior(account)
+
+			services := &service.Service{Account: account}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/me", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.getProfile)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/me", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_updateProfile(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail)
+
+	name := "Alexey"
+	email := "new@example.com"
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"name":"Alexey"}`,
+			mockBehavior: func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail) {
+				s.EXPECT().Update(1, todo.UpdateUserInput{Name: &name}).
+					Return(todo.Profile{Id: 1, Name: "Alexey", Username: "alex", Email: "alex@example.com", EmailVerified: true}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"name":"Alexey","username":"alex","email":"alex@example.com","email_verified":true}`,
+		},
+		{
+			name:      "New Email",
+			inputBody: `{"email":"new@example.com"}`,
+			mockBehavior: func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail) {
+				s.EXPECT().Update(1, todo.UpdateUserInput{Email: &email}).
+					Return(todo.Profile{Id: 1, Name: "Alex", Username: "alex", Email: "new@example.com"}, nil)
+				e.EXPECT().SendVerification(1).Return(errors.New("smtp is down"))
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":1,"name":"Alex","username":"alex","email":"new@example.com","email_verified":false}`,
+		},
+		{
+			name:                 "Invalid Email",
+			inputBody:            `{"email":"alex"}`,
+			mockBehavior:         func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'UpdateUserInput.Email' Error:Field validation for 'Email' failed on the 'email' tag"}`,
+		},
+		{
+			name:                 "Empty Input",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"update structure has no values"}`,
+		},
+		{
+			name:      "Username Taken",
+			inputBody: `{"username":"bob"}`,
+			mockBehavior: func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail) {
+				s.EXPECT().Update(1, gomock.Any()).Return(todo.Profile{}, service.ErrUsernameTaken)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"username is already taken"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"name":"Alexey"}`,
+			mockBehavior: func(s *mock_service.MockAccount, e *mock_service.MockAccountEmail) {
+				s.EXPECT().Update(1, gomock.Any()).Return(todo.Profile{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mock_service.NewMockAccount(c)
+			accountEmail := mock_service.NewMockAccountEmail(c)
+			testCase.mockBehavior(account, accountEmail)
+
+			services := &service.Service{Account: account, AccountEmail: accountEmail}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.PATCH("/me", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.updateProfile)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PATCH", "/me", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_changePassword(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccount)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"current_password":"qwerty","new_password":"secret"}`,
+			mockBehavior: func(s *mock_service.MockAccount) {
+				s.EXPECT().ChangePassword(1, 5, todo.ChangePasswordInput{CurrentPassword: "qwerty", NewPassword: "secret"}).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Missing New Password",
+			inputBody:            `{"current_password":"qwerty"}`,
+			mockBehavior:         func(s *mock_service.MockAccount) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'ChangePasswordInput.NewPassword' Error:Field validation for 'NewPassword' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Wrong Current Password",
+			inputBody: `{"current_password":"wrong","new_password":"secret"}`,
+			mockBehavior: func(s *mock_service.MockAccount) {
+				s.EXPECT().ChangePassword(1, 5, gomock.Any()).Return(service.ErrInvalidCredentials)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid current password"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"current_password":"qwerty","new_password":"secret"}`,
+			mockBehavior: func(s *mock_service.MockAccount) {
+				s.EXPECT().ChangePassword(1, 5, gomock.Any()).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mock_service.NewMockAccount(c)
+			testCase.mockBehavior(account)
+
+			services := &service.Service{Account: account}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/me/password", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+				c.Set(sessionCtx, 5)
+			}, handler.changePassword)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/me/password", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteAccount(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccount, cach *mock_service.MockTodoListCach)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"password":"qwerty"}`,
+			mockBehavior: func(s *mock_service.MockAccount, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Delete(1, "qwerty").Return([]int{1, 2}, nil)
+				cach.EXPECT().Delete(1).Return(nil)
+				cach.EXPECT().Delete(2).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Wrong Password",
+			inputBody: `{"password":"wrong"}`,
+			mockBehavior: func(s *mock_service.MockAccount, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Delete(1, "wrong").Return(nil, service.ErrInvalidCredentials)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid password"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"password":"qwerty"}`,
+			mockBehavior: func(s *mock_service.MockAccount, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Delete(1, "qwerty").Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+		{
+			name:      "Cach Failure",
+			inputBody: `{"password":"qwerty"}`,
+			mockBehavior: func(s *mock_service.MockAccount, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Delete(1, "qwerty").Return([]int{1}, nil)
+				cach.EXPECT().Delete(1).Return(errors.New("redis is down"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"redis is down"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			account := mock_service.NewMockAccount(c)
+			cach := mock_service.NewMockTodoListCach(c)
+			testCase.mockBehavior(account, cach)
+
+			services := &service.Service{Account: account, TodoListCach: cach}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.DELETE("/me", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.deleteAccount)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/me", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getAdminUsersResponse struct {
+	Data []todo.AdminUser `json:"data"`
+}
+
+type adminResetPasswordResponse struct {
+	EmailSent bool `json:"email_sent"` // false - письмо не отправлено
+}
+
+// @Summary Get Users
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description search users by username, name or email with owned lists and items counts
+// @ID admin-get-users
+// @Produce  json
+// @Param q query string false "search string"
+// @Param limit query int false "page size (default 50, max 200)"
+// @Param offset query int false "offset"
+// @Success 200 {object} getAdminUsersResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users [get]
+func (h *Handler) adminGetUsers(c *gin.Context) {
+	var query todo.AdminUsersQuery
+	if err := c.BindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid query params: %s", err.Error()))
+		return
+	}
+
+	users, err := h.services.Admin.GetUsers(query)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAdminUsersResponse{
+		Data: users,
+	})
+}
+
+// @Summary Get User
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description get user with owned lists and items counts
+// @ID admin-get-user
+// @Produce  json
+// @Param id path int true "User Id"
+// @Success 200 {object} todo.AdminUser
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users/{id} [get]
+func (h *Handler) adminGetUser(c *gin.Context) {
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid user id param")
+		return
+	}
+
+	user, err := h.services.Admin.GetUser(userId)
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Disable User
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description disable account: sessions are revoked, sign-in and requests with existing tokens are rejected
+// @ID admin-disable-user
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User Id"
+// @Param input body todo.AdminActionInput false "reason for the audit log"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users/{id}/disable [post]
+func (h *Handler) adminDisableUser(c *gin.Context) {
+	adminId, userId, input, ok := adminActionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Admin.Disable(adminId, userId, input, c.ClientIP()); err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Enable User
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description enable previously disabled account
+// @ID admin-enable-user
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User Id"
+// @Param input body todo.AdminActionInput false "reason for the audit log"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users/{id}/enable [post]
+func (h *Handler) adminEnableUser(c *gin.Context) {
+	adminId, userId, input, ok := adminActionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Admin.Enable(adminId, userId, input, c.ClientIP()); err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Reset User Password
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description invalidate the password and revoke sessions; a reset link is sent to the user's email. Users without a verified email are rejected with 400
+// @ID admin-reset-password
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User Id"
+// @Param input body todo.AdminActionInput false "reason for the audit log"
+// @Success 200 {object} adminResetPasswordResponse
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users/{id}/reset-password [post]
+func (h *Handler) adminResetPassword(c *gin.Context) {
+	adminId, userId, input, ok := adminActionParams(c)
+	if !ok {
+		return
+	}
+
+	sent, err := h.services.Admin.ResetPassword(adminId, userId, input, c.ClientIP())
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adminResetPasswordResponse{
+		EmailSent: sent,
+	})
+}
+
+// @Summary Impersonate User
+// @Security ApiKeyAuth
+// @Tags admin
+// @Description sign in as the user for support; the session is recorded in the audit log and shown in the user's devices
+// @ID admin-impersonate
+// @Accept  json
+// @Produce  json
+// @Param id path int true "User Id"
+// @Param input body todo.ImpersonateInput true "reason for the audit log"
+// @Success 200 {object} todo.Tokens
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /admin/users/{id}/impersonate [post]
+func (h *Handler) adminImpersonate(c *gin.Context) {
+	adminId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid user id param")
+		return
+	}
+
+	var input todo.ImpersonateInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.services.Admin.Impersonate(adminId, userId, input, todo.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
+	})
+	if err != nil {
+		adminErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Id администратора, id пользователя из пути и необязательная причина действия из тела запроса.
+// При ошибке ответ уже отправлен
+func adminActionParams(c *gin.Context) (int, int, todo.AdminActionInput, bool) {
+	var input todo.AdminActionInput
+
+	adminId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return 0, 0, input, false
+	}
+
+	userId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid user id param")
+		return 0, 0, input, false
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&input); err != nil {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return 0, 0, input, false
+		}
+	}
+
+	return adminId, userId, input, true
+}
+
+func adminErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		newErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAdminSelf), errors.Is(err, service.ErrImpersonateAdmin),
+		errors.Is(err, service.ErrAccountDisabled), errors.Is(err, service.ErrNoVerifiedEmail):
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_adminGetUsers(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAdmin)
+
+	disabled := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?q=alex&limit=10&offset=20",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().GetUsers(todo.AdminUsersQuery{Query: "alex", Limit: 10, Offset: 20}).Return([]todo.AdminUser{
+					{Id: 7, Name: "Alex", Username: "alex", DisabledAt: &disabled, Lists: 2, Items: 5},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"data":[{"id":7,"name":"Alex","username":"alex","email_verified":false,"is_admin":false,` +
+				`"disabled_at":"2022-06-11T09:00:00Z","lists":2,"items":5}]}`,
+		},
+		{
+			name:                 "Invalid Limit",
+			query:                "?limit=-1",
+			mockBehavior:         func(s *mock_service.MockAdmin) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params: Key: 'AdminUsersQuery.Limit' Error:Field validation for 'Limit' failed on the 'min' tag"}`,
+		},
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().GetUsers(todo.AdminUsersQuery{}).Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			admin := mock_service.NewMockAdmin(c)
+			testCase.mockBehavior(admin)
+
+			services := &service.Service{Admin: admin}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/admin/users", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.adminGetUsers)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin/users"+testCase.query, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_adminDisableUser(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAdmin)
+
+	testTable := []struct {
+		name                 string
+		userId               string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			userId:    "7",
+			inputBody: `{"reason":"spam"}`,
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().Disable(1, 7, todo.AdminActionInput{Reason: "spam"}, "192.0.2.1").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:   "Without Reason",
+			userId: "7",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().Disable(1, 7, todo.AdminActionInput{}, "192.0.2.1").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Id",
+			userId:               "abc",
+			mockBehavior:         func(s *mock_service.MockAdmin) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid user id param"}`,
+		},
+		{
+			name:   "Self",
+			userId: "1",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().Disable(1, 1, todo.AdminActionInput{}, "192.0.2.1").Return(service.ErrAdminSelf)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"administrator cannot apply this action to own account"}`,
+		},
+		{
+			name:   "Not Found",
+			userId: "7",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().Disable(1, 7, todo.AdminActionInput{}, "192.0.2.1").Return(service.ErrUserNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"user not found"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			admin := mock_service.NewMockAdmin(c)
+			testCase.mockBehavior(admin)
+
+			services := &service.Service{Admin: admin}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/admin/users/:id/disable", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.adminDisableUser)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/admin/users/"+testCase.userId+"/disable", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_adminResetPassword(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAdmin)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().ResetPassword(1, 7, todo.AdminActionInput{}, "192.0.2.1").Return(true, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"email_sent":true}`,
+		},
+		{
+			name: "No Verified Email",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().ResetPassword(1, 7, todo.AdminActionInput{}, "192.0.2.1").Return(false, service.ErrNoVerifiedEmail)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"user has no verified email"}`,
+		},
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().ResetPassword(1, 7, todo.AdminActionInput{}, "192.0.2.1").Return(false, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			admin := mock_service.NewMockAdmin(c)
+			testCase.mockBehavior(admin)
+
+			services := &service.Service{Admin: admin}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/admin/users/:id/reset-password", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.adminResetPassword)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/admin/users/7/reset-password", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_adminImpersonate(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAdmin)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"reason":"ticket 42"}`,
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().Impersonate(1, 7, todo.ImpersonateInput{Reason: "ticket 42"}, todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"access","refresh_token":"refresh","expires_in":900}`,
+		},
+		{
+			name:                 "Empty Reason",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *mock_service.MockAdmin) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'ImpersonateInput.Reason' Error:Field validation for 'Reason' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Admin Target",
+			inputBody: `{"reason":"ticket 42"}`,
+			mockBehavior: func(s *mock_service.MockAdmin) {
+				s.EXPECT().Impersonate(1, 7, todo.ImpersonateInput{Reason: "ticket 42"}, todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, service.ErrImpersonateAdmin)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"cannot impersonate an administrator"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			admin := mock_service.NewMockAdmin(c)
+			testCase.mockBehavior(admin)
+
+			services := &service.Service{Admin: admin}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/admin/users/:id/impersonate", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.adminImpersonate)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/admin/users/7/impersonate", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
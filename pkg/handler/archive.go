@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Максимальный размер импортируемого архива
+const maxArchiveSize = 32 << 20
+
+// @Summary Export Data
+// @Security ApiKeyAuth
+// @Tags account
+// @Description download profile, tags and owned lists with items and reminders as a versioned JSON archive
+// @ID export-data
+// @Produce  json
+// @Success 200 {object} todo.Archive
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/export [get]
+func (h *Handler) exportData(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	archive, err := h.services.Archive.Export(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="todo-export-%s.json"`, archive.ExportedAt.Format("2006-01-02")))
+	c.JSON(http.StatusOK, archive)
+}
+
+// @Summary Import Data
+// @Security ApiKeyAuth
+// @Tags account
+// @Description import an archive from GET /api/me/export; lists are added to the personal workspace, tags with the same name are merged
+// @ID import-data
+// @Accept  json
+// @Produce  json
+// @Param input body todo.Archive true "Archive"
+// @Success 200 {object} todo.ImportResult
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/import [post]
+func (h *Handler) importData(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveSize)
+
+	var archive todo.Archive
+	if err := c.BindJSON(&archive); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.services.Archive.Import(userId, archive)
+	if errors.Is(err, service.ErrInvalidArchive) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Новые списки появились у пользователя, удаляем из кэша Redis его lists
+	if err := h.services.TodoListCach.HDelete(userId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_exportData(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockArchive)
+
+	exported := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+	created := time.Date(2022, 6, 10, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedDisposition  string
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockArchive) {
+				s.EXPECT().Export(1).Return(todo.Archive{
+					Version:    todo.ArchiveVersion,
+					ExportedAt: exported,
+					Profile:    todo.ArchiveProfile{Name: "Alex", Username: "alex"},
+					Tags:       []todo.ArchiveTag{{Id: 3, Name: "work"}},
+					Lists: []todo.ArchiveList{{Id: 10, Title: "Home", Items: []todo.ArchiveItem{
+						{Id: 20, ListId: 10, Title: "Buy milk", Priority: todo.PriorityHigh, Occurrence: 1, CreatedAt: created, TagIds: []int{3}},
+					}}},
+				}, nil)
+			},
+			expectedStatusCode:  200,
+			expectedDisposition: `attachment; filename="todo-export-2022-06-11.json"`,
+			expectedResponseBody: fmt.Sprintf(`{"version":%d,"exported_at":"2022-06-11T09:00:00Z","profile":{"name":"Alex","username":"alex"},`+
+				`"tags":[{"id":3,"name":"work"}],"lists":[{"id":10,"title":"Home","description":"","items":[`+
+				`{"id":20,"title":"Buy milk","description":"","done":false,"priority":"high","occurrence":1,"created_at":"2022-06-10T09:00:00Z","tag_ids":[3]}]}]}`,
+				todo.ArchiveVersion),
+		},
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockArchive) {
+				s.EXPECT().Export(1).Return(todo.Archive{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			archive := mock_service.NewMockArchive(c)
+			testCase.mockBehavior(archive)
+
+			services := &service.Service{Archive: archive}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/me/export", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.exportData)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/me/export", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedDisposition, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_importData(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockArchive, cach *mock_service.MockTodoListCach)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"version":1,"tags":[],"lists":[{"id":10,"title":"Home","items":[{"id":20,"title":"Buy milk","priority":"high"}]}]}`,
+			mockBehavior: func(s *mock_service.MockArchive, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Import(1, todo.Archive{
+					Version: 1,
+					Tags:    []todo.ArchiveTag{},
+					Lists:   []todo.ArchiveList{{Id: 10, Title: "Home", Items: []todo.ArchiveItem{{Id: 20, Title: "Buy milk", Priority: todo.PriorityHigh}}}},
+				}).Return(todo.ImportResult{Lists: 1, Items: 1}, nil)
+				cach.EXPECT().HDelete(1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"lists":1,"items":1,"tags":0}`,
+		},
+		{
+			name:                 "Invalid JSON",
+			inputBody:            `{"version":1,"lists":[{"items":[{"priority":"later"}]}]}`,
+			mockBehavior:         func(s *mock_service.MockArchive, cach *mock_service.MockTodoListCach) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid priority: \"later\" (allowed: none, low, medium, high, urgent)"}`,
+		},
+		{
+			name:      "Invalid Archive",
+			inputBody: `{"version":2}`,
+			mockBehavior: func(s *mock_service.MockArchive, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Import(1, todo.Archive{Version: 2}).
+					Return(todo.ImportResult{}, fmt.Errorf("%w: unsupported archive version: 2 (supported: 1)", service.ErrInvalidArchive))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid archive: unsupported archive version: 2 (supported: 1)"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"version":1}`,
+			mockBehavior: func(s *mock_service.MockArchive, cach *mock_service.MockTodoListCach) {
+				s.EXPECT().Import(1, gomock.Any()).Return(todo.ImportResult{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			archive := mock_service.NewMockArchive(c)
+			cach := mock_service.NewMockTodoListCach(c)
+			testCase.mockBehavior(archive, cach)
+
+			services := &service.Service{Archive: archive, TodoListCach: cach}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/me/import", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.importData)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/me/import", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -1,16 +1,21 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 	"todo-app"
+	"todo-app/pkg/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 // @Summary SighUp
 // @Tags auth
-// @Description create account
+// @Description create account, a verification link is sent if email is given
 // @ID create-account
 // @Accept json
 // @Produce json
@@ -29,30 +34,50 @@ func (h *Handler) signUp(c *gin.Context) { // Обработчик для рег
 	}
 
 	id, err := h.services.Authorization.CreateUser(input)
+	if errors.Is(err, service.ErrEmailTaken) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if input.Email != "" { // Аккаунт уже создан, письмо можно запросить повторно
+		if err := h.services.AccountEmail.SendVerification(id); err != nil {
+			logrus.Errorf("failed to send verification email to user %d: %s", id, err.Error())
+		}
+	}
+
 	c.JSON(http.StatusOK, map[string]interface{}{
 		"id": id,
 	})
 }
 
+// Ответ на вход при включенной 2FA: токен второго шага обменивается на пару токенов в /auth/2fa/verify
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+}
+
 type signInInput struct { // Структура для идентификации
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// Имя устройства для списка сессий (optional), например "Pixel 6"
+	DeviceName string `json:"device_name"`
 }
 
 // @Summary SignIn
 // @Tags auth
-// @Description login
+// @Description login; after repeated failures the next attempt is delayed (429) or the account is locked (423), see Retry-After
 // @ID login
 // @Accept json
 // @Produce json
 // @Param input body signInInput true "credentials"
-// @Success 200 {string} string "token"
-// @Failure 400,404 {object} errorResponse
+// @Success 200 {object} todo.Tokens "tokens, or twoFactorChallengeResponse when two-factor authentication is enabled"
+// @Failure 400,401,403,404 {object} errorResponse
+// @Failure 423,429 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
 // @Router /auth/sign-in [post]
@@ -64,13 +89,198 @@ func (h *Handler) signIn(c *gin.Context) { // Обработчик для аут
 		return
 	}
 
-	token, err := h.services.Authorization.GenerateToken(input.Username, input.Password)
+	tokens, err := h.services.Authorization.GenerateToken(input.Username, input.Password, todo.SessionClient{
+		DeviceName: input.DeviceName,
+		UserAgent:  c.Request.UserAgent(),
+		Ip:         c.ClientIP(),
+	})
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrAccountDisabled) {
+		newErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+	if loginBlockedResponse(c, err) {
+		return
+	}
+	var challenge *service.TwoFactorRequiredError
+	if errors.As(err, &challenge) {
+		c.JSON(http.StatusOK, twoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge.ChallengeToken,
+			ExpiresIn:         challenge.ExpiresIn,
+		})
+		return
+	}
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"token": token,
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Ответ на *service.LoginBlockedError: 423 при блокировке учетной записи, иначе 429, в обоих случаях с Retry-After
+func loginBlockedResponse(c *gin.Context, err error) bool {
+	var blocked *service.LoginBlockedError
+	if !errors.As(err, &blocked) {
+		return false
+	}
+
+	// Retry-After в целых секундах, округление вверх
+	c.Header("Retry-After", strconv.Itoa(int((blocked.RetryAfter+time.Second-1)/time.Second)))
+	if blocked.Locked {
+		newErrorResponse(c, http.StatusLocked, err.Error())
+	} else {
+		newErrorResponse(c, http.StatusTooManyRequests, err.Error())
+	}
+	return true
+}
+
+// @Summary Verify Two-Factor
+// @Tags auth
+// @Description second sign-in step: exchange challenge token and TOTP or recovery code for tokens
+// @Description challenge token is single-use and accepts at most 5 codes
+// @Description wrong codes count as failed logins: the next attempt may be delayed (429) or the account locked (423)
+// @ID verify-two-factor
+// @Accept json
+// @Produce json
+// @Param input body todo.TwoFactorVerifyInput true "challenge token and code"
+// @Success 200 {object} todo.Tokens
+// @Failure 400,401 {object} errorResponse
+// @Failure 423,429 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/2fa/verify [post]
+func (h *Handler) verifyTwoFactor(c *gin.Context) {
+	var input todo.TwoFactorVerifyInput
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid input body: %s", err.Error()))
+		return
+	}
+
+	tokens, err := h.services.Authorization.VerifyTwoFactor(input.ChallengeToken, input.Code, todo.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
 	})
+	if errors.Is(err, service.ErrInvalidChallenge) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if loginBlockedResponse(c, err) {
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Refresh
+// @Tags auth
+// @Description exchange refresh token for a new token pair (each refresh token is single-use)
+// @ID refresh-token
+// @Accept json
+// @Produce json
+// @Param input body todo.RefreshTokenInput true "refresh token"
+// @Success 200 {object} todo.Tokens
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/refresh [post]
+func (h *Handler) refresh(c *gin.Context) {
+	var input todo.RefreshTokenInput
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid input body: %s", err.Error()))
+		return
+	}
+
+	tokens, err := h.services.Authorization.RefreshToken(input.RefreshToken, c.ClientIP())
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Logout
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description revoke current session
+// @ID logout
+// @Produce json
+// @Success 200 {object} statusResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/logout [post]
+func (h *Handler) logout(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sessionId, err := getSessionId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := h.services.Authorization.Logout(userId, sessionId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Logout All
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description revoke all sessions of the user (log out all devices)
+// @ID logout-all
+// @Produce json
+// @Success 200 {object} statusResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/logout-all [post]
+func (h *Handler) logoutAll(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := h.services.Authorization.LogoutAll(userId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary JWKS
+// @Tags auth
+// @Description public keys for verifying access tokens (RS256 and EdDSA keys only)
+// @ID jwks
+// @Produce json
+// @Success 200 {object} jwtkeys.JWKS
+// @Failure default {object} errorResponse
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) getJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.services.Authorization.JWKS())
 }
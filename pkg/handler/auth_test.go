@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"todo-app"
+	"todo-app/pkg/jwtkeys"
 	"todo-app/pkg/service"
 	mock_service "todo-app/pkg/service/mocks"
 
@@ -47,6 +49,21 @@ func TestHandler_signUp(t *testing.T) { //Тест функция метода h
 			expectedStatusCode:   400,
 			expectedResponseBody: `{"message":"invalid input body"}`,
 		},
+		{
+			name:      "Email Taken",
+			inputBody: `{"name":"Test", "username":"test","password":"qwerty","email":"taken@example.com"}`,
+			inputUser: todo.User{
+				Name:     "Test",
+				Username: "test",
+				Password: "qwerty",
+				Email:    "taken@example.com",
+			},
+			mockBehavior: func(s *mock_service.MockAuthorization, user todo.User) {
+				s.EXPECT().CreateUser(user).Return(0, service.ErrEmailTaken)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"email is already taken"}`,
+		},
 		{
 			name:      "Service Failure",
 			inputBody: `{"name":"Test", "username":"test","password":"qwerty"}`,
@@ -108,18 +125,20 @@ func TestHandler_signIn(t *testing.T) { //Тест функция метода h
 
 		expectedStatusCode   int    // статус код ответа
 		expectedResponseBody string // ожидаемое тело ответа.
+		expectedRetryAfter   string // заголовок Retry-After
 	}{
 		{ // позитивный сценарий
 			name:      "OK",
-			inputBody: `{"username":"test","password":"qwerty"}`,
+			inputBody: `{"username":"test","password":"qwerty","device_name":"Pixel 6"}`,
 			username:  "test",
 			password:  "qwerty",
 			token:     "token",
 			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
-				s.EXPECT().GenerateToken(username, password).Return("token", nil)
+				// httptest.NewRequest задает адрес клиента 192.0.2.1
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{DeviceName: "Pixel 6", Ip: "192.0.2.1"}).Return(todo.Tokens{AccessToken: "token", RefreshToken: "refresh", ExpiresIn: 900}, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: `{"token":"token"}`,
+			expectedResponseBody: `{"token":"token","refresh_token":"refresh","expires_in":900}`,
 		},
 		{
 			name:      "Empty Field password",
@@ -150,11 +169,71 @@ func TestHandler_signIn(t *testing.T) { //Тест функция метода h
 			username:  "test",
 			password:  "qwerty",
 			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
-				s.EXPECT().GenerateToken(username, password).Return("", errors.New("token invalid"))
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{Ip: "192.0.2.1"}).Return(todo.Tokens{}, errors.New("token invalid"))
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"message":"token invalid"}`,
 		},
+		{
+			name:      "Two-Factor Required",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			username:  "test",
+			password:  "qwerty",
+			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, &service.TwoFactorRequiredError{ChallengeToken: "challenge", ExpiresIn: 300})
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"two_factor_required":true,"challenge_token":"challenge","expires_in":300}`,
+		},
+		{
+			name:      "Invalid Credentials",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			username:  "test",
+			password:  "qwerty",
+			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{Ip: "192.0.2.1"}).Return(todo.Tokens{}, service.ErrInvalidCredentials)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid username or password"}`,
+		},
+		{
+			name:      "Disabled Account",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			username:  "test",
+			password:  "qwerty",
+			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{Ip: "192.0.2.1"}).Return(todo.Tokens{}, service.ErrAccountDisabled)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"account is disabled"}`,
+		},
+		{
+			name:      "Too Many Attempts",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			username:  "test",
+			password:  "qwerty",
+			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, &service.LoginBlockedError{RetryAfter: 1500 * time.Millisecond})
+			},
+			expectedStatusCode:   429,
+			expectedResponseBody: `{"message":"too many login attempts, try again later"}`,
+			expectedRetryAfter:   "2",
+		},
+		{
+			name:      "Account Locked",
+			inputBody: `{"username":"test","password":"qwerty"}`,
+			username:  "test",
+			password:  "qwerty",
+			mockBehavior: func(s *mock_service.MockAuthorization, username string, password string) {
+				s.EXPECT().GenerateToken(username, password, todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, &service.LoginBlockedError{RetryAfter: 15 * time.Minute, Locked: true})
+			},
+			expectedStatusCode:   423,
+			expectedResponseBody: `{"message":"account temporarily locked due to too many failed login attempts"}`,
+			expectedRetryAfter:   "900",
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -184,6 +263,281 @@ func TestHandler_signIn(t *testing.T) { //Тест функция метода h
 			// Assert
 			assert.Equal(t, w.Code, testCase.expectedStatusCode)
 			assert.Equal(t, w.Body.String(), testCase.expectedResponseBody)
+			assert.Equal(t, testCase.expectedRetryAfter, w.Header().Get("Retry-After"))
 		})
 	}
 }
+
+func TestHandler_refresh(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization, token string)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		token                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"refresh_token":"refresh"}`,
+			token:     "refresh",
+			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
+				s.EXPECT().RefreshToken(token, "192.0.2.1").Return(todo.Tokens{AccessToken: "token", RefreshToken: "refresh2", ExpiresIn: 900}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token","refresh_token":"refresh2","expires_in":900}`,
+		},
+		{
+			name:                 "Empty Token",
+			inputBody:            `{}`,
+			mockBehavior:         func(s *mock_service.MockAuthorization, token string) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body: Key: 'RefreshTokenInput.RefreshToken' Error:Field validation for 'RefreshToken' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Invalid Token",
+			inputBody: `{"refresh_token":"refresh"}`,
+			token:     "refresh",
+			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
+				s.EXPECT().RefreshToken(token, "192.0.2.1").Return(todo.Tokens{}, service.ErrInvalidRefreshToken)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid refresh token"}`,
+		},
+		{
+			name:      "Service Failure",
+			inputBody: `{"refresh_token":"refresh"}`,
+			token:     "refresh",
+			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
+				s.EXPECT().RefreshToken(token, "192.0.2.1").Return(todo.Tokens{}, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth, testCase.token)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/refresh", handler.refresh)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/refresh", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_logout(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		path                 string
+		mockBehavior         func(s *mock_service.MockAuthorization)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Logout",
+			path: "/logout",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().ParseToken("token").Return(1, 5, nil)
+				s.EXPECT().CheckUser(1).Return(false, nil)
+				s.EXPECT().Logout(1, 5).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Logout All",
+			path: "/logout-all",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().ParseToken("token").Return(1, 5, nil)
+				s.EXPECT().CheckUser(1).Return(false, nil)
+				s.EXPECT().LogoutAll(1).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Service Failure",
+			path: "/logout",
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().ParseToken("token").Return(1, 5, nil)
+				s.EXPECT().CheckUser(1).Return(false, nil)
+				s.EXPECT().Logout(1, 5).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/logout", handler.userIdentity, handler.logout)
+			r.POST("/logout-all", handler.userIdentity, handler.logoutAll)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", testCase.path, nil)
+			req.Header.Set("Authorization", "Bearer token")
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_verifyTwoFactor(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAuthorization)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"challenge_token":"challenge","code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().VerifyTwoFactor("challenge", "123456", todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{AccessToken: "token", RefreshToken: "refresh", ExpiresIn: 900}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token","refresh_token":"refresh","expires_in":900}`,
+		},
+		{
+			name:                 "Empty Code",
+			inputBody:            `{"challenge_token":"challenge"}`,
+			mockBehavior:         func(s *mock_service.MockAuthorization) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body: Key: 'TwoFactorVerifyInput.Code' Error:Field validation for 'Code' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Invalid Code",
+			inputBody: `{"challenge_token":"challenge","code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().VerifyTwoFactor("challenge", "123456", todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid two-factor code"}`,
+		},
+		{
+			name:      "Expired Challenge",
+			inputBody: `{"challenge_token":"challenge","code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().VerifyTwoFactor("challenge", "123456", todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, service.ErrInvalidChallenge)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid or expired two-factor challenge"}`,
+		},
+		{
+			name:      "Locked",
+			inputBody: `{"challenge_token":"challenge","code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockAuthorization) {
+				s.EXPECT().VerifyTwoFactor("challenge", "123456", todo.SessionClient{Ip: "192.0.2.1"}).
+					Return(todo.Tokens{}, &service.LoginBlockedError{RetryAfter: time.Minute, Locked: true})
+			},
+			expectedStatusCode:   423,
+			expectedResponseBody: `{"message":"account temporarily locked due to too many failed login attempts"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(auth)
+
+			services := &service.Service{Authorization: auth}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/2fa/verify", handler.verifyTwoFactor)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/2fa/verify", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getJWKS(t *testing.T) {
+	// Init Deps
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockAuthorization(c)
+	auth.EXPECT().JWKS().Return(jwtkeys.JWKS{Keys: []jwtkeys.JWK{
+		{Kty: "OKP", Kid: "ed-1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	}})
+
+	services := &service.Service{Authorization: auth}
+	handler := NewHandler(services)
+
+	// Test Server
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", handler.getJWKS)
+
+	// Test Request
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+	// Perform Request
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, `{"keys":[{"kty":"OKP","kid":"ed-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, w.Body.String())
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Verify Email
+// @Tags auth
+// @Description confirm email address with the token from the verification email
+// @ID verify-email
+// @Produce json
+// @Param token query string true "token from the email"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/verify [get]
+func (h *Handler) verifyEmail(c *gin.Context) {
+	var input todo.VerifyEmailInput
+
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid token param: %s", err.Error()))
+		return
+	}
+
+	err := h.services.AccountEmail.Verify(input.Token)
+	if errors.Is(err, service.ErrInvalidEmailToken) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Request Verification Email
+// @Security ApiKeyAuth
+// @Tags auth
+// @Description send a new verification link to the user's email
+// @ID resend-verification
+// @Produce json
+// @Success 200 {object} statusResponse
+// @Failure 400,401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/verify [post]
+func (h *Handler) resendVerification(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = h.services.AccountEmail.SendVerification(userId)
+	if errors.Is(err, service.ErrEmailNotSet) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Forgot Password
+// @Tags auth
+// @Description send a password reset link to a verified email; the response is the same whether the email is registered or not
+// @ID forgot-password
+// @Accept json
+// @Produce json
+// @Param input body todo.ForgotPasswordInput true "email"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/forgot-password [post]
+func (h *Handler) forgotPassword(c *gin.Context) {
+	var input todo.ForgotPasswordInput
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid input body: %s", err.Error()))
+		return
+	}
+
+	if err := h.services.AccountEmail.ForgotPassword(input.Email); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Reset Password
+// @Tags auth
+// @Description set a new password with the token from the reset email; all sessions of the user are revoked
+// @ID reset-password
+// @Accept json
+// @Produce json
+// @Param input body todo.ResetPasswordInput true "token and new password"
+// @Success 200 {object} statusResponse
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/reset-password [post]
+func (h *Handler) resetPassword(c *gin.Context) {
+	var input todo.ResetPasswordInput
+
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid input body: %s", err.Error()))
+		return
+	}
+
+	err := h.services.AccountEmail.ResetPassword(input.Token, input.Password)
+	if errors.Is(err, service.ErrInvalidEmailToken) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/mailer"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_accountEmail(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccountEmail)
+
+	testTable := []struct {
+		name                 string
+		method               string
+		path                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "Verify",
+			method: "GET",
+			path:   "/verify?token=abc",
+			mockBehavior: func(s *mock_service.MockAccountEmail) {
+				s.EXPECT().Verify("abc").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Verify No Token",
+			method:               "GET",
+			path:                 "/verify",
+			mockBehavior:         func(s *mock_service.MockAccountEmail) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid token param: Key: 'VerifyEmailInput.Token' Error:Field validation for 'Token' failed on the 'required' tag"}`,
+		},
+		{
+			name:   "Verify Invalid Token",
+			method: "GET",
+			path:   "/verify?token=abc",
+			mockBehavior: func(s *mock_service.MockAccountEmail) {
+				s.EXPECT().Verify("abc").Return(service.ErrInvalidEmailToken)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid or expired token"}`,
+		},
+		{
+			name:   "Resend Verification No Email",
+			method: "POST",
+			path:   "/verify",
+			mockBehavior: func(s *mock_service.MockAccountEmail) {
+				s.EXPECT().SendVerification(1).Return(service.ErrEmailNotSet)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"email is not set"}`,
+		},
+		{
+			name:      "Forgot Password",
+			method:    "POST",
+			path:      "/forgot-password",
+			inputBody: `{"email":"alex@example.com"}`,
+			mockBehavior: func(s *mock_service.MockAccountEmail) {
+				s.EXPECT().ForgotPassword("alex@example.com").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Forgot Password Invalid Email",
+			method:               "POST",
+			path:                 "/forgot-password",
+			inputBody:            `{"email":"alex"}`,
+			mockBehavior:         func(s *mock_service.MockAccountEmail) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid input body: Key: 'ForgotPasswordInput.Email' Error:Field validation for 'Email' failed on the 'email' tag"}`,
+		},
+		{
+			name:      "Reset Password",
+			method:    "POST",
+			path:      "/reset-password",
+			inputBody: `{"token":"abc","password":"new-password"}`,
+			mockBehavior: func(s *mock_service.MockAccountEmail) {
+				s.EXPECT().ResetPassword("abc", "new-password").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Reset Password Invalid Token",
+			method:    "POST",
+			path:      "/reset-password",
+			inputBody: `{"token":"abc","password":"new-password"}`,
+			mockBehavior: func(s *mock_service.MockAccountEmail) {
+				s.EXPECT().ResetPassword("abc", "new-password").Return(service.ErrInvalidEmailToken)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid or expired token"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			accountEmail := mock_service.NewMockAccountEmail(c)
+			testCase.mockBehavior(accountEmail)
+
+			services := &service.Service{AccountEmail: accountEmail}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/verify", handler.verifyEmail)
+			r.POST("/verify", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.resendVerification)
+			r.POST("/forgot-password", handler.forgotPassword)
+			r.POST("/reset-password", handler.resetPassword)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(testCase.method, testCase.path, bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+// Ошибка отправки письма не отменяет регистрацию
+func TestHandler_signUpWithEmail(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	auth := mock_service.NewMockAuthorization(c)
+	auth.EXPECT().CreateUser(todo.User{Name: "Test", Username: "test", Password: "qwerty", Email: "test@example.com"}).
+		Return(1, nil)
+	accountEmail := mock_service.NewMockAccountEmail(c)
+	accountEmail.EXPECT().SendVerification(1).Return(errors.New("smtp failure"))
+
+	handler := NewHandler(&service.Service{Authorization: auth, AccountEmail: accountEmail})
+
+	r := gin.New()
+	r.POST("/sign-up", handler.signUp)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/sign-up",
+		bytes.NewBufferString(`{"name":"Test","username":"test","password":"qwerty","email":"test@example.com"}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+}
+
+// Встроенные шаблоны писем разбираются и подставляют ссылку
+func TestMailTemplates(t *testing.T) {
+	fsys, err := MailTemplates()
+	if err != nil {
+		t.Fatalf("failed to open mail templates: %s", err.Error())
+	}
+	templates, err := mailer.NewTemplates(fsys)
+	if err != nil {
+		t.Fatalf("failed to parse mail templates: %s", err.Error())
+	}
+
+	for _, name := range []string{"verify", "reset"} {
+		t.Run(name, func(t *testing.T) {
+			msg, err := templates.Render(name, "alex@example.com", map[string]string{
+				"Name": "Alex", "Username": "alex", "Link": "http://todo.test/?token=a&b", "Token": "a&b", "ExpiresIn": "1 час",
+			})
+			assert.NoError(t, err)
+			assert.NotEmpty(t, msg.Subject)
+			assert.Contains(t, msg.Text, "http://todo.test/?token=a&b")
+			assert.True(t, strings.Contains(msg.HTML, "token=a&amp;b"), "link must be escaped in HTML")
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"html/template"
 	"io/fs"
 	"net/http"
+	"todo-app"
 	"todo-app/pkg/service"
 
 	_ "todo-app/docs"
@@ -21,6 +22,11 @@ type Handler struct {
 	services *service.Service
 }
 
+// Шаблоны писем (см. mailer.NewTemplates)
+func MailTemplates() (fs.FS, error) {
+	return fs.Sub(f, "web/templates/email")
+}
+
 func NewHandler(services *service.Service) *Handler {
 	return &Handler{
 		services: services,
@@ -46,34 +52,177 @@ func (h *Handler) InitRoutes() (*gin.Engine, error) { // Инициализац
 
 	mux.NoRoute(Response404) // При неверном URL вызывает ф-ю Response404
 
+	mux.GET("/.well-known/jwks.json", h.getJWKS) // Открытые ключи подписи токенов
+
+	// Области доступа маршрутов для персональных токенов (токен входа имеет все права)
+	var (
+		listsRead       = h.requireScope(todo.ScopeListsRead)
+		listsWrite      = h.requireScope(todo.ScopeListsWrite)
+		itemsRead       = h.requireScope(todo.ScopeItemsRead)
+		itemsWrite      = h.requireScope(todo.ScopeItemsWrite)
+		tagsRead        = h.requireScope(todo.ScopeTagsRead)
+		tagsWrite       = h.requireScope(todo.ScopeTagsWrite)
+		workspacesRead  = h.requireScope(todo.ScopeWorkspacesRead)
+		workspacesWrite = h.requireScope(todo.ScopeWorkspacesWrite)
+		account         = h.requireScope(todo.ScopeAccount)
+	)
+
 	auth := mux.Group("/auth") // Группа аутентификации
 	{
 		auth.POST("/sign-up", h.signUp)
 		auth.POST("/sign-in", h.signIn)
+		auth.POST("/2fa/verify", h.verifyTwoFactor)
+		auth.POST("/refresh", h.refresh)
+		auth.GET("/verify", h.verifyEmail)
+		auth.POST("/verify", h.userIdentity, account, h.resendVerification)
+		auth.POST("/forgot-password", h.forgotPassword)
+		auth.POST("/reset-password", h.resetPassword)
+
+		oidc := auth.Group("/oidc") // Вход через провайдеров OpenID Connect
+		{
+			oidc.GET("/providers", h.getOIDCProviders)
+			oidc.GET("/login", h.oidcLogin)
+			oidc.GET("/callback", h.oidcCallback)
+		}
+		auth.POST("/logout", h.userIdentity, account, h.logout)
+		auth.POST("/logout-all", h.userIdentity, account, h.logoutAll)
 	}
 
 	api := mux.Group("/api", h.userIdentity) //Группа для взаимодействия с List
 	{
 		lists := api.Group("/lists")
 		{
-			lists.POST("/", h.createList)
-			lists.GET("/", h.getAllLists)
-			lists.GET("/:id", h.getListById)
-			lists.PUT("/:id", h.updateList)
-			lists.DELETE("/:id", h.deleteList)
+			lists.POST("/", listsWrite, h.createList)
+			lists.GET("/", listsRead, h.getAllLists)
+			lists.GET("/:id", listsRead, h.getListById)
+			lists.PUT("/:id", listsWrite, h.updateList)
+			lists.DELETE("/:id", listsWrite, h.deleteList)
+			lists.POST("/:id/move", listsWrite, h.moveList)
 
 			items := lists.Group(":id/items")
 			{
-				items.POST("/", h.createItem)
-				items.GET("/", h.getAllItems)
+				items.POST("/", itemsWrite, h.createItem)
+				items.GET("/", itemsRead, h.getAllItems)
+				items.POST("/:itemId/move", itemsWrite, h.moveItem)
+			}
+
+			members := lists.Group(":id/members")
+			{
+				members.GET("/", listsRead, h.getListMembers)
+				members.POST("/", listsWrite, h.addListMember)
+				members.DELETE("/:username", listsWrite, h.removeListMember)
+			}
+
+			listInvitations := lists.Group(":id/invitations")
+			{
+				listInvitations.POST("/", listsWrite, h.createInvitation)
+				listInvitations.GET("/", listsRead, h.getListInvitations)
+				listInvitations.DELETE("/:invitationId", listsWrite, h.revokeInvitation)
 			}
 		}
 
 		items := api.Group("items")
 		{
-			items.GET("/:id", h.getItemById)
-			items.PUT("/:id", h.updateItem)
-			items.DELETE("/:id", h.deleteItem)
+			items.GET("/", itemsRead, h.getItemsByTags)
+			items.GET("/overdue", itemsRead, h.getOverdueItems)
+			items.GET("/:id", itemsRead, h.getItemById)
+			items.PUT("/:id", itemsWrite, h.updateItem)
+			items.DELETE("/:id", itemsWrite, h.deleteItem)
+			items.POST("/:id/move", itemsWrite, h.moveItemToList)
+			items.POST("/:id/copy", itemsWrite, h.copyItemToList)
+
+			itemTags := items.Group(":id/tags")
+			{
+				itemTags.GET("/", tagsRead, h.getItemTags)
+				itemTags.POST("/:tagId", itemsWrite, h.attachTag)
+				itemTags.DELETE("/:tagId", itemsWrite, h.detachTag)
+			}
+
+			reminders := items.Group(":id/reminders")
+			{
+				reminders.POST("/", itemsWrite, h.createReminder)
+				reminders.GET("/", itemsRead, h.getItemReminders)
+				reminders.DELETE("/:reminderId", itemsWrite, h.deleteReminder)
+			}
+		}
+
+		invitations := api.Group("/invitations")
+		{
+			invitations.GET("/", listsRead, h.getPendingInvitations)
+			invitations.POST("/:id/accept", listsWrite, h.acceptInvitation)
+			invitations.POST("/:id/decline", listsWrite, h.declineInvitation)
+		}
+
+		// Отдельная группа: в gin статический путь не может соседствовать с /invitations/:id
+		api.POST("/invite-links/accept", listsWrite, h.acceptInviteLink)
+
+		workspaces := api.Group("/workspaces")
+		{
+			workspaces.POST("/", workspacesWrite, h.createWorkspace)
+			workspaces.GET("/", workspacesRead, h.getAllWorkspaces)
+			workspaces.GET("/:id/lists", workspacesRead, h.getWorkspaceLists)
+
+			workspaceMembers := workspaces.Group(":id/members")
+			{
+				workspaceMembers.GET("/", workspacesRead, h.getWorkspaceMembers)
+				workspaceMembers.POST("/", workspacesWrite, h.addWorkspaceMember)
+				workspaceMembers.DELETE("/:username", workspacesWrite, h.removeWorkspaceMember)
+			}
+		}
+
+		tags := api.Group("/tags")
+		{
+			tags.POST("/", tagsWrite, h.createTag)
+			tags.GET("/", tagsRead, h.getAllTags)
+			tags.GET("/:id", tagsRead, h.getTagById)
+			tags.PUT("/:id", tagsWrite, h.updateTag)
+			tags.DELETE("/:id", tagsWrite, h.deleteTag)
+		}
+
+		api.GET("/search", listsRead, itemsRead, h.search)
+
+		me := api.Group("/me", account) // Данные текущего пользователя, только с токеном входа
+		{
+			me.GET("", h.getProfile)
+			me.PATCH("", h.updateProfile)
+			me.DELETE("", h.deleteAccount)
+			me.POST("/password", h.changePassword)
+			me.GET("/export", h.exportData)
+			me.POST("/import", h.importData)
+
+			sessions := me.Group("/sessions")
+			{
+				sessions.GET("/", h.getSessions)
+				sessions.DELETE("/:id", h.deleteSession)
+			}
+
+			tokens := me.Group("/tokens")
+			{
+				tokens.POST("/", h.createAccessToken)
+				tokens.GET("/", h.getAccessTokens)
+				tokens.DELETE("/:id", h.deleteAccessToken)
+			}
+
+			twoFactor := me.Group("/2fa")
+			{
+				twoFactor.POST("/enroll", h.enrollTwoFactor)
+				twoFactor.POST("/confirm", h.confirmTwoFactor)
+				twoFactor.POST("/disable", h.disableTwoFactor)
+			}
+		}
+	}
+
+	// Управление пользователями, только для администраторов с токеном входа
+	admin := mux.Group("/admin", h.userIdentity, account, h.requireAdmin)
+	{
+		users := admin.Group("/users")
+		{
+			users.GET("/", h.adminGetUsers)
+			users.GET("/:id", h.adminGetUser)
+			users.POST("/:id/disable", h.adminDisableUser)
+			users.POST("/:id/enable", h.adminEnableUser)
+			users.POST("/:id/reset-password", h.adminResetPassword)
+			users.POST("/:id/impersonate", h.adminImpersonate)
 		}
 	}
 	return mux, nil
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createInvitationResponse struct {
+	Id    int    `json:"id"`
+	Token string `json:"token,omitempty"` // токен ссылки-приглашения, показывается один раз
+}
+
+type getInvitationsResponse struct {
+	Data []todo.Invitation `json:"data"`
+}
+
+// @Summary Create Invitation
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description invite user by username or create invite link token (owner only)
+// @ID create-invitation
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Param input body todo.CreateInvitationInput true "Invitee username (empty for invite link), role and lifetime"
+// @Success 200 {object} createInvitationResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/invitations [post]
+func (h *Handler) createInvitation(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	var input todo.CreateInvitationInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, token, err := h.services.Invitations.Create(userId, listId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, createInvitationResponse{
+		Id:    id,
+		Token: token,
+	})
+}
+
+// @Summary Get List Invitations
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description get pending invitations of list (owner only)
+// @ID get-list-invitations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Success 200 {object} getInvitationsResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/invitations [get]
+func (h *Handler) getListInvitations(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	invitations, err := h.services.Invitations.GetByList(userId, listId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getInvitationsResponse{
+		Data: invitations,
+	})
+}
+
+// @Summary Revoke Invitation
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description revoke pending invitation or invite link (owner only)
+// @ID revoke-invitation
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Param invitationId path int true "Invitation Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/invitations/{invitationId} [delete]
+func (h *Handler) revokeInvitation(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	invitationId, err := strconv.Atoi(c.Param("invitationId"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid invitation id param")
+		return
+	}
+
+	if err := h.services.Invitations.Revoke(userId, listId, invitationId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Get Pending Invitations
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description get pending invitations addressed to user
+// @ID get-pending-invitations
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} getInvitationsResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/invitations [get]
+func (h *Handler) getPendingInvitations(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	invitations, err := h.services.Invitations.GetPending(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getInvitationsResponse{
+		Data: invitations,
+	})
+}
+
+// @Summary Accept Invitation
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description accept invitation and join list with offered role
+// @ID accept-invitation
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invitation Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/invitations/{id}/accept [post]
+func (h *Handler) acceptInvitation(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	invitationId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid invitation id param")
+		return
+	}
+
+	if _, err := h.services.Invitations.Accept(userId, invitationId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// В кэше пользователя устарел список lists
+	if err := h.services.TodoListCach.HDelete(userId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Decline Invitation
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description decline invitation addressed to user
+// @ID decline-invitation
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Invitation Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/invitations/{id}/decline [post]
+func (h *Handler) declineInvitation(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	invitationId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid invitation id param")
+		return
+	}
+
+	if err := h.services.Invitations.Decline(userId, invitationId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Accept Invite Link
+// @Security ApiKeyAuth
+// @Tags invitations
+// @Description join list by invite link token
+// @ID accept-invite-link
+// @Accept  json
+// @Produce  json
+// @Param input body todo.AcceptInviteLinkInput true "Invite link token"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/invite-links/accept [post]
+func (h *Handler) acceptInviteLink(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.AcceptInviteLinkInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, err := h.services.Invitations.AcceptToken(userId, input.Token); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// В кэше пользователя устарел список lists
+	if err := h.services.TodoListCach.HDelete(userId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
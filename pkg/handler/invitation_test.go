@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createInvitation(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockInvitations)
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "By Username",
+			inputBody: `{"username":"bob","role":"editor"}`,
+			mockBehavior: func(s *mock_service.MockInvitations) {
+				s.EXPECT().Create(1, 3, todo.CreateInvitationInput{Username: "bob", Role: todo.ListRoleEditor}).Return(5, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":5}`,
+		},
+		{
+			name:      "Invite Link",
+			inputBody: `{"role":"viewer","expires_in":24}`,
+			mockBehavior: func(s *mock_service.MockInvitations) {
+				s.EXPECT().Create(1, 3, todo.CreateInvitationInput{Role: todo.ListRoleViewer, ExpiresIn: 24}).Return(6, "token", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":6,"token":"token"}`,
+		},
+		{
+			name:                 "Invalid Role",
+			inputBody:            `{"username":"bob","role":"admin"}`,
+			mockBehavior:         func(s *mock_service.MockInvitations) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid role (allowed: owner, editor, viewer)"}`,
+		},
+		{
+			name:                 "Too Long",
+			inputBody:            `{"role":"viewer","expires_in":1000}`,
+			mockBehavior:         func(s *mock_service.MockInvitations) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"expires_in must be between 0 and 720 hours"}`,
+		},
+		{
+			name:      "Not Owner",
+			inputBody: `{"username":"bob","role":"editor"}`,
+			mockBehavior: func(s *mock_service.MockInvitations) {
+				s.EXPECT().Create(1, 3, todo.CreateInvitationInput{Username: "bob", Role: todo.ListRoleEditor}).Return(0, "", todo.ErrListRole(todo.ListRoleOwner))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"list role owner required"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			invitations := mock_service.NewMockInvitations(c)
+			testCase.mockBehavior(invitations)
+
+			services := &service.Service{Invitations: invitations}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/lists/:id/invitations", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.createInvitation)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/lists/3/invitations", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_acceptInvitation(t *testing.T) {
+	type field struct {
+		invitations *mock_service.MockInvitations
+		cache       *mock_service.MockTodoListCach
+	}
+
+	testTable := []struct {
+		name                 string
+		invitationId         string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:         "OK",
+			invitationId: "5",
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.invitations.EXPECT().Accept(1, 5).Return(3, nil),
+					f.cache.EXPECT().HDelete(1).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Id",
+			invitationId:         "a",
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid invitation id param"}`,
+		},
+		{
+			name:         "Expired",
+			invitationId: "5",
+			prepare: func(f *field) {
+				f.invitations.EXPECT().Accept(1, 5).Return(0, errors.New("invitation not found or expired"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"invitation not found or expired"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				invitations: mock_service.NewMockInvitations(c),
+				cache:       mock_service.NewMockTodoListCach(c),
+			}
+			testCase.prepare(&f)
+
+			services := &service.Service{Invitations: f.invitations, TodoListCach: f.cache}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/invitations/:id/accept", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.acceptInvitation)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/invitations/"+testCase.invitationId+"/accept", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_acceptInviteLink(t *testing.T) {
+	type field struct {
+		invitations *mock_service.MockInvitations
+		cache       *mock_service.MockTodoListCach
+	}
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"token":"abc"}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.invitations.EXPECT().AcceptToken(1, "abc").Return(3, nil),
+					f.cache.EXPECT().HDelete(1).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "No Token",
+			inputBody:            `{}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'AcceptInviteLinkInput.Token' Error:Field validation for 'Token' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Already Member",
+			inputBody: `{"token":"abc"}`,
+			prepare: func(f *field) {
+				f.invitations.EXPECT().AcceptToken(1, "abc").Return(0, errors.New("already a member of this list"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"already a member of this list"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				invitations: mock_service.NewMockInvitations(c),
+				cache:       mock_service.NewMockTodoListCach(c),
+			}
+			testCase.prepare(&f)
+
+			services := &service.Service{Invitations: f.invitations, TodoListCach: f.cache}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/invite-links/accept", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.acceptInviteLink)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/invite-links/accept", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"todo-app"
@@ -62,15 +64,29 @@ func (h *Handler) createItem(c *gin.Context) {
 	})
 }
 
+// Ответ на постраничный запрос (с limit), без limit задачи возвращаются массивом
+type getAllItemsResponse struct {
+	Data       []todo.TodoItem `json:"data"`
+	NextCursor string          `json:"next_cursor,omitempty"` // курсор следующей страницы, на последней странице отсутствует
+}
+
 // @Summary Get All Items
 // @Security ApiKeyAuth
 // @Tags items
-// @Description get all Items
+// @Description get all Items as an array; with limit the items are returned by pages in an object with data and next_cursor fields, pass next_cursor to get the next page
 // @ID get-all-items
 // @Accept  json
 // @Produce  json
 // @Param id path int true "List Id"
-// @Success 200 {object} []todo.TodoItem
+// @Param overdue query bool false "only overdue items"
+// @Param sort query string false "items order: manual (default), by priority (highest first, then by creation time), title or creation time" Enums(priority, title, created)
+// @Param order query string false "direction of the sort field" Enums(asc, desc)
+// @Param done query bool false "only done or not done items"
+// @Param q query string false "substring of the title"
+// @Param limit query int false "page size (max 200), all items if not set"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param tree query bool false "return subtasks nested in children of their parent items"
+// @Success 200 {array} todo.TodoItem
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Failure default {object} errorResponse
@@ -88,40 +104,198 @@ func (h *Handler) getAllItems(c *gin.Context) {
 		return
 	}
 
+	overdue, err := parseBoolQuery(c, "overdue")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tree, err := parseBoolQuery(c, "tree")
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var query todo.ItemsQuery
+	if err := c.BindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid query params: %s", err.Error()))
+		return
+	}
+	if err := query.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var items []todo.TodoItem
+	nextCursor := ""
+
+	switch {
+	case overdue: // Просроченность зависит от текущего времени, поэтому такие выборки не кэшируются
+		items, err = h.services.TodoItem.GetOverdue(userId, listId)
+	case !query.IsDefault(): // В кэше хранится только полная выборка в порядке по умолчанию, остальные выборки берем из БД
+		items, nextCursor, err = h.services.TodoItem.GetAll(userId, listId, query)
+	default:
+		items, err = h.getAllItemsCached(userId, listId)
+	}
+	if errors.Is(err, todo.ErrInvalidCursor) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// В кэше хранится плоский список, дерево строится при каждом запросе
+	if tree {
+		items = todo.BuildItemTree(items)
+	}
+
+	// Обертка с курсором только для постраничных запросов, остальные клиенты получают массив
+	if query.Limit > 0 {
+		c.JSON(http.StatusOK, getAllItemsResponse{
+			Data:       items,
+			NextCursor: nextCursor,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// Ищем в кэше ключ items:userId:listId, если его нет, то отправляемся к БД и кэшируем результат, если есть, то достаем и отправляем
+func (h *Handler) getAllItemsCached(userId, listId int) ([]todo.TodoItem, error) {
 	var items []todo.TodoItem
 
-	// Ищем в кэше ключ items:userId:listId, если его нет, то отправляемся к БД, если есть, то достаем и отправляем
 	val, err := h.services.TodoItemCach.HGet(userId, listId, -1)
 	if err == redis.Nil { // Если в кэше нет  items, берем из БД
 
 		logrus.Print("Request to Postgres")
 
-		items, err = h.services.TodoItem.GetAll(userId, listId)
+		items, _, err = h.services.TodoItem.GetAll(userId, listId, todo.ItemsQuery{})
 		if err != nil {
-			newErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 
 		data, err := json.Marshal(items) // Конвертируем структуру в слайз байт
 		if err != nil {
-			newErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 
 		// Добавим items в кэш Redis. Используем команду конвейер (Pipeline) для одновременного выполнения команд записи в кэш и установление тайм-аута ключа
-		err = h.services.TodoItemCach.HSet(userId, listId, -1, string(data))
-		if err != nil {
-			newErrorResponse(c, http.StatusInternalServerError, err.Error())
-			return
+		if err := h.services.TodoItemCach.HSet(userId, listId, -1, string(data)); err != nil {
+			return nil, err
 		}
 	} else if err != nil {
-		newErrorResponse(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, err
 	} else { // если ключ есть в кэше, то отправляем его значение
 		logrus.Print("Request to Redis")
 		json.Unmarshal([]byte(val), &items)
 	}
 
+	return items, nil
+}
+
+// Необязательный логический параметр запроса, отсутствие параметра - false
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+
+	res, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s param", name)
+	}
+
+	return res, nil
+}
+
+// @Summary Move Item
+// @Security ApiKeyAuth
+// @Tags items
+// @Description move item before or after another item of the list (manual ordering)
+// @ID move-item
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Param itemId path int true "Item Id"
+// @Param input body todo.MoveInput true "Neighbour item: before_id or after_id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/items/{itemId}/move [post]
+func (h *Handler) moveItem(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	var input todo.MoveInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(itemId); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.services.TodoItem.Move(userId, listId, itemId, input); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Удаляем список items:listId, т.к. изменился порядок задач
+	err = h.services.TodoItemCach.HDelete(userId, listId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Get Overdue Items
+// @Security ApiKeyAuth
+// @Tags items
+// @Description get overdue Items from all lists
+// @ID get-overdue-items
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []todo.TodoItem
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/overdue [get]
+func (h *Handler) getOverdueItems(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	items, err := h.services.TodoItem.GetOverdue(userId, -1)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	c.JSON(http.StatusOK, items)
 }
 
@@ -277,3 +451,102 @@ func (h *Handler) deleteItem(c *gin.Context) {
 
 	c.JSON(http.StatusOK, statusResponse{"ok"})
 }
+
+// @Summary Move Item To List
+// @Security ApiKeyAuth
+// @Tags items
+// @Description move item with subtasks to the end of another list
+// @ID move-item-to-list
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Item Id"
+// @Param input body todo.TransferItemInput true "Target list"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/move [post]
+func (h *Handler) moveItemToList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input todo.TransferItemInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.services.TodoItem.MoveToList(userId, itemId, input.ListId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Удаляем все данные из кэша Redis: изменились списки items:listId обоих списков,
+	// а у перенесенных задач в кэше item:id остался прежний list_id
+	err = h.services.TodoItemCach.Delete(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Copy Item To List
+// @Security ApiKeyAuth
+// @Tags items
+// @Description copy item with subtasks and tags to the end of list
+// @ID copy-item-to-list
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Item Id"
+// @Param input body todo.TransferItemInput true "Target list"
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/copy [post]
+func (h *Handler) copyItemToList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid id param")
+		return
+	}
+
+	var input todo.TransferItemInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.TodoItem.CopyToList(userId, itemId, input.ListId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Исходный список не изменился, удаляем только список items:listId списка назначения
+	if err := h.services.TodoItemCach.HDelete(userId, input.ListId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"todo-app"
 	"todo-app/pkg/service"
 	mock_service "todo-app/pkg/service/mocks"
@@ -97,6 +98,38 @@ func TestHandler_createItem(t *testing.T) {
 			expectedStatusCode:   200,
 			expectedResponseBody: `{"id":88}`,
 		},
+		{
+			name: "OK Priority",
+			args: args{
+				userId: 2,
+				listId: 3,
+				inputItem: todo.TodoItem{
+					Title:    "test",
+					Priority: todo.PriorityHigh,
+				},
+			},
+			Id:        89,
+			inputBody: `{"title":"test","priority":"high"}`,
+			prepare: func(f *field, args args, Id int) {
+				gomock.InOrder(
+					f.mockBehaviorCreate.EXPECT().Create(args.userId, args.listId, args.inputItem).Return(Id, nil),
+					f.mockBehaviorHDel.EXPECT().HDelete(args.userId, args.listId).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":89}`,
+		},
+		{
+			name: "Invalid Priority",
+			args: args{
+				userId: 2,
+				listId: 3,
+			},
+			inputBody:            `{"title":"test","priority":"later"}`,
+			prepare:              func(f *field, args args, Id int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid priority: \"later\" (allowed: none, low, medium, high, urgent)"}`,
+		},
 		{
 			name: "Empty Fields",
 			args: args{
@@ -224,6 +257,7 @@ func TestHandler_getAllItems(t *testing.T) {
 		name                 string
 		CtxNil               bool
 		ErrId                bool
+		query                string
 		args                 args
 		prepare              func(f *field, args args)
 		expectedStatusCode   int // статус код ответа
@@ -292,7 +326,7 @@ func TestHandler_getAllItems(t *testing.T) {
 			prepare: func(f *field, args args) {
 				gomock.InOrder(
 					f.mockBehaviorH.EXPECT().HGet(args.userId, args.listId, -1).Return("", redis.Nil),
-					f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId).Return(args.ReturnGetAll, nil),
+					f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId, todo.ItemsQuery{}).Return(args.ReturnGetAll, "", nil),
 					f.mockBehaviorH.EXPECT().HSet(args.userId, args.listId, -1, args.ReturnHGet_InputHSet).Return(nil),
 				)
 			},
@@ -308,7 +342,7 @@ func TestHandler_getAllItems(t *testing.T) {
 			prepare: func(f *field, args args) {
 				gomock.InOrder(
 					f.mockBehaviorH.EXPECT().HGet(args.userId, args.listId, -1).Return("", redis.Nil),
-					f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId).Return([]todo.TodoItem{}, errors.New("Error GetAll")),
+					f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId, todo.ItemsQuery{}).Return([]todo.TodoItem{}, "", errors.New("Error GetAll")),
 				)
 			},
 			expectedStatusCode:   500,
@@ -338,13 +372,140 @@ func TestHandler_getAllItems(t *testing.T) {
 			prepare: func(f *field, args args) {
 				gomock.InOrder(
 					f.mockBehaviorH.EXPECT().HGet(args.userId, args.listId, -1).Return("", redis.Nil),
-					f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId).Return(args.ReturnGetAll, nil),
+					f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId, todo.ItemsQuery{}).Return(args.ReturnGetAll, "", nil),
 					f.mockBehaviorH.EXPECT().HSet(args.userId, args.listId, -1, args.ReturnHGet_InputHSet).Return(errors.New("Error HSet")),
 				)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"message":"Error HSet"}`,
 		},
+		{
+			name:  "OK Overdue",
+			query: "?overdue=true",
+			args: args{
+				userId: 55,
+				listId: 44,
+				ReturnGetAll: []todo.TodoItem{
+					{
+						Id:          1,
+						Title:       "test1",
+						Description: "by testing 1",
+						DueDate:     timePointers(time.Date(2022, 5, 30, 18, 0, 0, 0, time.UTC)),
+						DueTimezone: "UTC",
+					},
+				},
+			},
+			prepare: func(f *field, args args) {
+				f.mockBehaviorGetAll.EXPECT().GetOverdue(args.userId, args.listId).Return(args.ReturnGetAll, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "[{\"id\":1,\"title\":\"test1\",\"description\":\"by testing 1\",\"done\":false,\"due_date\":\"2022-05-30T18:00:00Z\",\"due_timezone\":\"UTC\"}]",
+		},
+		{
+			name:  "Error GetOverdue",
+			query: "?overdue=1",
+			args: args{
+				userId: 55,
+				listId: 44,
+			},
+			prepare: func(f *field, args args) {
+				f.mockBehaviorGetAll.EXPECT().GetOverdue(args.userId, args.listId).Return(nil, errors.New("Error GetOverdue"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error GetOverdue"}`,
+		},
+		{
+			name:  "OK Sort Priority",
+			query: "?sort=priority",
+			args: args{
+				userId: 55,
+				listId: 44,
+				ReturnGetAll: []todo.TodoItem{
+					{Id: 4, Title: "test4", Priority: todo.PriorityUrgent},
+					{Id: 1, Title: "test1", Priority: todo.PriorityLow},
+					{Id: 2, Title: "test2"},
+				},
+			},
+			prepare: func(f *field, args args) {
+				f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId, todo.ItemsQuery{Sort: todo.ItemSortPriority}).Return(args.ReturnGetAll, "", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":4,"title":"test4","description":"","done":false,"priority":"urgent"},{"id":1,"title":"test1","description":"","done":false,"priority":"low"},{"id":2,"title":"test2","description":"","done":false}]`,
+		},
+		{
+			name:  "OK Tree From Redis",
+			query: "?tree=true",
+			args: args{
+				userId:               55,
+				listId:               44,
+				ReturnHGet_InputHSet: `[{"id":1,"title":"root","description":"","done":false},{"id":2,"title":"child","description":"","done":false,"parent_id":1},{"id":3,"title":"grandchild","description":"","done":true,"parent_id":2}]`,
+			},
+			prepare: func(f *field, args args) {
+				f.mockBehaviorH.EXPECT().HGet(args.userId, args.listId, -1).Return(args.ReturnHGet_InputHSet, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":1,"title":"root","description":"","done":false,"children":[{"id":2,"title":"child","description":"","done":false,"parent_id":1,"children":[{"id":3,"title":"grandchild","description":"","done":true,"parent_id":2}]}]}]`,
+		},
+		{
+			name:                 "Invalid Tree Param",
+			query:                "?tree=maybe",
+			args:                 args{userId: 55, listId: 44},
+			prepare:              func(f *field, args args) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid tree param"}`,
+		},
+		{
+			name:                 "Invalid Sort Param",
+			query:                "?sort=due",
+			args:                 args{userId: 55, listId: 44},
+			prepare:              func(f *field, args args) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid sort param: due"}`,
+		},
+		{
+			name:  "OK Page",
+			query: "?sort=created&order=desc&done=false&q=test&limit=2",
+			args: args{
+				userId:       55,
+				listId:       44,
+				ReturnGetAll: []todo.TodoItem{{Id: 4, Title: "test4"}, {Id: 2, Title: "test2"}},
+			},
+			prepare: func(f *field, args args) {
+				// Отфильтрованная и постраничная выборка не кэшируется
+				done := false
+				f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId, todo.ItemsQuery{
+					Sort: todo.ItemSortCreated, Order: todo.SortOrderDesc, Done: &done, Query: "test", Limit: 2,
+				}).Return(args.ReturnGetAll, "next", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":4,"title":"test4","description":"","done":false},{"id":2,"title":"test2","description":"","done":false}],"next_cursor":"next"}`,
+		},
+		{
+			name:                 "Cursor Without Limit",
+			query:                "?cursor=" + todo.Cursor{Values: []string{"V", "4"}}.Encode(),
+			args:                 args{userId: 55, listId: 44},
+			prepare:              func(f *field, args args) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"cursor requires limit"}`,
+		},
+		{
+			name:  "Invalid Cursor Values",
+			query: "?limit=2&cursor=" + todo.Cursor{Values: []string{"4"}}.Encode(),
+			args:  args{userId: 55, listId: 44},
+			prepare: func(f *field, args args) {
+				f.mockBehaviorGetAll.EXPECT().GetAll(args.userId, args.listId, gomock.Any()).Return(nil, "", todo.ErrInvalidCursor)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid cursor"}`,
+		},
+		{
+			name:                 "Invalid Overdue Param",
+			query:                "?overdue=yes",
+			args:                 args{userId: 55, listId: 44},
+			prepare:              func(f *field, args args) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid overdue param"}`,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -379,7 +540,7 @@ func TestHandler_getAllItems(t *testing.T) {
 			if testCase.ErrId {
 				req = httptest.NewRequest("GET", "/lists/err/items", nil)
 			} else {
-				req = httptest.NewRequest("GET", fmt.Sprintf("/lists/%d/items", testCase.args.listId), nil)
+				req = httptest.NewRequest("GET", fmt.Sprintf("/lists/%d/items%s", testCase.args.listId, testCase.query), nil)
 			}
 
 			// Perform Request
@@ -562,6 +723,84 @@ func TestHandler_getItemById(t *testing.T) {
 	}
 }
 
+func TestHandler_getOverdueItems(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoItem, userId int, items []todo.TodoItem)
+
+	due := time.Date(2022, 5, 30, 0, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	testTable := []struct {
+		name                 string
+		CtxNil               bool
+		userId               int
+		items                []todo.TodoItem
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			userId: 7,
+			items: []todo.TodoItem{
+				{Id: 3, Title: "report", DueDate: &due, DueAllDay: true, DueTimezone: "Europe/Moscow"},
+			},
+			mockBehavior: func(s *mock_service.MockTodoItem, userId int, items []todo.TodoItem) {
+				s.EXPECT().GetOverdue(userId, -1).Return(items, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":3,"title":"report","description":"","done":false,"due_date":"2022-05-30T00:00:00+03:00","due_all_day":true,"due_timezone":"Europe/Moscow"}]`,
+		},
+		{
+			name:                 "Error getUserId",
+			CtxNil:               true,
+			mockBehavior:         func(s *mock_service.MockTodoItem, userId int, items []todo.TodoItem) {},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"user id not found"}`,
+		},
+		{
+			name:   "Error GetOverdue",
+			userId: 7,
+			mockBehavior: func(s *mock_service.MockTodoItem, userId int, items []todo.TodoItem) {
+				s.EXPECT().GetOverdue(userId, -1).Return(nil, errors.New("Error GetOverdue"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error GetOverdue"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			item := mock_service.NewMockTodoItem(c)
+			testCase.mockBehavior(item, testCase.userId, testCase.items)
+
+			services := &service.Service{TodoItem: item}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			if testCase.CtxNil {
+				r.GET("/items/overdue", handler.getOverdueItems)
+			} else {
+				r.GET("/items/overdue", func(c *gin.Context) { c.Set(userCtx, testCase.userId) }, handler.getOverdueItems)
+			}
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/items/overdue", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestHandler_updateItem(t *testing.T) {
 
 	type field struct {
@@ -882,6 +1121,286 @@ func TestHandler_deleteItem(t *testing.T) {
 	}
 }
 
+func TestHandler_moveItem(t *testing.T) {
+
+	type field struct {
+		mockBehaviorMove *mock_service.MockTodoItem
+		mockBehaviorHDel *mock_service.MockTodoItemCach
+	}
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		inputBody            string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			url:       "/lists/1/items/7/move",
+			inputBody: `{"after_id":4}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.mockBehaviorMove.EXPECT().Move(2, 1, 7, todo.MoveInput{AfterId: intPointers(4)}).Return(nil),
+					f.mockBehaviorHDel.EXPECT().HDelete(2, 1).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Item Id",
+			url:                  "/lists/1/items/a/move",
+			inputBody:            `{"after_id":4}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid item id param"}`,
+		},
+		{
+			name:                 "Both Neighbours",
+			url:                  "/lists/1/items/7/move",
+			inputBody:            `{"before_id":3,"after_id":4}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"move requires either before_id or after_id"}`,
+		},
+		{
+			name:      "Error Move",
+			url:       "/lists/1/items/7/move",
+			inputBody: `{"before_id":3}`,
+			prepare: func(f *field) {
+				f.mockBehaviorMove.EXPECT().Move(2, 1, 7, todo.MoveInput{BeforeId: intPointers(3)}).Return(errors.New("element 3 not found"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"element 3 not found"}`,
+		},
+		{
+			name:      "Error HDel",
+			url:       "/lists/1/items/7/move",
+			inputBody: `{"before_id":3}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.mockBehaviorMove.EXPECT().Move(2, 1, 7, todo.MoveInput{BeforeId: intPointers(3)}).Return(nil),
+					f.mockBehaviorHDel.EXPECT().HDelete(2, 1).Return(errors.New("Error HDel")),
+				)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error HDel"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				mockBehaviorMove: mock_service.NewMockTodoItem(c),
+				mockBehaviorHDel: mock_service.NewMockTodoItemCach(c),
+			}
+
+			testCase.prepare(&f)
+
+			services := &service.Service{TodoItem: f.mockBehaviorMove, TodoItemCach: f.mockBehaviorHDel}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/lists/:id/items/:itemId/move", func(c *gin.Context) { c.Set(userCtx, 2) }, handler.moveItem)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", testCase.url, bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_moveItemToList(t *testing.T) {
+
+	type field struct {
+		mockBehaviorMove *mock_service.MockTodoItem
+		mockBehaviorDel  *mock_service.MockTodoItemCach
+	}
+
+	testTable := []struct {
+		name                 string
+		itemId               string
+		inputBody            string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			itemId:    "3",
+			inputBody: `{"list_id":5}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.mockBehaviorMove.EXPECT().MoveToList(2, 3, 5).Return(nil),
+					f.mockBehaviorDel.EXPECT().Delete(2).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Id",
+			itemId:               "a",
+			inputBody:            `{"list_id":5}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid id param"}`,
+		},
+		{
+			name:                 "No List Id",
+			itemId:               "3",
+			inputBody:            `{}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'TransferItemInput.ListId' Error:Field validation for 'ListId' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Error Move",
+			itemId:    "3",
+			inputBody: `{"list_id":5}`,
+			prepare: func(f *field) {
+				f.mockBehaviorMove.EXPECT().MoveToList(2, 3, 5).Return(errors.New("sql: no rows in result set"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				mockBehaviorMove: mock_service.NewMockTodoItem(c),
+				mockBehaviorDel:  mock_service.NewMockTodoItemCach(c),
+			}
+
+			testCase.prepare(&f)
+
+			services := &service.Service{TodoItem: f.mockBehaviorMove, TodoItemCach: f.mockBehaviorDel}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/items/:id/move", func(c *gin.Context) { c.Set(userCtx, 2) }, handler.moveItemToList)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/items/"+testCase.itemId+"/move", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_copyItemToList(t *testing.T) {
+
+	type field struct {
+		mockBehaviorCopy *mock_service.MockTodoItem
+		mockBehaviorHDel *mock_service.MockTodoItemCach
+	}
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"list_id":5}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.mockBehaviorCopy.EXPECT().CopyToList(2, 3, 5).Return(10, nil),
+					f.mockBehaviorHDel.EXPECT().HDelete(2, 5).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":10}`,
+		},
+		{
+			name:      "Error Copy",
+			inputBody: `{"list_id":5}`,
+			prepare: func(f *field) {
+				f.mockBehaviorCopy.EXPECT().CopyToList(2, 3, 5).Return(0, errors.New("Error Copy"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error Copy"}`,
+		},
+		{
+			name:      "Error HDel",
+			inputBody: `{"list_id":5}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.mockBehaviorCopy.EXPECT().CopyToList(2, 3, 5).Return(10, nil),
+					f.mockBehaviorHDel.EXPECT().HDelete(2, 5).Return(errors.New("Error HDel")),
+				)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error HDel"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				mockBehaviorCopy: mock_service.NewMockTodoItem(c),
+				mockBehaviorHDel: mock_service.NewMockTodoItemCach(c),
+			}
+
+			testCase.prepare(&f)
+
+			services := &service.Service{TodoItem: f.mockBehaviorCopy, TodoItemCach: f.mockBehaviorHDel}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/items/:id/copy", func(c *gin.Context) { c.Set(userCtx, 2) }, handler.copyItemToList)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/items/3/copy", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func boolPointers(b bool) *bool {
 	return &b
 }
+
+func timePointers(t time.Time) *time.Time {
+	return &t
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -57,16 +58,22 @@ func (h *Handler) createList(c *gin.Context) {
 }
 
 type getAllListsResponce struct { // Структура для использования в ответе
-	Data []todo.TodoList `json:"data"`
+	Data       []todo.TodoList `json:"data"`
+	NextCursor string          `json:"next_cursor,omitempty"` // курсор следующей страницы, на последней странице отсутствует
 }
 
 // @Summary Get All Lists
 // @Security ApiKeyAuth
 // @Tags lists
-// @Description get all lists
+// @Description get all lists; with limit the lists are returned by pages, pass next_cursor to get the next page
 // @ID get-all-lists
 // @Accept  json
 // @Produce  json
+// @Param sort query string false "lists order: manual (default), title or creation time" Enums(title, created)
+// @Param order query string false "direction of the sort field" Enums(asc, desc)
+// @Param q query string false "substring of the title"
+// @Param limit query int false "page size (max 200), all lists if not set"
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} getAllListsResponce
 // @Failure 400,404 {object} errorResponse
 // @Failure 500 {object} errorResponse
@@ -81,13 +88,42 @@ func (h *Handler) getAllLists(c *gin.Context) {
 		return
 	}
 
+	var query todo.ListsQuery
+	if err := c.BindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid query params: %s", err.Error()))
+		return
+	}
+	if err := query.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// В кэше хранится только полная выборка в порядке по умолчанию, остальные выборки берем из БД
+	if !query.IsDefault() {
+		lists, nextCursor, err := h.services.TodoList.GetAll(userId, query)
+		if errors.Is(err, todo.ErrInvalidCursor) {
+			newErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		c.JSON(http.StatusOK, getAllListsResponce{
+			Data:       lists,
+			NextCursor: nextCursor,
+		})
+		return
+	}
+
 	// Проверяем существует ли ключ "lists_userId" в кэше redis
 	val, err := h.services.TodoListCach.HGet(userId, -1)
 	if err == redis.Nil { // Если ключа не существует, вытаскиваем данные из postgres и кэшируем в redis
 
 		logrus.Print("Request to Postgres")
 
-		lists, err = h.services.TodoList.GetAll(userId) // вытаскиваем списки из БД для определенного пользователя
+		lists, _, err = h.services.TodoList.GetAll(userId, query) // вытаскиваем списки из БД для определенного пользователя
 		if err != nil {
 			newErrorResponse(c, http.StatusInternalServerError, err.Error())
 			return
@@ -259,20 +295,82 @@ func (h *Handler) deleteList(c *gin.Context) {
 		return
 	}
 
-	err = h.services.TodoList.DeleteById(userId, id) // Удаляем из таблицы Списков и связывающей таблицы список по id
+	// Участников запоминаем до удаления, после него связь со списком уже не найти
+	members, err := h.services.TodoList.GetMembers(userId, id)
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Удаляем все данные из кэша Redis, т.к. изменения могли коснуться любого поля ключа user:userId
-	err = h.services.TodoListCach.Delete(userId)
+	err = h.services.TodoList.DeleteById(userId, id) // Удаляем из таблицы Списков и связывающей таблицы список по id
 	if err != nil {
 		newErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	// Удаляем все данные из кэша Redis всех участников, т.к. изменения могли коснуться любого поля ключа user:userId
+	for _, member := range members {
+		err = h.services.TodoListCach.Delete(member.UserId)
+		if err != nil {
+			newErrorResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"Ok": fmt.Sprintf("deleted list by id: %d", id),
 	})
 }
+
+// @Summary Move List
+// @Security ApiKeyAuth
+// @Tags lists
+// @Description move list before or after another list (manual ordering)
+// @ID move-list
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Param input body todo.MoveInput true "Neighbour list: before_id or after_id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/move [post]
+func (h *Handler) moveList(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid type list id")
+		return
+	}
+
+	var input todo.MoveInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(id); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.services.TodoList.Move(userId, id, input); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Удалим список lists из кэша redis, т.к. изменился порядок списков
+	err = h.services.TodoListCach.HDelete(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
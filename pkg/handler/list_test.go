@@ -180,6 +180,7 @@ func TestHandler_getAllLists(t *testing.T) {
 		name                 string
 		CtxNil               bool
 		userId               int
+		query                string
 		ReturnHGet_InputHSet string
 		ReturnGetAll         []todo.TodoList
 		prepare              func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList)
@@ -231,7 +232,7 @@ func TestHandler_getAllLists(t *testing.T) {
 			prepare: func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList) {
 				gomock.InOrder(
 					f.mockBehaviorH.EXPECT().HGet(userId, -1).Return("", redis.Nil),
-					f.mockBehaviorGetAll.EXPECT().GetAll(userId).Return(ReturnGetAll, nil),
+					f.mockBehaviorGetAll.EXPECT().GetAll(userId, todo.ListsQuery{}).Return(ReturnGetAll, "", nil),
 					f.mockBehaviorH.EXPECT().HSet(userId, -1, ReturnHGet_InputHSet).Return(nil),
 				)
 			},
@@ -244,7 +245,7 @@ func TestHandler_getAllLists(t *testing.T) {
 			prepare: func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList) {
 				gomock.InOrder(
 					f.mockBehaviorH.EXPECT().HGet(userId, -1).Return("", redis.Nil),
-					f.mockBehaviorGetAll.EXPECT().GetAll(userId).Return(nil, errors.New("Error GetAll")),
+					f.mockBehaviorGetAll.EXPECT().GetAll(userId, todo.ListsQuery{}).Return(nil, "", errors.New("Error GetAll")),
 				)
 			},
 			expectedStatusCode:   500,
@@ -269,13 +270,41 @@ func TestHandler_getAllLists(t *testing.T) {
 			prepare: func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList) {
 				gomock.InOrder(
 					f.mockBehaviorH.EXPECT().HGet(userId, -1).Return("", redis.Nil),
-					f.mockBehaviorGetAll.EXPECT().GetAll(userId).Return(ReturnGetAll, nil),
+					f.mockBehaviorGetAll.EXPECT().GetAll(userId, todo.ListsQuery{}).Return(ReturnGetAll, "", nil),
 					f.mockBehaviorH.EXPECT().HSet(userId, -1, ReturnHGet_InputHSet).Return(errors.New("Error HSet")),
 				)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: `{"message":"Error HSet"}`,
 		},
+		{
+			name:   "OK Page",
+			userId: 55,
+			query:  "?sort=title&order=desc&q=te&limit=1",
+			prepare: func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList) {
+				// Постраничная выборка не кэшируется
+				f.mockBehaviorGetAll.EXPECT().GetAll(userId, todo.ListsQuery{Sort: todo.ListSortTitle, Order: todo.SortOrderDesc, Query: "te", Limit: 1}).
+					Return([]todo.TodoList{{Id: 4, Title: "test4"}}, "next", nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":4,"title":"test4","description":""}],"next_cursor":"next"}`,
+		},
+		{
+			name:                 "Invalid Order",
+			userId:               55,
+			query:                "?order=up",
+			prepare:              func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid order param: up"}`,
+		},
+		{
+			name:                 "Cursor For Another Sort",
+			userId:               55,
+			query:                "?sort=title&limit=1&cursor=" + todo.Cursor{Values: []string{"V", "4"}}.Encode(),
+			prepare:              func(f *field, userId int, ReturnHGet_InputHSet string, ReturnGetAll []todo.TodoList) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid cursor: sort or order changed"}`,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -306,7 +335,7 @@ func TestHandler_getAllLists(t *testing.T) {
 
 			// Test Request
 			w := httptest.NewRecorder()
-			req := httptest.NewRequest("GET", "/lists", nil)
+			req := httptest.NewRequest("GET", "/lists"+testCase.query, nil)
 
 			// Perform Request
 			r.ServeHTTP(w, req)
@@ -648,8 +677,10 @@ func TestHandler_deleteList(t *testing.T) {
 			userId: 5,
 			prepare: func(f *field, userId, Id int) {
 				gomock.InOrder(
+					f.mockBehaviorDeleteById.EXPECT().GetMembers(userId, Id).Return([]todo.ListMember{{UserId: userId}, {UserId: 7}}, nil),
 					f.mockBehaviorDeleteById.EXPECT().DeleteById(userId, Id).Return(nil),
 					f.mockBehaviorH.EXPECT().Delete(userId).Return(nil),
+					f.mockBehaviorH.EXPECT().Delete(7).Return(nil),
 				)
 			},
 			expectedStatusCode:   200,
@@ -669,12 +700,25 @@ func TestHandler_deleteList(t *testing.T) {
 			expectedStatusCode:   400,
 			expectedResponseBody: "{\"message\":\"invalid type list id\"}",
 		},
+		{
+			name:   "Error GetMembers",
+			Id:     4,
+			userId: 5,
+			prepare: func(f *field, userId, Id int) {
+				f.mockBehaviorDeleteById.EXPECT().GetMembers(userId, Id).Return(nil, errors.New("sql: no rows in result set"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: "{\"message\":\"sql: no rows in result set\"}",
+		},
 		{
 			name:   "Error DeleteById",
 			Id:     4,
 			userId: 5,
 			prepare: func(f *field, userId, Id int) {
-				f.mockBehaviorDeleteById.EXPECT().DeleteById(userId, Id).Return(errors.New("Error DeleteById"))
+				gomock.InOrder(
+					f.mockBehaviorDeleteById.EXPECT().GetMembers(userId, Id).Return([]todo.ListMember{{UserId: userId}}, nil),
+					f.mockBehaviorDeleteById.EXPECT().DeleteById(userId, Id).Return(errors.New("Error DeleteById")),
+				)
 			},
 			expectedStatusCode:   500,
 			expectedResponseBody: "{\"message\":\"Error DeleteById\"}",
@@ -685,6 +729,7 @@ func TestHandler_deleteList(t *testing.T) {
 			userId: 5,
 			prepare: func(f *field, userId, Id int) {
 				gomock.InOrder(
+					f.mockBehaviorDeleteById.EXPECT().GetMembers(userId, Id).Return([]todo.ListMember{{UserId: userId}}, nil),
 					f.mockBehaviorDeleteById.EXPECT().DeleteById(userId, Id).Return(nil),
 					f.mockBehaviorH.EXPECT().Delete(userId).Return(errors.New("Error Delete")),
 				)
@@ -739,6 +784,114 @@ func TestHandler_deleteList(t *testing.T) {
 	}
 }
 
+func TestHandler_moveList(t *testing.T) {
+
+	type field struct {
+		mockBehaviorMove *mock_service.MockTodoList
+		mockBehaviorHDel *mock_service.MockTodoListCach
+	}
+
+	testTable := []struct {
+		name                 string
+		userId               int
+		listId               string
+		inputBody            string
+		prepare              func(f *field, userId int)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			userId:    2,
+			listId:    "5",
+			inputBody: `{"before_id":3}`,
+			prepare: func(f *field, userId int) {
+				gomock.InOrder(
+					f.mockBehaviorMove.EXPECT().Move(userId, 5, todo.MoveInput{BeforeId: intPointers(3)}).Return(nil),
+					f.mockBehaviorHDel.EXPECT().HDelete(userId).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Id",
+			userId:               2,
+			listId:               "a",
+			inputBody:            `{"before_id":3}`,
+			prepare:              func(f *field, userId int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid type list id"}`,
+		},
+		{
+			name:                 "No Neighbour",
+			userId:               2,
+			listId:               "5",
+			inputBody:            `{}`,
+			prepare:              func(f *field, userId int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"move requires either before_id or after_id"}`,
+		},
+		{
+			name:                 "Relative To Itself",
+			userId:               2,
+			listId:               "5",
+			inputBody:            `{"after_id":5}`,
+			prepare:              func(f *field, userId int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"cannot move relative to itself"}`,
+		},
+		{
+			name:      "Error Move",
+			userId:    2,
+			listId:    "5",
+			inputBody: `{"after_id":3}`,
+			prepare: func(f *field, userId int) {
+				f.mockBehaviorMove.EXPECT().Move(userId, 5, todo.MoveInput{AfterId: intPointers(3)}).Return(errors.New("Error Move"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error Move"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				mockBehaviorMove: mock_service.NewMockTodoList(c),
+				mockBehaviorHDel: mock_service.NewMockTodoListCach(c),
+			}
+
+			testCase.prepare(&f, testCase.userId)
+
+			services := &service.Service{TodoList: f.mockBehaviorMove, TodoListCach: f.mockBehaviorHDel}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/lists/:id/move", func(c *gin.Context) { c.Set(userCtx, testCase.userId) }, handler.moveList)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/lists/"+testCase.listId+"/move", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func stringPointers(s string) *string {
 	return &s
 }
+
+func intPointers(i int) *int {
+	return &i
+}
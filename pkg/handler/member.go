@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getListMembersResponse struct {
+	Data []todo.ListMember `json:"data"`
+}
+
+// @Summary Get List Members
+// @Security ApiKeyAuth
+// @Tags members
+// @Description get members of shared list with their roles
+// @ID get-list-members
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Success 200 {object} getListMembersResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members [get]
+func (h *Handler) getListMembers(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	members, err := h.services.TodoList.GetMembers(userId, listId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getListMembersResponse{
+		Data: members,
+	})
+}
+
+// @Summary Add List Member
+// @Security ApiKeyAuth
+// @Tags members
+// @Description share list with user by username or change member role (owner only)
+// @ID add-list-member
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Param input body todo.AddMemberInput true "Member username and role"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members [post]
+func (h *Handler) addListMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	var input todo.AddMemberInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	memberId, err := h.services.TodoList.AddMember(userId, listId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// У нового участника в кэше устарел список lists
+	err = h.services.TodoListCach.HDelete(memberId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Remove List Member
+// @Security ApiKeyAuth
+// @Tags members
+// @Description remove member from list (owner) or leave list (own username)
+// @ID remove-list-member
+// @Accept  json
+// @Produce  json
+// @Param id path int true "List Id"
+// @Param username path string true "Member username"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/lists/{id}/members/{username} [delete]
+func (h *Handler) removeListMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	listId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid list id param")
+		return
+	}
+
+	memberId, err := h.services.TodoList.RemoveMember(userId, listId, c.Param("username"))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Удаляем все данные исключенного участника из кэша Redis, т.к. там остались данные списка
+	err = h.services.TodoListCach.Delete(memberId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getListMembers(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTodoList)
+
+	testTable := []struct {
+		name                 string
+		listId               string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			listId: "3",
+			mockBehavior: func(s *mock_service.MockTodoList) {
+				s.EXPECT().GetMembers(1, 3).Return([]todo.ListMember{
+					{UserId: 1, Name: "Alex", Username: "alex", Role: todo.ListRoleOwner},
+					{UserId: 2, Name: "Bob", Username: "bob", Role: todo.ListRoleViewer},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"user_id":1,"name":"Alex","username":"alex","role":"owner"},{"user_id":2,"name":"Bob","username":"bob","role":"viewer"}]}`,
+		},
+		{
+			name:                 "Invalid List Id",
+			listId:               "a",
+			mockBehavior:         func(s *mock_service.MockTodoList) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid list id param"}`,
+		},
+		{
+			name:   "Not Member",
+			listId: "3",
+			mockBehavior: func(s *mock_service.MockTodoList) {
+				s.EXPECT().GetMembers(1, 3).Return(nil, errors.New("sql: no rows in result set"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lists := mock_service.NewMockTodoList(c)
+			testCase.mockBehavior(lists)
+
+			services := &service.Service{TodoList: lists}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/lists/:id/members", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getListMembers)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/lists/"+testCase.listId+"/members", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_addListMember(t *testing.T) {
+	type field struct {
+		lists *mock_service.MockTodoList
+		cache *mock_service.MockTodoListCach
+	}
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username":"bob","role":"editor"}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.lists.EXPECT().AddMember(1, 3, todo.AddMemberInput{Username: "bob", Role: todo.ListRoleEditor}).Return(2, nil),
+					f.cache.EXPECT().HDelete(2).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Role",
+			inputBody:            `{"username":"bob","role":"admin"}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid role (allowed: owner, editor, viewer)"}`,
+		},
+		{
+			name:                 "No Username",
+			inputBody:            `{"role":"viewer"}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'AddMemberInput.Username' Error:Field validation for 'Username' failed on the 'required' tag"}`,
+		},
+		{
+			name:      "Not Owner",
+			inputBody: `{"username":"bob","role":"viewer"}`,
+			prepare: func(f *field) {
+				f.lists.EXPECT().AddMember(1, 3, todo.AddMemberInput{Username: "bob", Role: todo.ListRoleViewer}).Return(0, todo.ErrListRole(todo.ListRoleOwner))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"list role owner required"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				lists: mock_service.NewMockTodoList(c),
+				cache: mock_service.NewMockTodoListCach(c),
+			}
+			testCase.prepare(&f)
+
+			services := &service.Service{TodoList: f.lists, TodoListCach: f.cache}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/lists/:id/members", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.addListMember)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/lists/3/members", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_removeListMember(t *testing.T) {
+	type field struct {
+		lists *mock_service.MockTodoList
+		cache *mock_service.MockTodoListCach
+	}
+
+	testTable := []struct {
+		name                 string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.lists.EXPECT().RemoveMember(1, 3, "bob").Return(2, nil),
+					f.cache.EXPECT().Delete(2).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name: "Last Owner",
+			prepare: func(f *field) {
+				f.lists.EXPECT().RemoveMember(1, 3, "bob").Return(0, errors.New("cannot remove the last owner of the list"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"cannot remove the last owner of the list"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				lists: mock_service.NewMockTodoList(c),
+				cache: mock_service.NewMockTodoListCach(c),
+			}
+			testCase.prepare(&f)
+
+			services := &service.Service{TodoList: f.lists, TodoListCach: f.cache}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.DELETE("/lists/:id/members/:username", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.removeListMember)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/lists/3/members/bob", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
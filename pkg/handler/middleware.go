@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"todo-app"
+	"todo-app/pkg/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,6 +13,9 @@ import (
 const (
 	authorizationHeader = "Authorization"
 	userCtx             = "userId"
+	sessionCtx          = "sessionId"
+	scopesCtx           = "scopes" // области доступа персонального токена, для токена входа не задаются
+	adminCtx            = "isAdmin"
 )
 
 func (h *Handler) userIdentity(c *gin.Context) {
@@ -26,13 +31,79 @@ func (h *Handler) userIdentity(c *gin.Context) {
 		return
 	}
 
-	userId, err := h.services.Authorization.ParseToken(headerParts[1])
+	// Персональный токен доступа: права ограничены его областями (см. requireScope)
+	if strings.HasPrefix(headerParts[1], todo.AccessTokenPrefix) {
+		userId, scopes, err := h.services.AccessTokens.Authenticate(headerParts[1])
+		if err != nil {
+			newErrorResponse(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if !h.checkUser(c, userId) {
+			return
+		}
+		c.Set(userCtx, userId)
+		c.Set(scopesCtx, scopes)
+		return
+	}
+
+	userId, sessionId, err := h.services.Authorization.ParseToken(headerParts[1])
 	if err != nil {
 		newErrorResponse(c, http.StatusUnauthorized, err.Error())
 		return
 	}
 
+	if !h.checkUser(c, userId) {
+		return
+	}
 	c.Set(userCtx, userId)
+	c.Set(sessionCtx, sessionId)
+}
+
+// Отключенный администратором или удаленный пользователь отклоняется даже с действующим токеном
+func (h *Handler) checkUser(c *gin.Context, userId int) bool {
+	isAdmin, err := h.services.Authorization.CheckUser(userId)
+	if errors.Is(err, service.ErrAccountDisabled) {
+		newErrorResponse(c, http.StatusForbidden, err.Error())
+		return false
+	}
+	if errors.Is(err, service.ErrUserNotFound) {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return false
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	c.Set(adminCtx, isAdmin)
+	return true
+}
+
+// Маршруты администратора, вызывается после userIdentity
+func (h *Handler) requireAdmin(c *gin.Context) {
+	value, _ := c.Get(adminCtx)
+	if isAdmin, _ := value.(bool); !isAdmin {
+		newErrorResponse(c, http.StatusForbidden, "administrator role required")
+		return
+	}
+}
+
+// Область доступа, необходимая для маршрута. Токен входа имеет все права,
+// персональный токен - только выданные ему области
+func (h *Handler) requireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := c.Get(scopesCtx)
+		if !ok {
+			return
+		}
+
+		granted, _ := scopes.([]string)
+		if !todo.HasScope(granted, scope) {
+			newErrorResponse(c, http.StatusForbidden, todo.ErrScopeRequired(scope).Error())
+			return
+		}
+	}
 }
 
 func getUserId(c *gin.Context) (int, error) {
@@ -50,3 +121,17 @@ func getUserId(c *gin.Context) (int, error) {
 
 	return idInt, nil
 }
+
+func getSessionId(c *gin.Context) (int, error) {
+	id, ok := c.Get(sessionCtx)
+	if !ok {
+		return 0, errors.New("session id not found")
+	}
+
+	idInt, ok := id.(int)
+	if !ok {
+		return 0, errors.New("session id is of invalid type")
+	}
+
+	return idInt, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http/httptest"
 	"testing"
+	"todo-app"
 	"todo-app/pkg/service"
 	mock_service "todo-app/pkg/service/mocks"
 
@@ -32,10 +33,35 @@ func TestHandler_userIdentity(t *testing.T) {
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, nil)
+				s.EXPECT().ParseToken(token).Return(1, 5, nil)
+				s.EXPECT().CheckUser(1).Return(false, nil)
 			},
 			expectedStatusCode:   200,
-			expectedResponseBody: "1",
+			expectedResponseBody: "1 5",
+		},
+		{
+			name:        "Disabled User",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
+				s.EXPECT().ParseToken(token).Return(1, 5, nil)
+				s.EXPECT().CheckUser(1).Return(false, service.ErrAccountDisabled)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"account is disabled"}`,
+		},
+		{
+			name:        "Deleted User",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
+				s.EXPECT().ParseToken(token).Return(1, 5, nil)
+				s.EXPECT().CheckUser(1).Return(false, service.ErrUserNotFound)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"user not found"}`,
 		},
 		{
 			name:                 "Empty Header",
@@ -60,13 +86,24 @@ func TestHandler_userIdentity(t *testing.T) {
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"message":"invalid auth header"}`,
 		},
+		{
+			name:        "Revoked Token",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
+				s.EXPECT().ParseToken(token).Return(0, 0, service.ErrTokenRevoked)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"token revoked"}`,
+		},
 		{
 			name:        "Parse Token Error",
 			headerName:  "Authorization",
 			headerValue: "Bearer token",
 			token:       "token",
 			mockBehavior: func(s *mock_service.MockAuthorization, token string) {
-				s.EXPECT().ParseToken(token).Return(1, errors.New("failed to parse token"))
+				s.EXPECT().ParseToken(token).Return(0, 0, errors.New("failed to parse token"))
 			},
 			expectedStatusCode:   401,
 			expectedResponseBody: `{"message":"failed to parse token"}`,
@@ -89,7 +126,8 @@ func TestHandler_userIdentity(t *testing.T) {
 			r := gin.New()
 			r.GET("/protected", handler.userIdentity, func(c *gin.Context) {
 				id, _ := c.Get(userCtx)
-				c.String(200, fmt.Sprintf("%d", id.(int)))
+				sessionId, _ := c.Get(sessionCtx)
+				c.String(200, fmt.Sprintf("%d %d", id.(int), sessionId.(int)))
 			})
 
 			// Test Request
@@ -107,6 +145,210 @@ func TestHandler_userIdentity(t *testing.T) {
 	}
 }
 
+func TestHandler_userIdentity_AccessToken(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockAccessTokens, auth *mock_service.MockAuthorization, token string)
+
+	testTable := []struct {
+		name                 string
+		token                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			token: "todo_pat_secret",
+			mockBehavior: func(s *mock_service.MockAccessTokens, auth *mock_service.MockAuthorization, token string) {
+				s.EXPECT().Authenticate(token).Return(1, []string{todo.ScopeListsRead}, nil)
+				auth.EXPECT().CheckUser(1).Return(false, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "1 [lists:read]",
+		},
+		{
+			name:  "Disabled User",
+			token: "todo_pat_secret",
+			mockBehavior: func(s *mock_service.MockAccessTokens, auth *mock_service.MockAuthorization, token string) {
+				s.EXPECT().Authenticate(token).Return(1, []string{todo.ScopeListsRead}, nil)
+				auth.EXPECT().CheckUser(1).Return(false, service.ErrAccountDisabled)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"account is disabled"}`,
+		},
+		{
+			name:  "Invalid Token",
+			token: "todo_pat_secret",
+			mockBehavior: func(s *mock_service.MockAccessTokens, auth *mock_service.MockAuthorization, token string) {
+				s.EXPECT().Authenticate(token).Return(0, nil, service.ErrInvalidAccessToken)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"invalid access token"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tokens := mock_service.NewMockAccessTokens(c)
+			auth := mock_service.NewMockAuthorization(c)
+			testCase.mockBehavior(tokens, auth, testCase.token)
+
+			// JWT для персонального токена не разбирается, проверяется только пользователь
+			services := &service.Service{Authorization: auth, AccessTokens: tokens}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/protected", handler.userIdentity, func(c *gin.Context) {
+				id, _ := c.Get(userCtx)
+				scopes, _ := c.Get(scopesCtx)
+				c.String(200, fmt.Sprintf("%d %v", id.(int), scopes))
+			})
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", "Bearer "+testCase.token)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			//Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_requireScope(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		scopes               []string // nil - токен входа
+		required             string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Sign-In Token",
+			required:             todo.ScopeAccount,
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Granted",
+			scopes:               []string{todo.ScopeItemsRead},
+			required:             todo.ScopeItemsRead,
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Write Implies Read",
+			scopes:               []string{todo.ScopeListsWrite},
+			required:             todo.ScopeListsRead,
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Read Does Not Imply Write",
+			scopes:               []string{todo.ScopeListsRead},
+			required:             todo.ScopeListsWrite,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"token scope lists:write required"}`,
+		},
+		{
+			name:                 "Account Routes",
+			scopes:               todo.AccessTokenScopes,
+			required:             todo.ScopeAccount,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"token scope account required"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(&service.Service{})
+
+			// Test Server
+			r := gin.New()
+			r.GET("/protected", func(c *gin.Context) {
+				if testCase.scopes != nil {
+					c.Set(scopesCtx, testCase.scopes)
+				}
+			}, handler.requireScope(testCase.required), func(c *gin.Context) {
+				c.String(200, "ok")
+			})
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/protected", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			//Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_requireAdmin(t *testing.T) {
+	testTable := []struct {
+		name                 string
+		isAdmin              interface{} // nil - признак не установлен
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:                 "Admin",
+			isAdmin:              true,
+			expectedStatusCode:   200,
+			expectedResponseBody: "ok",
+		},
+		{
+			name:                 "Not Admin",
+			isAdmin:              false,
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"administrator role required"}`,
+		},
+		{
+			name:                 "Not Set",
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"administrator role required"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := NewHandler(&service.Service{})
+
+			// Test Server
+			r := gin.New()
+			r.GET("/admin", func(c *gin.Context) {
+				if testCase.isAdmin != nil {
+					c.Set(adminCtx, testCase.isAdmin)
+				}
+			}, handler.requireAdmin, func(c *gin.Context) {
+				c.String(200, "ok")
+			})
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/admin", nil)
+
+			// Make Request
+			r.ServeHTTP(w, req)
+
+			//Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
 func TestHandler_getUserId(t *testing.T) {
 
 	var getContext = func(id interface{}) *gin.Context { // функция записи id в контекст
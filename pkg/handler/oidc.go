@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getOIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// @Summary OIDC Providers
+// @Tags auth
+// @Description names of configured OpenID Connect providers
+// @ID oidc-providers
+// @Produce json
+// @Success 200 {object} getOIDCProvidersResponse
+// @Failure default {object} errorResponse
+// @Router /auth/oidc/providers [get]
+func (h *Handler) getOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, getOIDCProvidersResponse{Providers: h.services.OIDC.Providers()})
+}
+
+// @Summary OIDC Login
+// @Tags auth
+// @Description redirect to the sign-in page of an OpenID Connect provider
+// @ID oidc-login
+// @Param provider query string true "provider name"
+// @Param device_name query string false "device name for the sessions list"
+// @Success 302
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/oidc/login [get]
+func (h *Handler) oidcLogin(c *gin.Context) {
+	var input todo.OIDCLoginInput
+
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid query params: %s", err.Error()))
+		return
+	}
+
+	authURL, err := h.services.OIDC.LoginURL(input.Provider, input.DeviceName)
+	if errors.Is(err, service.ErrUnknownOIDCProvider) {
+		newErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// @Summary OIDC Callback
+// @Tags auth
+// @Description return from the provider: the account is linked or created and the app's tokens are issued
+// @ID oidc-callback
+// @Produce json
+// @Param state query string true "state from the login redirect"
+// @Param code query string false "authorization code"
+// @Success 200 {object} todo.Tokens "tokens, or twoFactorChallengeResponse when two-factor authentication is enabled"
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /auth/oidc/callback [get]
+func (h *Handler) oidcCallback(c *gin.Context) {
+	var input todo.OIDCCallbackInput
+
+	if err := c.BindQuery(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid query params: %s", err.Error()))
+		return
+	}
+
+	tokens, err := h.services.OIDC.Callback(input, todo.SessionClient{
+		UserAgent: c.Request.UserAgent(),
+		Ip:        c.ClientIP(),
+	})
+	if errors.Is(err, service.ErrInvalidOIDCState) || errors.Is(err, service.ErrUnknownOIDCProvider) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrOIDCLoginFailed) {
+		newErrorResponse(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrOIDCAccountNotLinked) || errors.Is(err, service.ErrAccountDisabled) {
+		newErrorResponse(c, http.StatusForbidden, err.Error())
+		return
+	}
+	var challenge *service.TwoFactorRequiredError
+	if errors.As(err, &challenge) {
+		c.JSON(http.StatusOK, twoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge.ChallengeToken,
+			ExpiresIn:         challenge.ExpiresIn,
+		})
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_oidc(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOIDC)
+
+	client := todo.SessionClient{Ip: "192.0.2.1"}
+
+	testTable := []struct {
+		name                 string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedLocation     string
+	}{
+		{
+			name: "Providers",
+			path: "/providers",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Providers().Return([]string{"corp", "google"})
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"providers":["corp","google"]}`,
+		},
+		{
+			name: "Login",
+			path: "/login?provider=corp&device_name=laptop",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().LoginURL("corp", "laptop").Return("https://sso.example.com/authorize?state=abc", nil)
+			},
+			expectedStatusCode: 302,
+			expectedLocation:   "https://sso.example.com/authorize?state=abc",
+		},
+		{
+			name:                 "Login No Provider",
+			path:                 "/login",
+			mockBehavior:         func(s *mock_service.MockOIDC) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params: Key: 'OIDCLoginInput.Provider' Error:Field validation for 'Provider' failed on the 'required' tag"}`,
+		},
+		{
+			name: "Login Unknown Provider",
+			path: "/login?provider=other",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().LoginURL("other", "").Return("", service.ErrUnknownOIDCProvider)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"unknown oidc provider"}`,
+		},
+		{
+			name: "Callback",
+			path: "/callback?state=abc&code=xyz",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Callback(todo.OIDCCallbackInput{State: "abc", Code: "xyz"}, client).
+					Return(todo.Tokens{AccessToken: "token", RefreshToken: "refresh", ExpiresIn: 900}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"token":"token","refresh_token":"refresh","expires_in":900}`,
+		},
+		{
+			name: "Callback Two-Factor Required",
+			path: "/callback?state=abc&code=xyz",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Callback(todo.OIDCCallbackInput{State: "abc", Code: "xyz"}, client).
+					Return(todo.Tokens{}, &service.TwoFactorRequiredError{ChallengeToken: "challenge", ExpiresIn: 300})
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"two_factor_required":true,"challenge_token":"challenge","expires_in":300}`,
+		},
+		{
+			name: "Callback Invalid State",
+			path: "/callback?state=abc&code=xyz",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Callback(todo.OIDCCallbackInput{State: "abc", Code: "xyz"}, client).
+					Return(todo.Tokens{}, service.ErrInvalidOIDCState)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid or expired oidc state"}`,
+		},
+		{
+			name: "Callback Denied",
+			path: "/callback?state=abc&error=access_denied",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Callback(todo.OIDCCallbackInput{State: "abc", Error: "access_denied"}, client).
+					Return(todo.Tokens{}, service.ErrOIDCLoginFailed)
+			},
+			expectedStatusCode:   401,
+			expectedResponseBody: `{"message":"oidc login failed"}`,
+		},
+		{
+			name: "Callback Not Linked",
+			path: "/callback?state=abc&code=xyz",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Callback(todo.OIDCCallbackInput{State: "abc", Code: "xyz"}, client).
+					Return(todo.Tokens{}, service.ErrOIDCAccountNotLinked)
+			},
+			expectedStatusCode:   403,
+			expectedResponseBody: `{"message":"no account is linked to this identity"}`,
+		},
+		{
+			name: "Callback Service Failure",
+			path: "/callback?state=abc&code=xyz",
+			mockBehavior: func(s *mock_service.MockOIDC) {
+				s.EXPECT().Callback(todo.OIDCCallbackInput{State: "abc", Code: "xyz"}, client).
+					Return(todo.Tokens{}, errors.New("service failure"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"service failure"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			oidc := mock_service.NewMockOIDC(c)
+			testCase.mockBehavior(oidc)
+
+			services := &service.Service{OIDC: oidc}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/providers", handler.getOIDCProviders)
+			r.GET("/login", handler.oidcLogin)
+			r.GET("/callback", handler.oidcCallback)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", testCase.path, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			if testCase.expectedLocation != "" {
+				assert.Equal(t, testCase.expectedLocation, w.Header().Get("Location"))
+			} else {
+				assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getAllRemindersResponse struct {
+	Data []todo.Reminder `json:"data"`
+}
+
+// @Summary Create Reminder
+// @Security ApiKeyAuth
+// @Tags reminders
+// @Description create reminder for item (remind_at - absolute time, offset_minutes - minutes before due date)
+// @ID create-reminder
+// @Accept json
+// @Produce json
+// @Param id path int true "Item Id"
+// @Param input body todo.Reminder true "Reminder info"
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/reminders [post]
+func (h *Handler) createReminder(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	var input todo.Reminder
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.Reminders.Create(userId, itemId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary Get Item Reminders
+// @Security ApiKeyAuth
+// @Tags reminders
+// @Description get reminders of item
+// @ID get-item-reminders
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Item Id"
+// @Success 200 {object} getAllRemindersResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/reminders [get]
+func (h *Handler) getItemReminders(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	reminders, err := h.services.Reminders.GetByItem(userId, itemId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllRemindersResponse{
+		Data: reminders,
+	})
+}
+
+// @Summary Delete Reminder
+// @Security ApiKeyAuth
+// @Tags reminders
+// @Description delete reminder
+// @ID delete-reminder
+// @Accept json
+// @Produce json
+// @Param id path int true "Item Id"
+// @Param reminderId path int true "Reminder Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/reminders/{reminderId} [delete]
+func (h *Handler) deleteReminder(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	reminderId, err := strconv.Atoi(c.Param("reminderId"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid reminder id param")
+		return
+	}
+
+	if err := h.services.Reminders.Delete(userId, reminderId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
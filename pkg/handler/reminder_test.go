@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createReminder(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder)
+
+	offset := 30
+
+	testTable := []struct {
+		name                 string
+		userId               int
+		itemId               string
+		inputBody            string
+		inputReminder        todo.Reminder
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:          "OK",
+			userId:        1,
+			itemId:        "2",
+			inputBody:     `{"offset_minutes":30,"channel":"email","target":"user@example.com"}`,
+			inputReminder: todo.Reminder{OffsetMinutes: &offset, Channel: "email", Target: "user@example.com"},
+			mockBehavior: func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder) {
+				s.EXPECT().Create(userId, itemId, reminder).Return(5, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":5}`,
+		},
+		{
+			name:          "Email With Name",
+			userId:        1,
+			itemId:        "2",
+			inputBody:     `{"offset_minutes":30,"channel":"email","target":"Alex <user@example.com>"}`,
+			inputReminder: todo.Reminder{OffsetMinutes: &offset, Channel: "email", Target: "user@example.com"},
+			mockBehavior: func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder) {
+				s.EXPECT().Create(userId, itemId, reminder).Return(5, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":5}`,
+		},
+		{
+			name:                 "Invalid Item Id",
+			userId:               1,
+			itemId:               "a",
+			inputBody:            `{"offset_minutes":30,"channel":"email","target":"user@example.com"}`,
+			mockBehavior:         func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid item id param"}`,
+		},
+		{
+			name:                 "No Time",
+			userId:               1,
+			itemId:               "2",
+			inputBody:            `{"channel":"email","target":"user@example.com"}`,
+			mockBehavior:         func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"reminder requires either remind_at or offset_minutes"}`,
+		},
+		{
+			name:                 "Invalid Webhook",
+			userId:               1,
+			itemId:               "2",
+			inputBody:            `{"offset_minutes":30,"channel":"webhook","target":"ftp://example.com"}`,
+			mockBehavior:         func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid webhook target"}`,
+		},
+		{
+			name:          "Error Create",
+			userId:        1,
+			itemId:        "2",
+			inputBody:     `{"offset_minutes":30,"channel":"webhook","target":"https://example.com/hook"}`,
+			inputReminder: todo.Reminder{OffsetMinutes: &offset, Channel: "webhook", Target: "https://example.com/hook"},
+			mockBehavior: func(s *mock_service.MockReminders, userId, itemId int, reminder todo.Reminder) {
+				s.EXPECT().Create(userId, itemId, reminder).Return(0, errors.New("offset reminder requires item with due date"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"offset reminder requires item with due date"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reminders := mock_service.NewMockReminders(c)
+			testCase.mockBehavior(reminders, testCase.userId, 2, testCase.inputReminder)
+
+			services := &service.Service{Reminders: reminders}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/items/:id/reminders", func(c *gin.Context) { c.Set(userCtx, testCase.userId) }, handler.createReminder)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/items/"+testCase.itemId+"/reminders", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteReminder(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockReminders)
+
+	testTable := []struct {
+		name                 string
+		reminderId           string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "OK",
+			reminderId: "5",
+			mockBehavior: func(s *mock_service.MockReminders) {
+				s.EXPECT().Delete(1, 5).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Reminder Id",
+			reminderId:           "a",
+			mockBehavior:         func(s *mock_service.MockReminders) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid reminder id param"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			reminders := mock_service.NewMockReminders(c)
+			testCase.mockBehavior(reminders)
+
+			services := &service.Service{Reminders: reminders}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.DELETE("/items/:id/reminders/:reminderId", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.deleteReminder)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/items/2/reminders/"+testCase.reminderId, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
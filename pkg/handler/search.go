@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getSearchResponse struct {
+	Data []todo.SearchResult `json:"data"`
+}
+
+// @Summary Search
+// @Security ApiKeyAuth
+// @Tags search
+// @Description full-text search in titles and descriptions of accessible lists and items (russian and english stemming)
+// @Description matches in title and snippet are wrapped in <b></b>, the rest of the text is html-escaped
+// @ID search
+// @Produce  json
+// @Param q query string true "search words: \"phrase\", OR, -word are supported"
+// @Param limit query int false "page size (default 20, max 100)"
+// @Param offset query int false "offset"
+// @Success 200 {object} getSearchResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/search [get]
+func (h *Handler) search(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var query todo.SearchQuery
+	if err := c.BindQuery(&query); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid query params: %s", err.Error()))
+		return
+	}
+
+	results, err := h.services.Search.Search(userId, query)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getSearchResponse{
+		Data: results,
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_search(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockSearch)
+
+	testTable := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?q=%D0%BF%D0%BE%D0%BA%D1%83%D0%BF%D0%BA%D0%B8&limit=10&offset=20",
+			mockBehavior: func(s *mock_service.MockSearch) {
+				s.EXPECT().Search(1, todo.SearchQuery{Query: "покупки", Limit: 10, Offset: 20}).Return([]todo.SearchResult{
+					{Type: todo.SearchTypeList, Id: 2, ListId: 2, Title: "<b>Покупки</b>", Rank: 0.6},
+					{Type: todo.SearchTypeItem, Id: 5, ListId: 2, ListTitle: "Покупки", Title: "Молоко",
+						Snippet: "не забыть при <b>покупке</b>", Rank: 0.2},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{"data":[{"type":"list","id":2,"list_id":2,"title":"\u003cb\u003eПокупки\u003c/b\u003e","rank":0.6},` +
+				`{"type":"item","id":5,"list_id":2,"list_title":"Покупки","title":"Молоко",` +
+				`"snippet":"не забыть при \u003cb\u003eпокупке\u003c/b\u003e","rank":0.2}]}`,
+		},
+		{
+			name:                 "Empty Query",
+			mockBehavior:         func(s *mock_service.MockSearch) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params: Key: 'SearchQuery.Query' Error:Field validation for 'Query' failed on the 'required' tag"}`,
+		},
+		{
+			name:                 "Invalid Offset",
+			query:                "?q=milk&offset=-1",
+			mockBehavior:         func(s *mock_service.MockSearch) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid query params: Key: 'SearchQuery.Offset' Error:Field validation for 'Offset' failed on the 'min' tag"}`,
+		},
+		{
+			name:  "Service Failure",
+			query: "?q=milk",
+			mockBehavior: func(s *mock_service.MockSearch) {
+				s.EXPECT().Search(1, todo.SearchQuery{Query: "milk"}).Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			search := mock_service.NewMockSearch(c)
+			testCase.mockBehavior(search)
+
+			services := &service.Service{Search: search}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/api/search", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+			}, handler.search)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/api/search"+testCase.query, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
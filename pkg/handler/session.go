@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getSessionsResponse struct {
+	Data []todo.Session `json:"data"`
+}
+
+// @Summary Get Sessions
+// @Security ApiKeyAuth
+// @Tags sessions
+// @Description get active sessions (devices) of the user
+// @ID get-sessions
+// @Produce  json
+// @Success 200 {object} getSessionsResponse
+// @Failure 401 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/sessions [get]
+func (h *Handler) getSessions(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sessionId, err := getSessionId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sessions, err := h.services.Sessions.GetAll(userId, sessionId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getSessionsResponse{
+		Data: sessions,
+	})
+}
+
+// @Summary Delete Session
+// @Security ApiKeyAuth
+// @Tags sessions
+// @Description log out session (device) of the user
+// @ID delete-session
+// @Produce  json
+// @Param id path int true "Session Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/sessions/{id} [delete]
+func (h *Handler) deleteSession(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sessionId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid session id param")
+		return
+	}
+
+	if err := h.services.Sessions.Revoke(userId, sessionId); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			newErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
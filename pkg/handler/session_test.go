@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getSessions(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockSessions)
+
+	seen := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(s *mock_service.MockSessions) {
+				s.EXPECT().GetAll(1, 5).Return([]todo.Session{
+					{Id: 5, DeviceName: "Pixel 6", UserAgent: "okhttp/4.9", Ip: "192.0.2.1", CreatedAt: seen, LastSeenAt: seen, Current: true},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":5,"device_name":"Pixel 6","user_agent":"okhttp/4.9","ip":"192.0.2.1","created_at":"2022-06-11T09:00:00Z","last_seen_at":"2022-06-11T09:00:00Z","current":true}]}`,
+		},
+		{
+			name: "Service Failure",
+			mockBehavior: func(s *mock_service.MockSessions) {
+				s.EXPECT().GetAll(1, 5).Return(nil, errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessions := mock_service.NewMockSessions(c)
+			testCase.mockBehavior(sessions)
+
+			services := &service.Service{Sessions: sessions}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/me/sessions", func(c *gin.Context) {
+				c.Set(userCtx, 1)
+				c.Set(sessionCtx, 5)
+			}, handler.getSessions)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/me/sessions", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_deleteSession(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockSessions)
+
+	testTable := []struct {
+		name                 string
+		sessionId            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			sessionId: "7",
+			mockBehavior: func(s *mock_service.MockSessions) {
+				s.EXPECT().Revoke(1, 7).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Session Id",
+			sessionId:            "a",
+			mockBehavior:         func(s *mock_service.MockSessions) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid session id param"}`,
+		},
+		{
+			name:      "Not Found",
+			sessionId: "7",
+			mockBehavior: func(s *mock_service.MockSessions) {
+				s.EXPECT().Revoke(1, 7).Return(service.ErrSessionNotFound)
+			},
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"session not found"}`,
+		},
+		{
+			name:      "Service Failure",
+			sessionId: "7",
+			mockBehavior: func(s *mock_service.MockSessions) {
+				s.EXPECT().Revoke(1, 7).Return(errors.New("something went wrong"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"something went wrong"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			sessions := mock_service.NewMockSessions(c)
+			testCase.mockBehavior(sessions)
+
+			services := &service.Service{Sessions: sessions}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.DELETE("/me/sessions/:id", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.deleteSession)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("DELETE", "/me/sessions/"+testCase.sessionId, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create Tag
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description create tag
+// @ID create-tag
+// @Accept json
+// @Produce json
+// @Param input body todo.Tag true "Tag info"
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/tags [post]
+func (h *Handler) createTag(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.Tag
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.Tags.Create(userId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+type getAllTagsResponse struct {
+	Data []todo.Tag `json:"data"`
+}
+
+// @Summary Get All Tags
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description get all tags of user
+// @ID get-all-tags
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} getAllTagsResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/tags [get]
+func (h *Handler) getAllTags(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	tags, err := h.services.Tags.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllTagsResponse{
+		Data: tags,
+	})
+}
+
+// @Summary Get Tag By Id
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description get tag by id
+// @ID get-tag-by-id
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tag Id"
+// @Success 200 {object} todo.Tag
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/tags/{id} [get]
+func (h *Handler) getTagById(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid tag id param")
+		return
+	}
+
+	tag, err := h.services.Tags.GetById(userId, id)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// @Summary Update Tag
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description update tag
+// @ID update-tag
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tag Id"
+// @Param input body todo.UpdateTagInput true "New tag options"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/tags/{id} [put]
+func (h *Handler) updateTag(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid tag id param")
+		return
+	}
+
+	var input todo.UpdateTagInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.services.Tags.Update(userId, id, input); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Delete Tag
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description delete tag by id (tag is removed from all items)
+// @ID delete-tag
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/tags/{id} [delete]
+func (h *Handler) deleteTag(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid tag id param")
+		return
+	}
+
+	if err := h.services.Tags.Delete(userId, id); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Get Item Tags
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description get tags attached to item
+// @ID get-item-tags
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Item Id"
+// @Success 200 {object} getAllTagsResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/tags [get]
+func (h *Handler) getItemTags(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	itemId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return
+	}
+
+	tags, err := h.services.Tags.GetByItem(userId, itemId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllTagsResponse{
+		Data: tags,
+	})
+}
+
+// @Summary Attach Tag
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description attach tag to item
+// @ID attach-tag
+// @Accept json
+// @Produce json
+// @Param id path int true "Item Id"
+// @Param tagId path int true "Tag Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/tags/{tagId} [post]
+func (h *Handler) attachTag(c *gin.Context) {
+	userId, itemId, tagId, ok := parseItemTagParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Tags.Attach(userId, itemId, tagId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Detach Tag
+// @Security ApiKeyAuth
+// @Tags tags
+// @Description detach tag from item
+// @ID detach-tag
+// @Accept json
+// @Produce json
+// @Param id path int true "Item Id"
+// @Param tagId path int true "Tag Id"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items/{id}/tags/{tagId} [delete]
+func (h *Handler) detachTag(c *gin.Context) {
+	userId, itemId, tagId, ok := parseItemTagParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.services.Tags.Detach(userId, itemId, tagId); err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// Разбор userId из контекста и id задачи и метки из URL. При ошибке ответ уже отправлен
+func parseItemTagParams(c *gin.Context) (userId, itemId, tagId int, ok bool) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return 0, 0, 0, false
+	}
+
+	itemId, err = strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid item id param")
+		return 0, 0, 0, false
+	}
+
+	tagId, err = strconv.Atoi(c.Param("tagId"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid tag id param")
+		return 0, 0, 0, false
+	}
+
+	return userId, itemId, tagId, true
+}
+
+// @Summary Get Items By Tags
+// @Security ApiKeyAuth
+// @Tags items
+// @Description get items from all lists marked with tags
+// @ID get-items-by-tags
+// @Accept  json
+// @Produce  json
+// @Param tag query string true "comma separated tag names, e.g. work,urgent"
+// @Param match query string false "all (default) - item has every tag, any - item has at least one" Enums(all, any)
+// @Success 200 {object} []todo.TodoItem
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/items [get]
+func (h *Handler) getItemsByTags(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	names := c.Query("tag")
+	if names == "" {
+		newErrorResponse(c, http.StatusBadRequest, "empty tag param")
+		return
+	}
+
+	matchAll := true
+	switch c.DefaultQuery("match", "all") {
+	case "all":
+	case "any":
+		matchAll = false
+	default:
+		newErrorResponse(c, http.StatusBadRequest, "invalid match param")
+		return
+	}
+
+	items, err := h.services.Tags.GetItems(userId, names, matchAll)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
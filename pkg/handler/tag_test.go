@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_createTag(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTags, userId int, tag todo.Tag)
+
+	testTable := []struct {
+		name                 string
+		userId               int
+		inputBody            string
+		inputTag             todo.Tag
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			userId:    1,
+			inputBody: `{"name":"work","color":"#ff0000"}`,
+			inputTag:  todo.Tag{Name: "work", Color: "#ff0000"},
+			mockBehavior: func(s *mock_service.MockTags, userId int, tag todo.Tag) {
+				s.EXPECT().Create(userId, tag).Return(3, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"id":3}`,
+		},
+		{
+			name:                 "Empty Name",
+			userId:               1,
+			inputBody:            `{"color":"#ff0000"}`,
+			mockBehavior:         func(s *mock_service.MockTags, userId int, tag todo.Tag) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Key: 'Tag.Name' Error:Field validation for 'Name' failed on the 'required' tag"}`,
+		},
+		{
+			name:                 "Comma In Name",
+			userId:               1,
+			inputBody:            `{"name":"work,home"}`,
+			mockBehavior:         func(s *mock_service.MockTags, userId int, tag todo.Tag) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"tag name must not contain commas"}`,
+		},
+		{
+			name:                 "Long Color",
+			userId:               1,
+			inputBody:            `{"name":"work","color":"rgba(255, 0, 0, 0.5)"}`,
+			mockBehavior:         func(s *mock_service.MockTags, userId int, tag todo.Tag) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"tag color is too long"}`,
+		},
+		{
+			name:      "Error Create",
+			userId:    1,
+			inputBody: `{"name":"work"}`,
+			inputTag:  todo.Tag{Name: "work"},
+			mockBehavior: func(s *mock_service.MockTags, userId int, tag todo.Tag) {
+				s.EXPECT().Create(userId, tag).Return(0, errors.New("Error Create"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error Create"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tags := mock_service.NewMockTags(c)
+			testCase.mockBehavior(tags, testCase.userId, testCase.inputTag)
+
+			services := &service.Service{Tags: tags}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/tags", func(c *gin.Context) { c.Set(userCtx, testCase.userId) }, handler.createTag)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/tags", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_attachTag(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTags)
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			url:  "/items/5/tags/7",
+			mockBehavior: func(s *mock_service.MockTags) {
+				s.EXPECT().Attach(1, 5, 7).Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Item Id",
+			url:                  "/items/err/tags/7",
+			mockBehavior:         func(s *mock_service.MockTags) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid item id param"}`,
+		},
+		{
+			name:                 "Invalid Tag Id",
+			url:                  "/items/5/tags/err",
+			mockBehavior:         func(s *mock_service.MockTags) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid tag id param"}`,
+		},
+		{
+			name: "Error Attach",
+			url:  "/items/5/tags/7",
+			mockBehavior: func(s *mock_service.MockTags) {
+				s.EXPECT().Attach(1, 5, 7).Return(errors.New("sql: no rows in result set"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tags := mock_service.NewMockTags(c)
+			testCase.mockBehavior(tags)
+
+			services := &service.Service{Tags: tags}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/items/:id/tags/:tagId", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.attachTag)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", testCase.url, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_getItemsByTags(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTags)
+
+	testTable := []struct {
+		name                 string
+		url                  string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			url:  "/items?tag=work,urgent",
+			mockBehavior: func(s *mock_service.MockTags) {
+				s.EXPECT().GetItems(1, "work,urgent", true).Return([]todo.TodoItem{
+					{Id: 3, Title: "report", ListId: 2},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":3,"title":"report","description":"","done":false,"list_id":2}]`,
+		},
+		{
+			name: "OK Match Any",
+			url:  "/items?tag=work,urgent&match=any",
+			mockBehavior: func(s *mock_service.MockTags) {
+				s.EXPECT().GetItems(1, "work,urgent", false).Return([]todo.TodoItem{}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "Empty Tag Param",
+			url:                  "/items",
+			mockBehavior:         func(s *mock_service.MockTags) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"empty tag param"}`,
+		},
+		{
+			name:                 "Invalid Match Param",
+			url:                  "/items?tag=work&match=some",
+			mockBehavior:         func(s *mock_service.MockTags) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid match param"}`,
+		},
+		{
+			name: "Error GetItems",
+			url:  "/items?tag=work",
+			mockBehavior: func(s *mock_service.MockTags) {
+				s.EXPECT().GetItems(1, "work", true).Return(nil, errors.New("Error GetItems"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"Error GetItems"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			tags := mock_service.NewMockTags(c)
+			testCase.mockBehavior(tags)
+
+			services := &service.Service{Tags: tags}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/items", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getItemsByTags)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", testCase.url, nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
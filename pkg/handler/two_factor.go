@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"todo-app"
+	"todo-app/pkg/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"` // показываются один раз
+}
+
+// @Summary Enroll Two-Factor
+// @Security ApiKeyAuth
+// @Tags two-factor
+// @Description start TOTP enrolment: returns secret and otpauth URI for authenticator app
+// @ID enroll-two-factor
+// @Produce  json
+// @Success 200 {object} todo.TwoFactorEnrollment
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/2fa/enroll [post]
+func (h *Handler) enrollTwoFactor(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	enrollment, err := h.services.TwoFactor.Enroll(userId)
+	if errors.Is(err, service.ErrTwoFactorEnabled) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// @Summary Confirm Two-Factor
+// @Security ApiKeyAuth
+// @Tags two-factor
+// @Description enable two-factor authentication with the first code from authenticator app, returns recovery codes
+// @ID confirm-two-factor
+// @Accept  json
+// @Produce  json
+// @Param input body todo.TwoFactorCodeInput true "TOTP code"
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/2fa/confirm [post]
+func (h *Handler) confirmTwoFactor(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.TwoFactorCodeInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	codes, err := h.services.TwoFactor.Confirm(userId, input.Code)
+	if errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, service.ErrTwoFactorEnabled) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, recoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// @Summary Disable Two-Factor
+// @Security ApiKeyAuth
+// @Tags two-factor
+// @Description disable two-factor authentication, requires TOTP or recovery code
+// @ID disable-two-factor
+// @Accept  json
+// @Produce  json
+// @Param input body todo.TwoFactorCodeInput true "TOTP or recovery code"
+// @Success 200 {object} statusResponse
+// @Failure 400,401,403 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/me/2fa/disable [post]
+func (h *Handler) disableTwoFactor(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.TwoFactorCodeInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.services.TwoFactor.Disable(userId, input.Code)
+	if errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, service.ErrTwoFactorNotEnabled) {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_twoFactor(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockTwoFactor)
+
+	testTable := []struct {
+		name                 string
+		path                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Enroll",
+			path: "/enroll",
+			mockBehavior: func(s *mock_service.MockTwoFactor) {
+				s.EXPECT().Enroll(1).Return(todo.TwoFactorEnrollment{
+					Secret: "JBSWY3DPEHPK3PXP",
+					URI:    "otpauth://totp/Todo%20App:alex?secret=JBSWY3DPEHPK3PXP",
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"secret":"JBSWY3DPEHPK3PXP","uri":"otpauth://totp/Todo%20App:alex?secret=JBSWY3DPEHPK3PXP"}`,
+		},
+		{
+			name: "Enroll Already Enabled",
+			path: "/enroll",
+			mockBehavior: func(s *mock_service.MockTwoFactor) {
+				s.EXPECT().Enroll(1).Return(todo.TwoFactorEnrollment{}, service.ErrTwoFactorEnabled)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"two-factor authentication already enabled"}`,
+		},
+		{
+			name:      "Confirm",
+			path:      "/confirm",
+			inputBody: `{"code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockTwoFactor) {
+				s.EXPECT().Confirm(1, "123456").Return([]string{"abcde-fghij", "klmno-pqrst"}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"recovery_codes":["abcde-fghij","klmno-pqrst"]}`,
+		},
+		{
+			name:      "Confirm Invalid Code",
+			path:      "/confirm",
+			inputBody: `{"code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockTwoFactor) {
+				s.EXPECT().Confirm(1, "123456").Return(nil, service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid two-factor code"}`,
+		},
+		{
+			name:      "Disable",
+			path:      "/disable",
+			inputBody: `{"code":"abcde-fghij"}`,
+			mockBehavior: func(s *mock_service.MockTwoFactor) {
+				s.EXPECT().Disable(1, "abcde-fghij").Return(nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:      "Disable Not Enabled",
+			path:      "/disable",
+			inputBody: `{"code":"123456"}`,
+			mockBehavior: func(s *mock_service.MockTwoFactor) {
+				s.EXPECT().Disable(1, "123456").Return(service.ErrTwoFactorNotEnabled)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"two-factor authentication is not enabled"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			twoFactor := mock_service.NewMockTwoFactor(c)
+			testCase.mockBehavior(twoFactor)
+
+			services := &service.Service{TwoFactor: twoFactor}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			setUser := func(c *gin.Context) { c.Set(userCtx, 1) }
+			r.POST("/enroll", setUser, handler.enrollTwoFactor)
+			r.POST("/confirm", setUser, handler.confirmTwoFactor)
+			r.POST("/disable", setUser, handler.disableTwoFactor)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", testCase.path, bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
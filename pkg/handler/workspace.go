@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+)
+
+type getAllWorkspacesResponse struct {
+	Data []todo.Workspace `json:"data"`
+}
+
+// @Summary Create Workspace
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description create workspace, creator becomes its owner
+// @ID create-workspace
+// @Accept  json
+// @Produce  json
+// @Param input body todo.Workspace true "Workspace name"
+// @Success 200 {integer} integer 1
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/workspaces [post]
+func (h *Handler) createWorkspace(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var input todo.Workspace
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id, err := h.services.Workspaces.Create(userId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id": id,
+	})
+}
+
+// @Summary Get All Workspaces
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description get workspaces of user with his role, personal workspace first
+// @ID get-all-workspaces
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} getAllWorkspacesResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/workspaces [get]
+func (h *Handler) getAllWorkspaces(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	workspaces, err := h.services.Workspaces.GetAll(userId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllWorkspacesResponse{
+		Data: workspaces,
+	})
+}
+
+// @Summary Get Workspace Lists
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description get lists of workspace available to user
+// @ID get-workspace-lists
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Workspace Id"
+// @Success 200 {object} getAllListsResponce
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/workspaces/{id}/lists [get]
+func (h *Handler) getWorkspaceLists(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	workspaceId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid workspace id param")
+		return
+	}
+
+	lists, err := h.services.Workspaces.GetLists(userId, workspaceId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getAllListsResponce{
+		Data: lists,
+	})
+}
+
+// @Summary Get Workspace Members
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description get members of workspace with their roles
+// @ID get-workspace-members
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Workspace Id"
+// @Success 200 {object} getListMembersResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/workspaces/{id}/members [get]
+func (h *Handler) getWorkspaceMembers(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	workspaceId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid workspace id param")
+		return
+	}
+
+	members, err := h.services.Workspaces.GetMembers(userId, workspaceId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, getListMembersResponse{
+		Data: members,
+	})
+}
+
+// @Summary Add Workspace Member
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description add user to workspace by username or change member role (owner only)
+// @ID add-workspace-member
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Workspace Id"
+// @Param input body todo.AddMemberInput true "Member username and role"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/workspaces/{id}/members [post]
+func (h *Handler) addWorkspaceMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	workspaceId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid workspace id param")
+		return
+	}
+
+	var input todo.AddMemberInput
+	if err := c.BindJSON(&input); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		newErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	memberId, err := h.services.Workspaces.AddMember(userId, workspaceId, input)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Участник получил списки пространства (или новую роль в них), поэтому удаляем все его данные из кэша
+	err = h.services.TodoListCach.Delete(memberId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
+
+// @Summary Remove Workspace Member
+// @Security ApiKeyAuth
+// @Tags workspaces
+// @Description remove member from workspace (owner) or leave workspace (own username)
+// @ID remove-workspace-member
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Workspace Id"
+// @Param username path string true "Member username"
+// @Success 200 {object} statusResponse
+// @Failure 400,404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Failure default {object} errorResponse
+// @Router /api/workspaces/{id}/members/{username} [delete]
+func (h *Handler) removeWorkspaceMember(c *gin.Context) {
+	userId, err := getUserId(c)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	workspaceId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		newErrorResponse(c, http.StatusBadRequest, "invalid workspace id param")
+		return
+	}
+
+	memberId, err := h.services.Workspaces.RemoveMember(userId, workspaceId, c.Param("username"))
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Удаляем все данные исключенного участника из кэша Redis, т.к. там остались списки пространства
+	err = h.services.TodoListCach.Delete(memberId)
+	if err != nil {
+		newErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, statusResponse{"ok"})
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"todo-app"
+	"todo-app/pkg/service"
+	mock_service "todo-app/pkg/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_getWorkspaceLists(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockWorkspaces)
+
+	testTable := []struct {
+		name                 string
+		workspaceId          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "OK",
+			workspaceId: "2",
+			mockBehavior: func(s *mock_service.MockWorkspaces) {
+				s.EXPECT().GetLists(1, 2).Return([]todo.TodoList{
+					{Id: 1, Title: "Sprint", Position: "V", Role: todo.ListRoleEditor, WorkspaceId: 2},
+				}, nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"data":[{"id":1,"title":"Sprint","description":"","position":"V","role":"editor","workspace_id":2}]}`,
+		},
+		{
+			name:                 "Invalid Workspace Id",
+			workspaceId:          "a",
+			mockBehavior:         func(s *mock_service.MockWorkspaces) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid workspace id param"}`,
+		},
+		{
+			name:        "Not Member",
+			workspaceId: "2",
+			mockBehavior: func(s *mock_service.MockWorkspaces) {
+				s.EXPECT().GetLists(1, 2).Return(nil, errors.New("sql: no rows in result set"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"sql: no rows in result set"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mock_service.NewMockWorkspaces(c)
+			testCase.mockBehavior(workspaces)
+
+			services := &service.Service{Workspaces: workspaces}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.GET("/workspaces/:id/lists", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.getWorkspaceLists)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/workspaces/"+testCase.workspaceId+"/lists", nil)
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
+
+func TestHandler_addWorkspaceMember(t *testing.T) {
+	type field struct {
+		workspaces *mock_service.MockWorkspaces
+		cache      *mock_service.MockTodoListCach
+	}
+
+	testTable := []struct {
+		name                 string
+		inputBody            string
+		prepare              func(f *field)
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "OK",
+			inputBody: `{"username":"bob","role":"editor"}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.workspaces.EXPECT().AddMember(1, 2, todo.AddMemberInput{Username: "bob", Role: todo.ListRoleEditor}).Return(5, nil),
+					f.cache.EXPECT().Delete(5).Return(nil),
+				)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+		{
+			name:                 "Invalid Role",
+			inputBody:            `{"username":"bob","role":"admin"}`,
+			prepare:              func(f *field) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"invalid role (allowed: owner, editor, viewer)"}`,
+		},
+		{
+			name:      "Personal Workspace",
+			inputBody: `{"username":"bob","role":"viewer"}`,
+			prepare: func(f *field) {
+				f.workspaces.EXPECT().AddMember(1, 2, todo.AddMemberInput{Username: "bob", Role: todo.ListRoleViewer}).Return(0, errors.New("cannot share personal workspace"))
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"cannot share personal workspace"}`,
+		},
+		{
+			name:      "Error Cache",
+			inputBody: `{"username":"bob","role":"viewer"}`,
+			prepare: func(f *field) {
+				gomock.InOrder(
+					f.workspaces.EXPECT().AddMember(1, 2, todo.AddMemberInput{Username: "bob", Role: todo.ListRoleViewer}).Return(5, nil),
+					f.cache.EXPECT().Delete(5).Return(errors.New("redis error")),
+				)
+			},
+			expectedStatusCode:   500,
+			expectedResponseBody: `{"message":"redis error"}`,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Init Deps
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			f := field{
+				workspaces: mock_service.NewMockWorkspaces(c),
+				cache:      mock_service.NewMockTodoListCach(c),
+			}
+			testCase.prepare(&f)
+
+			services := &service.Service{Workspaces: f.workspaces, TodoListCach: f.cache}
+			handler := NewHandler(services)
+
+			// Test Server
+			r := gin.New()
+			r.POST("/workspaces/:id/members", func(c *gin.Context) { c.Set(userCtx, 1) }, handler.addWorkspaceMember)
+
+			// Test Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/workspaces/2/members", bytes.NewBufferString(testCase.inputBody))
+
+			// Perform Request
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, testCase.expectedStatusCode, w.Code)
+			assert.Equal(t, testCase.expectedResponseBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Ключи подписи JWT: загрузка из конфигурации, подпись активным ключом,
+// проверка любым из настроенных ключей (для ротации) и публикация открытых ключей в формате JWKS
+
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Поддерживаемые алгоритмы подписи
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const minSecretSize = 32 // минимальная длина секрета HS256 в байтах
+
+// Описание ключа в конфигурации. Секрет HS256 читается из переменной окружения SecretEnv,
+// ключи RS256 и EdDSA - из PEM-файлов. Ключ только с открытой частью используется лишь для проверки
+type KeyConfig struct {
+	Id             string `mapstructure:"id"`
+	Alg            string `mapstructure:"alg"`
+	SecretEnv      string `mapstructure:"secret_env"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+type Config struct {
+	ActiveKey string // id ключа, которым подписываются новые токены
+	Keys      []KeyConfig
+}
+
+type key struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{} // nil - ключ только для проверки
+	verifyKey interface{}
+}
+
+type KeySet struct {
+	active *key
+	keys   map[string]*key
+	order  []*key // порядок из конфигурации для JWKS
+}
+
+func New(cfg Config) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*key)}
+
+	for _, keyCfg := range cfg.Keys {
+		if keyCfg.Id == "" {
+			return nil, errors.New("jwt key id is required")
+		}
+		if _, ok := set.keys[keyCfg.Id]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id %s", keyCfg.Id)
+		}
+
+		k, err := loadKey(keyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", keyCfg.Id, err)
+		}
+
+		set.keys[k.id] = k
+		set.order = append(set.order, k)
+	}
+
+	active, ok := set.keys[cfg.ActiveKey]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", cfg.ActiveKey)
+	}
+	if active.signKey == nil {
+		return nil, fmt.Errorf("active jwt key %s has no private part", active.id)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+func loadKey(cfg KeyConfig) (*key, error) {
+	k := &key{id: cfg.Id}
+
+	switch cfg.Alg {
+	case AlgHS256:
+		secret := os.Getenv(cfg.SecretEnv)
+		if cfg.SecretEnv == "" || secret == "" {
+			return nil, errors.New("secret_env must name non-empty environment variable")
+		}
+		if len(secret) < minSecretSize {
+			return nil, fmt.Errorf("secret must be at least %d bytes", minSecretSize)
+		}
+		k.method = jwt.SigningMethodHS256
+		k.signKey = []byte(secret)
+		k.verifyKey = []byte(secret)
+
+	case AlgRS256:
+		k.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyFile != "" {
+			data, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			k.signKey = privateKey
+			k.verifyKey = &privateKey.PublicKey
+		} else {
+			data, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			k.verifyKey = publicKey
+		}
+
+	case AlgEdDSA:
+		k.method = SigningMethodEdDSA
+		if cfg.PrivateKeyFile != "" {
+			parsed, err := parsePEMFile(cfg.PrivateKeyFile, x509.ParsePKCS8PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, ok := parsed.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("key is not a valid Ed25519 private key")
+			}
+			k.signKey = privateKey
+			k.verifyKey = privateKey.Public()
+		} else {
+			parsed, err := parsePEMFile(cfg.PublicKeyFile, x509.ParsePKIXPublicKey)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, ok := parsed.(ed25519.PublicKey)
+			if !ok {
+				return nil, errors.New("key is not a valid Ed25519 public key")
+			}
+			k.verifyKey = publicKey
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported alg %q (allowed: %s, %s, %s)", cfg.Alg, AlgHS256, AlgRS256, AlgEdDSA)
+	}
+
+	return k, nil
+}
+
+func parsePEMFile(path string, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("key must be PEM encoded")
+	}
+
+	return parse(block.Bytes)
+}
+
+// Подпись токена активным ключом, id ключа передается в заголовке kid
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.method, claims)
+	token.Header["kid"] = s.active.id
+
+	return token.SignedString(s.active.signKey)
+}
+
+// Проверка токена ключом из заголовка kid. Алгоритм токена должен совпадать с алгоритмом ключа
+func (s *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, s.keyfunc)
+}
+
+func (s *KeySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := s.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return k.verifyKey, nil
+}
+
+// Открытый ключ в формате JWK (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // модуль RSA
+	E   string `json:"e,omitempty"`   // экспонента RSA
+	Crv string `json:"crv,omitempty"` // кривая OKP
+	X   string `json:"x,omitempty"`   // открытый ключ OKP
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Открытые ключи всех асимметричных ключей набора (включая выведенные из оборота, пока они в конфигурации).
+// Симметричные ключи HS256 не публикуются
+func (s *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(s.order))}
+
+	for _, k := range s.order {
+		switch publicKey := k.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: k.id,
+				Use: "sig",
+				Alg: k.method.Alg(),
+				N:   jwt.EncodeSegment(publicKey.N.Bytes()),
+				E:   jwt.EncodeSegment(bigEndian(publicKey.E)),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: k.id,
+				Use: "sig",
+				Alg: k.method.Alg(),
+				Crv: "Ed25519",
+				X:   jwt.EncodeSegment(publicKey),
+			})
+		}
+	}
+
+	return set
+}
+
+// Целое без ведущих нулевых байт
+func bigEndian(n int) []byte {
+	var buf []byte
+	for ; n > 0; n >>= 8 {
+		buf = append([]byte{byte(n)}, buf...)
+	}
+	return buf
+}
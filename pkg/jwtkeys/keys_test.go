@@ -0,0 +1,216 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("failed to write %s: %s", name, err.Error())
+	}
+	return path
+}
+
+func rsaKeyFiles(t *testing.T) (privateFile, publicFile string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %s", err.Error())
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal rsa key: %s", err.Error())
+	}
+	return writePEM(t, "rs.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey)),
+		writePEM(t, "rs.pub.pem", "PUBLIC KEY", publicDer)
+}
+
+func ed25519KeyFiles(t *testing.T) (privateFile, publicFile string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 key: %s", err.Error())
+	}
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal ed25519 key: %s", err.Error())
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal ed25519 key: %s", err.Error())
+	}
+	return writePEM(t, "ed.pem", "PRIVATE KEY", privateDer), writePEM(t, "ed.pub.pem", "PUBLIC KEY", publicDer)
+}
+
+func claims() *jwt.StandardClaims {
+	return &jwt.StandardClaims{Subject: "7", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+}
+
+func TestKeySet_SignParse(t *testing.T) {
+	t.Setenv("TEST_JWT_SECRET", testSecret)
+	rsPrivate, _ := rsaKeyFiles(t)
+	edPrivate, _ := ed25519KeyFiles(t)
+
+	testTable := []struct {
+		name string
+		key  KeyConfig
+	}{
+		{name: "HS256", key: KeyConfig{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_SECRET"}},
+		{name: "RS256", key: KeyConfig{Id: "rs", Alg: AlgRS256, PrivateKeyFile: rsPrivate}},
+		{name: "EdDSA", key: KeyConfig{Id: "ed", Alg: AlgEdDSA, PrivateKeyFile: edPrivate}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			set, err := New(Config{ActiveKey: testCase.key.Id, Keys: []KeyConfig{testCase.key}})
+			assert.NoError(t, err)
+
+			token, err := set.Sign(claims())
+			assert.NoError(t, err)
+
+			parsed, err := set.Parse(token, &jwt.StandardClaims{})
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.key.Id, parsed.Header["kid"])
+			assert.Equal(t, testCase.key.Alg, parsed.Header["alg"])
+			assert.Equal(t, "7", parsed.Claims.(*jwt.StandardClaims).Subject)
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	t.Setenv("TEST_JWT_SECRET", testSecret)
+	edPrivate, edPublic := ed25519KeyFiles(t)
+
+	old, err := New(Config{ActiveKey: "ed-1", Keys: []KeyConfig{
+		{Id: "ed-1", Alg: AlgEdDSA, PrivateKeyFile: edPrivate},
+	}})
+	assert.NoError(t, err)
+
+	oldToken, err := old.Sign(claims())
+	assert.NoError(t, err)
+
+	// Новый ключ активен, старый остается только для проверки
+	rotated, err := New(Config{ActiveKey: "hs-2", Keys: []KeyConfig{
+		{Id: "hs-2", Alg: AlgHS256, SecretEnv: "TEST_JWT_SECRET"},
+		{Id: "ed-1", Alg: AlgEdDSA, PublicKeyFile: edPublic},
+	}})
+	assert.NoError(t, err)
+
+	_, err = rotated.Parse(oldToken, &jwt.StandardClaims{})
+	assert.NoError(t, err)
+
+	newToken, err := rotated.Sign(claims())
+	assert.NoError(t, err)
+
+	// Выданный новым ключом токен неизвестен старому набору
+	_, err = old.Parse(newToken, &jwt.StandardClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_Parse_Rejects(t *testing.T) {
+	t.Setenv("TEST_JWT_SECRET", testSecret)
+	rsPrivate, rsPublic := rsaKeyFiles(t)
+
+	set, err := New(Config{ActiveKey: "rs", Keys: []KeyConfig{
+		{Id: "rs", Alg: AlgRS256, PrivateKeyFile: rsPrivate},
+	}})
+	assert.NoError(t, err)
+
+	publicPEM, err := os.ReadFile(rsPublic)
+	assert.NoError(t, err)
+
+	// Токен HS256, подписанный открытым ключом RS256 как секретом, не должен приниматься
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	confused.Header["kid"] = "rs"
+	confusedToken, err := confused.SignedString(publicPEM)
+	assert.NoError(t, err)
+
+	_, err = set.Parse(confusedToken, &jwt.StandardClaims{})
+	assert.Error(t, err)
+
+	noKid, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims()).SignedString([]byte(testSecret))
+	assert.NoError(t, err)
+
+	_, err = set.Parse(noKid, &jwt.StandardClaims{})
+	assert.Error(t, err)
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, claims())
+	none.Header["kid"] = "rs"
+	noneToken, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+
+	_, err = set.Parse(noneToken, &jwt.StandardClaims{})
+	assert.Error(t, err)
+}
+
+func TestNew_Errors(t *testing.T) {
+	t.Setenv("TEST_JWT_SECRET", testSecret)
+	t.Setenv("TEST_JWT_SHORT_SECRET", "short")
+	_, rsPublic := rsaKeyFiles(t)
+
+	testTable := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "No Keys", cfg: Config{ActiveKey: "hs"}},
+		{name: "Unknown Active", cfg: Config{ActiveKey: "other", Keys: []KeyConfig{{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_SECRET"}}}},
+		{name: "Short Secret", cfg: Config{ActiveKey: "hs", Keys: []KeyConfig{{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_SHORT_SECRET"}}}},
+		{name: "Missing Secret", cfg: Config{ActiveKey: "hs", Keys: []KeyConfig{{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_MISSING"}}}},
+		{name: "Unsupported Alg", cfg: Config{ActiveKey: "es", Keys: []KeyConfig{{Id: "es", Alg: "ES256"}}}},
+		{name: "Public Key Active", cfg: Config{ActiveKey: "rs", Keys: []KeyConfig{{Id: "rs", Alg: AlgRS256, PublicKeyFile: rsPublic}}}},
+		{name: "Wrong Key Type", cfg: Config{ActiveKey: "ed", Keys: []KeyConfig{{Id: "ed", Alg: AlgEdDSA, PublicKeyFile: rsPublic}}}},
+		{name: "Duplicate Id", cfg: Config{ActiveKey: "hs", Keys: []KeyConfig{
+			{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_SECRET"},
+			{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_SECRET"},
+		}}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := New(testCase.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	t.Setenv("TEST_JWT_SECRET", testSecret)
+	rsPrivate, _ := rsaKeyFiles(t)
+	_, edPublic := ed25519KeyFiles(t)
+
+	set, err := New(Config{ActiveKey: "hs", Keys: []KeyConfig{
+		{Id: "hs", Alg: AlgHS256, SecretEnv: "TEST_JWT_SECRET"},
+		{Id: "rs", Alg: AlgRS256, PrivateKeyFile: rsPrivate},
+		{Id: "ed", Alg: AlgEdDSA, PublicKeyFile: edPublic},
+	}})
+	assert.NoError(t, err)
+
+	keys := set.JWKS().Keys
+	assert.Len(t, keys, 2) // секрет HS256 не публикуется
+
+	assert.Equal(t, "RSA", keys[0].Kty)
+	assert.Equal(t, "rs", keys[0].Kid)
+	assert.Equal(t, "RS256", keys[0].Alg)
+	assert.Equal(t, "AQAB", keys[0].E)
+	assert.NotEmpty(t, keys[0].N)
+
+	assert.Equal(t, "OKP", keys[1].Kty)
+	assert.Equal(t, "ed", keys[1].Kid)
+	assert.Equal(t, "Ed25519", keys[1].Crv)
+	x, err := jwt.DecodeSegment(keys[1].X)
+	assert.NoError(t, err)
+	assert.Len(t, x, ed25519.PublicKeySize)
+}
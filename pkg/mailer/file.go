@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Письма сохраняются в каталог dir файлами .eml (открываются почтовым клиентом).
+// Пустой dir - письма только пишутся в лог
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if m.dir == "" {
+		logrus.Infof("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Text)
+		return nil
+	}
+
+	data, err := format(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	// Имя файла: время отправки и адрес получателя без символов, недопустимых в путях
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"),
+		strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(msg.To))
+
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o644)
+}
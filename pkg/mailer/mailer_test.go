@@ -0,0 +1,142 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Минимальный SMTP-сервер, принимающий одно письмо
+func startSMTPServer(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		write := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		write("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					messages <- data.String()
+					write("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				write("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				write("354 End data with <CR><LF>.<CR><LF>")
+			case cmd == "QUIT":
+				write("221 Bye")
+				return
+			default:
+				write("250 OK")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), messages
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	addr, messages := startSMTPServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	m := NewSMTPMailer(ConfigSMTP{Host: host, Port: port, From: "todo@example.com"})
+	err := m.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Подтвердите адрес почты",
+		Text:    "text part",
+		HTML:    "<p>html part</p>",
+	})
+	assert.NoError(t, err)
+
+	msg := <-messages
+	assert.Contains(t, msg, "From: todo@example.com\r\n")
+	assert.Contains(t, msg, "To: user@example.com\r\n")
+	assert.Contains(t, msg, "Subject: =?utf-8?q?")
+	assert.Contains(t, msg, "Content-Type: multipart/alternative; boundary=")
+	assert.Contains(t, msg, "text part")
+	assert.Contains(t, msg, "<p>html part</p>")
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := t.TempDir()
+
+	m := NewFileMailer(dir, "todo@example.com")
+	assert.NoError(t, m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello", Text: "text part"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-user@example.com.eml"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "Subject: Hello\r\n")
+	assert.Contains(t, string(data), "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.True(t, strings.HasSuffix(string(data), "text part"))
+}
+
+func TestTemplates_Render(t *testing.T) {
+	fsys := fstest.MapFS{
+		"verify.txt":  {Data: []byte(`{{define "subject"}} Verify {{.Name}} {{end}}{{define "text"}}Open {{.Link}}{{end}}`)},
+		"verify.html": {Data: []byte(`<a href="{{.Link}}">{{.Name}}</a>`)},
+		"reset.txt":   {Data: []byte(`{{define "subject"}}Reset{{end}}{{define "text"}}Code {{.Link}}{{end}}`)},
+	}
+
+	templates, err := NewTemplates(fsys)
+	assert.NoError(t, err)
+
+	data := map[string]string{"Name": "<Alex>", "Link": "http://localhost/verify?token=a&b"}
+
+	msg, err := templates.Render("verify", "user@example.com", data)
+	assert.NoError(t, err)
+	assert.Equal(t, Message{
+		To:      "user@example.com",
+		Subject: "Verify <Alex>",
+		Text:    "Open http://localhost/verify?token=a&b\r\n",
+		HTML:    `<a href="http://localhost/verify?token=a&amp;b">&lt;Alex&gt;</a>`,
+	}, msg)
+
+	// Блоки с одинаковыми именами в разных письмах не перекрывают друг друга, HTML необязателен
+	msg, err = templates.Render("reset", "user@example.com", data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Reset", msg.Subject)
+	assert.Empty(t, msg.HTML)
+
+	_, err = templates.Render("unknown", "user@example.com", data)
+	assert.Error(t, err)
+}
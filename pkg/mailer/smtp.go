@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+type ConfigSMTP struct {
+	Host     string
+	Port     string
+	Username string // при пустом значении авторизация не выполняется (например, локальный SMTP-перехватчик)
+	Password string
+	From     string
+}
+
+type SMTPMailer struct {
+	cfg ConfigSMTP
+}
+
+func NewSMTPMailer(cfg ConfigSMTP) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	data, err := format(m.cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	// smtp.SendMail не принимает контекст, поэтому ожидание ограничивается здесь
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(net.JoinHostPort(m.cfg.Host, m.cfg.Port), auth, m.cfg.From, []string{msg.To}, data)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
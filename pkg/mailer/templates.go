@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Шаблоны писем. Для письма name в fsys лежат name.txt с блоками "subject" и "text"
+// и (необязательно) name.html с HTML-версией. HTML экранируется html/template.
+// Каждый файл разбирается отдельно, поэтому имена блоков в разных письмах совпадают
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+func NewTemplates(fsys fs.FS) (*Templates, error) {
+	t := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+
+	textFiles, err := fs.Glob(fsys, "*.txt")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range textFiles {
+		tmpl, err := texttemplate.ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		t.text[strings.TrimSuffix(file, path.Ext(file))] = tmpl
+	}
+
+	htmlFiles, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range htmlFiles {
+		tmpl, err := htmltemplate.ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		t.html[strings.TrimSuffix(file, path.Ext(file))] = tmpl
+	}
+
+	return t, nil
+}
+
+// Письмо name для получателя to с данными data
+func (t *Templates) Render(name, to string, data interface{}) (Message, error) {
+	textTmpl, ok := t.text[name]
+	if !ok {
+		return Message{}, fmt.Errorf("mail template %s not found", name)
+	}
+
+	var subject, text bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := textTmpl.ExecuteTemplate(&text, "text", data); err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\r\n",
+	}
+
+	if htmlTmpl, ok := t.html[name]; ok {
+		var html bytes.Buffer
+		if err := htmlTmpl.Execute(&html, data); err != nil {
+			return Message{}, err
+		}
+		msg.HTML = html.String()
+	}
+
+	return msg, nil
+}
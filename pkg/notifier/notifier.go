@@ -0,0 +1,25 @@
+// Доставка напоминаний о задачах.
+//
+// Каждый канал (вебхук, почта) реализует интерфейс Notifier.
+// Планировщик напоминаний выбирает реализацию по полю channel напоминания.
+
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+type Notification struct {
+	ReminderId  int        `json:"reminder_id"`
+	UserId      int        `json:"user_id"`
+	ItemId      int        `json:"item_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Target      string     `json:"-"` // URL вебхука или адрес почты
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
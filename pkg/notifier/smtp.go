@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type ConfigSMTP struct {
+	Host     string
+	Port     string
+	Username string // при пустом значении авторизация не выполняется (например, локальный SMTP-перехватчик)
+	Password string
+	From     string
+}
+
+type SMTPNotifier struct {
+	cfg ConfigSMTP
+}
+
+func NewSMTPNotifier(cfg ConfigSMTP) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, notification Notification) error {
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	msg := n.message(notification)
+
+	// smtp.SendMail не принимает контекст, поэтому ожидание ограничивается здесь
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(net.JoinHostPort(n.cfg.Host, n.cfg.Port), auth, n.cfg.From, []string{notification.Target}, msg)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *SMTPNotifier) message(notification Notification) []byte {
+	var body bytes.Buffer
+	body.WriteString(notification.Title + "\r\n")
+	if notification.Description != "" {
+		body.WriteString("\r\n" + notification.Description + "\r\n")
+	}
+	if notification.DueDate != nil {
+		body.WriteString("\r\nDue: " + notification.DueDate.Format(time.RFC1123Z) + "\r\n")
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", notification.Target)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "Reminder: "+notification.Title))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes()
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Минимальный SMTP-сервер, принимающий одно письмо
+func startSMTPServer(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		write := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		write("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					messages <- data.String()
+					write("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				write("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				write("354 End data with <CR><LF>.<CR><LF>")
+			case cmd == "QUIT":
+				write("221 Bye")
+				return
+			default:
+				write("250 OK")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), messages
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	addr, messages := startSMTPServer(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	n := NewSMTPNotifier(ConfigSMTP{Host: host, Port: port, From: "todo@example.com"})
+	err := n.Notify(context.Background(), Notification{
+		Title:       "Pay invoice",
+		Description: "monthly",
+		Target:      "user@example.com",
+	})
+	assert.NoError(t, err)
+
+	msg := <-messages
+	assert.Contains(t, msg, "From: todo@example.com\r\n")
+	assert.Contains(t, msg, "To: user@example.com\r\n")
+	assert.Contains(t, msg, "Subject: Reminder: Pay invoice\r\n")
+	assert.Contains(t, msg, "monthly")
+}
+
+func TestSMTPNotifier_Notify_ConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+
+	n := NewSMTPNotifier(ConfigSMTP{Host: host, Port: port, From: "todo@example.com"})
+	err = n.Notify(context.Background(), Notification{Title: "title", Target: "user@example.com"})
+	assert.Error(t, err)
+}
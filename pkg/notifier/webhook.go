@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// Заголовок с HMAC-SHA256 подписью тела запроса, по которому получатель может проверить отправителя
+const SignatureHeader = "X-Todo-Signature"
+
+var ErrForbiddenAddress = errors.New("webhook target address is not allowed")
+
+// Сети, не относящиеся к публичным адресам, кроме покрытых методами net.IP
+var reservedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "этот" хост
+	mustParseCIDR("100.64.0.0/10"), // разделяемые адреса провайдера (CGNAT)
+}
+
+type WebhookNotifier struct {
+	client *http.Client
+	secret string
+}
+
+// secret - ключ подписи запросов, при пустом значении запросы не подписываются.
+// Запросы отправляются только на публичные адреса, перенаправления не выполняются
+func NewWebhookNotifier(timeout time.Duration, secret string) *WebhookNotifier {
+	return newWebhookNotifier(timeout, secret, publicAddress)
+}
+
+// allowed проверяет адрес после разрешения имени, непосредственно перед соединением,
+// поэтому подмена DNS-ответа между проверкой и соединением (DNS rebinding) не обходит запрет
+func newWebhookNotifier(timeout time.Duration, secret string, allowed func(ip net.IP) bool) *WebhookNotifier {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowed(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &WebhookNotifier{
+		client: &http.Client{
+			Timeout: timeout,
+			// Без прокси из окружения: иначе проверялся бы адрес прокси, а не получателя
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				TLSHandshakeTimeout: timeout,
+			},
+			// Ответ с перенаправлением считается ошибкой доставки
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret: secret,
+	}
+}
+
+// Публичный адрес: не loopback, не частная сеть, не link-local (в т.ч. метаданные облака 169.254.169.254)
+func publicAddress(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	testTable := []struct {
+		name       string
+		secret     string
+		statusCode int
+		wantErr    bool
+	}{
+		{name: "OK", statusCode: http.StatusOK},
+		{name: "Signed", secret: "secret", statusCode: http.StatusNoContent},
+		{name: "Server Error", statusCode: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			var got Notification
+			var signature string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &got)
+
+				signature = r.Header.Get(SignatureHeader)
+				if testCase.secret != "" {
+					mac := hmac.New(sha256.New, []byte(testCase.secret))
+					mac.Write(body)
+					assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+				}
+
+				w.WriteHeader(testCase.statusCode)
+			}))
+			defer server.Close()
+
+			n := newWebhookNotifier(time.Second, testCase.secret, func(ip net.IP) bool { return true })
+			err := n.Notify(context.Background(), Notification{ReminderId: 1, UserId: 2, ItemId: 3, Title: "title", Target: server.URL})
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, Notification{ReminderId: 1, UserId: 2, ItemId: 3, Title: "title"}, got)
+				if testCase.secret == "" {
+					assert.Empty(t, signature)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_Notify_ForbiddenAddress(t *testing.T) {
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(time.Second, "")
+	err := n.Notify(context.Background(), Notification{Target: server.URL})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	// Имя хоста проверяется по адресу, в который оно разрешилось
+	err = n.Notify(context.Background(), Notification{Target: fmt.Sprintf("http://localhost:%d", server.Listener.Addr().(*net.TCPAddr).Port)})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+
+	assert.False(t, hit)
+}
+
+func TestWebhookNotifier_Notify_Redirect(t *testing.T) {
+	hit := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	n := newWebhookNotifier(time.Second, "", func(ip net.IP) bool { return true })
+	err := n.Notify(context.Background(), Notification{Target: server.URL})
+	assert.EqualError(t, err, "webhook responded with status 307")
+	assert.False(t, hit)
+}
+
+func TestPublicAddress(t *testing.T) {
+	testTable := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "93.184.216.34", want: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "10.0.0.5"},
+		{ip: "172.16.3.4"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fd00::1"},
+		{ip: "0.0.0.0"},
+		{ip: "100.64.0.1"},
+		{ip: "::ffff:127.0.0.1"},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.ip, func(t *testing.T) {
+			assert.Equal(t, testCase.want, publicAddress(net.ParseIP(testCase.ip)))
+		})
+	}
+}
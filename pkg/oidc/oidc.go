@@ -0,0 +1,306 @@
+// Вход через внешних провайдеров OpenID Connect: authorization code flow с PKCE (RFC 7636),
+// получение настроек провайдера через discovery и проверка подписи и claims ID-токена.
+//
+// Для тестов и локальной разработки есть провайдер-заглушка, см. пакет oidctest.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Допуск расхождения часов с провайдером при проверке сроков ID-токена
+const clockSkew = time.Minute
+
+// Ключи провайдера перезапрашиваются при неизвестном kid не чаще этого интервала
+const keysRefreshInterval = time.Minute
+
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// Настройки провайдера. Секрет клиента читается из переменной окружения ClientSecretEnv,
+// без секрета клиент считается публичным и защищается только PKCE
+type Config struct {
+	Name            string   `mapstructure:"name"` // имя провайдера в адресах входа
+	Issuer          string   `mapstructure:"issuer"`
+	ClientId        string   `mapstructure:"client_id"`
+	ClientSecretEnv string   `mapstructure:"client_secret_env"`
+	RedirectURL     string   `mapstructure:"redirect_url"` // адрес /auth/oidc/callback приложения
+	Scopes          []string `mapstructure:"scopes"`       // по умолчанию openid, email, profile
+	// Создавать пользователя при первом входе, если учетную запись не удалось связать с существующей
+	AutoProvision bool `mapstructure:"auto_provision"`
+}
+
+// Настройки провайдера из /.well-known/openid-configuration
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Provider struct {
+	cfg          Config
+	clientSecret string
+	client       *http.Client
+
+	mu          sync.Mutex
+	metadata    *metadata // nil - discovery еще не выполнен
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+// Discovery выполняется при первом входе, поэтому недоступный провайдер не мешает запуску приложения.
+// client nil - http.Client с таймаутом 10 секунд
+func NewProvider(cfg Config, client *http.Client) (*Provider, error) {
+	if cfg.Name == "" || cfg.Issuer == "" || cfg.ClientId == "" || cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc provider %q: name, issuer, client_id and redirect_url are required", cfg.Name)
+	}
+
+	p := &Provider{cfg: cfg, client: client}
+	if cfg.ClientSecretEnv != "" {
+		p.clientSecret = os.Getenv(cfg.ClientSecretEnv)
+		if p.clientSecret == "" {
+			return nil, fmt.Errorf("oidc provider %q: environment variable %s is empty", cfg.Name, cfg.ClientSecretEnv)
+		}
+	}
+	if len(p.cfg.Scopes) == 0 {
+		p.cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return p, nil
+}
+
+func (p *Provider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *Provider) AutoProvision() bool {
+	return p.cfg.AutoProvision
+}
+
+// Адрес страницы входа провайдера. state связывает возврат на callback с этим входом,
+// nonce попадает в ID-токен, codeVerifier - секрет PKCE, передается провайдеру только его хэш
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientId},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return md.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Обмен кода авторизации на ID-токен (без проверки, см. Verify)
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if p.clientSecret == "" {
+		form.Set("client_id", p.cfg.ClientId)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" { // client_secret_basic, значения кодируются по RFC 6749 2.3.1
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientId), url.QueryEscape(p.clientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return "", fmt.Errorf("oidc token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oidc token endpoint: %s %s (status %d)", token.Error, token.ErrorDescription, resp.StatusCode)
+	}
+	if token.IdToken == "" {
+		return "", errors.New("oidc token response has no id_token")
+	}
+
+	return token.IdToken, nil
+}
+
+// Проверка ID-токена: подпись ключом провайдера, iss, aud, azp, сроки и nonce
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(rawIDToken, &claims, p.keyfunc(ctx)); err != nil {
+		return Claims{}, fmt.Errorf("%w: %s", ErrInvalidIDToken, err.Error())
+	}
+
+	if claims.Issuer != md.Issuer {
+		return Claims{}, fmt.Errorf("%w: unexpected issuer", ErrInvalidIDToken)
+	}
+	if !claims.Audience.contains(p.cfg.ClientId) {
+		return Claims{}, fmt.Errorf("%w: token was not issued for this client", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientId {
+		return Claims{}, fmt.Errorf("%w: unexpected authorized party", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: empty subject", ErrInvalidIDToken)
+	}
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return Claims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var md metadata
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", &md); err != nil {
+		return nil, fmt.Errorf("oidc discovery for %s: %w", p.cfg.Name, err)
+	}
+	if md.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery for %s: issuer %q does not match configured %q", p.cfg.Name, md.Issuer, p.cfg.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery for %s: incomplete provider metadata", p.cfg.Name)
+	}
+
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// Ключ проверки подписи по kid. Неизвестный kid означает ротацию ключей у провайдера - ключи перезапрашиваются
+func (p *Provider) keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if !allowedMethod(token.Method) {
+			return nil, fmt.Errorf("unsupported signing method %s", token.Method.Alg())
+		}
+		kid, _ := token.Header["kid"].(string)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		key, ok := p.lookupKey(kid)
+		if !ok && time.Since(p.keysFetched) > keysRefreshInterval {
+			if err := p.fetchKeys(ctx); err != nil {
+				return nil, err
+			}
+			key, ok = p.lookupKey(kid)
+		}
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		return key, nil
+	}
+}
+
+// Без kid допускается только единственный ключ провайдера
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// Вызывается под p.mu после discovery
+func (p *Provider) fetchKeys(ctx context.Context) error {
+	var set jsonWebKeySet
+	if err := p.getJSON(ctx, p.metadata.JWKSURI, &set); err != nil {
+		return fmt.Errorf("oidc keys for %s: %w", p.cfg.Name, err)
+	}
+
+	keys, err := set.publicKeys()
+	if err != nil {
+		return fmt.Errorf("oidc keys for %s: %w", p.cfg.Name, err)
+	}
+
+	p.keys = keys
+	p.keysFetched = time.Now()
+	return nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// Только асимметричные алгоритмы: HS256 с секретом клиента не поддерживается
+func allowedMethod(method jwt.SigningMethod) bool {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+	"todo-app/pkg/oidc"
+	"todo-app/pkg/oidc/oidctest"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const redirectURL = "http://todo.test/auth/oidc/callback"
+
+func newTestProvider(t *testing.T, clientSecret string) (*oidc.Provider, *oidctest.Issuer) {
+	issuer, server, err := oidctest.NewServer("todo-app", clientSecret)
+	if err != nil {
+		t.Fatalf("failed to start issuer: %s", err.Error())
+	}
+	t.Cleanup(server.Close)
+
+	cfg := oidc.Config{Name: "test", Issuer: issuer.URL, ClientId: "todo-app", RedirectURL: redirectURL}
+	if clientSecret != "" {
+		t.Setenv("TEST_OIDC_SECRET", clientSecret)
+		cfg.ClientSecretEnv = "TEST_OIDC_SECRET"
+	}
+
+	provider, err := oidc.NewProvider(cfg, nil)
+	if err != nil {
+		t.Fatalf("failed to create provider: %s", err.Error())
+	}
+	return provider, issuer
+}
+
+// Вход на странице провайдера, возвращает код из перенаправления на callback
+func authorize(t *testing.T, authURL, state string) string {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize request failed: %s", err.Error())
+	}
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("unexpected authorize response %d", resp.StatusCode)
+	}
+	assert.Equal(t, state, location.Query().Get("state"))
+	return location.Query().Get("code")
+}
+
+func TestProvider_Flow(t *testing.T) {
+	for _, secret := range []string{"", "s3cr&t"} {
+		t.Run("secret "+secret, func(t *testing.T) {
+			provider, _ := newTestProvider(t, secret)
+			ctx := context.Background()
+
+			authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+			assert.NoError(t, err)
+
+			u, _ := url.Parse(authURL)
+			assert.Equal(t, oidc.CodeChallenge("verifier"), u.Query().Get("code_challenge"))
+			assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+
+			code := authorize(t, authURL, "state")
+
+			idToken, err := provider.Exchange(ctx, code, "verifier")
+			assert.NoError(t, err)
+
+			claims, err := provider.Verify(ctx, idToken, "nonce")
+			assert.NoError(t, err)
+			assert.Equal(t, "user-1", claims.Subject)
+			assert.Equal(t, "user@example.com", claims.Email)
+			assert.True(t, claims.EmailVerified)
+
+			_, err = provider.Exchange(ctx, code, "verifier") // код одноразовый
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProvider_Exchange_PKCE(t *testing.T) {
+	provider, _ := newTestProvider(t, "")
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+	assert.NoError(t, err)
+
+	_, err = provider.Exchange(ctx, authorize(t, authURL, "state"), "other-verifier")
+	assert.Error(t, err)
+}
+
+func TestProvider_Verify(t *testing.T) {
+	provider, issuer := newTestProvider(t, "")
+	ctx := context.Background()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   issuer.URL,
+			"sub":   "user-1",
+			"aud":   []string{"todo-app"},
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	testTable := []struct {
+		name    string
+		modify  func(c jwt.MapClaims)
+		wantErr bool
+	}{
+		{name: "OK", modify: func(c jwt.MapClaims) {}},
+		{name: "Wrong Nonce", modify: func(c jwt.MapClaims) { c["nonce"] = "other" }, wantErr: true},
+		{name: "Wrong Audience", modify: func(c jwt.MapClaims) { c["aud"] = "other-app" }, wantErr: true},
+		{name: "Multiple Audiences Without azp", modify: func(c jwt.MapClaims) { c["aud"] = []string{"todo-app", "other"} }, wantErr: true},
+		{name: "Multiple Audiences", modify: func(c jwt.MapClaims) {
+			c["aud"] = []string{"todo-app", "other"}
+			c["azp"] = "todo-app"
+		}},
+		{name: "Wrong Issuer", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, wantErr: true},
+		{name: "Expired", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * time.Minute).Unix() }, wantErr: true},
+		{name: "No Subject", modify: func(c jwt.MapClaims) { delete(c, "sub") }, wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			claims := valid()
+			testCase.modify(claims)
+
+			token, err := issuer.SignIDToken(claims)
+			assert.NoError(t, err)
+
+			_, err = provider.Verify(ctx, token, "nonce")
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// Симметричная подпись не принимается, даже если ключ известен
+	hsToken := jwt.NewWithClaims(jwt.SigningMethodHS256, valid())
+	raw, err := hsToken.SignedString([]byte("todo-app"))
+	assert.NoError(t, err)
+	_, err = provider.Verify(ctx, raw, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestProvider_Discovery_IssuerMismatch(t *testing.T) {
+	issuer, server, err := oidctest.NewServer("todo-app", "")
+	if err != nil {
+		t.Fatalf("failed to start issuer: %s", err.Error())
+	}
+	defer server.Close()
+
+	// Провайдер отвечает с другим issuer (например, настроен адрес с лишним "/")
+	provider, err := oidc.NewProvider(oidc.Config{
+		Name: "test", Issuer: issuer.URL + "/", ClientId: "todo-app", RedirectURL: redirectURL,
+	}, nil)
+	assert.NoError(t, err)
+
+	_, err = provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+// Дробные ранги для ручной сортировки списков и задач.
+//
+// Ранг - строка из цифр base62 ("0-9A-Za-z"), порядок рангов совпадает с побайтовым
+// сравнением строк (в Postgres - COLLATE "C"). Между любыми двумя различными рангами
+// всегда можно получить новый, поэтому перемещение элемента меняет только его собственный ранг.
+// Ранги не заканчиваются на минимальную цифру "0", иначе между "a" и "a0" не нашлось бы места.
+
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// Между соседями нет места (ранги совпадают или идут в обратном порядке), список нужно перенумеровать
+var ErrNoSpace = errors.New("no space between ranks")
+
+// Ранг строго между a и b. Пустой a означает начало, пустой b - конец списка
+func Between(a, b string) (string, error) {
+	if !Valid(a) || !Valid(b) {
+		return "", errors.New("invalid rank")
+	}
+	if b != "" && a >= b {
+		return "", ErrNoSpace
+	}
+
+	return midpoint(a, b), nil
+}
+
+// Ранги для n элементов подряд, равномерно распределенные по пространству рангов
+func Sequence(n int) []string {
+	res := make([]string, n)
+	if n == 0 {
+		return res
+	}
+
+	// Длина ранга, при которой хватает места на n элементов с запасом
+	width := 1
+	for capacity := base; capacity <= n; capacity *= base {
+		width++
+	}
+
+	step := 1
+	for i := 0; i < width; i++ {
+		step *= base
+	}
+	step /= n + 1
+
+	for i := range res {
+		res[i] = format((i+1)*step, width)
+	}
+
+	return res
+}
+
+// Пустая строка допустима и означает границу списка
+func Valid(r string) bool {
+	for i := 0; i < len(r); i++ {
+		if strings.IndexByte(digits, r[i]) < 0 {
+			return false
+		}
+	}
+	return r == "" || r[len(r)-1] != digits[0]
+}
+
+// Середина между a и b (a < b, пустой b - бесконечность)
+func midpoint(a, b string) string {
+	if b != "" {
+		// Общий префикс переносится в результат без изменений, недостающие цифры a считаются нулями
+		n := 0
+		for n < len(b) && digitAt(a, n) == strings.IndexByte(digits, b[n]) {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	digitA := digitAt(a, 0)
+	digitB := base
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB)/2])
+	}
+
+	// Первые цифры соседние: если у b есть продолжение, достаточно его первой цифры
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[digitA]) + midpoint(rest, "")
+}
+
+func digitAt(r string, i int) int {
+	if i >= len(r) {
+		return 0
+	}
+	return strings.IndexByte(digits, r[i])
+}
+
+// Число в виде ранга фиксированной длины без завершающих нулей
+func format(v, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = digits[v%base]
+		v /= base
+	}
+	return strings.TrimRight(string(buf), digits[:1])
+}
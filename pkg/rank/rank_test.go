@@ -0,0 +1,94 @@
+package rank
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetween(t *testing.T) {
+	testTable := []struct {
+		name    string
+		a, b    string
+		want    string
+		wantErr error
+	}{
+		{name: "Empty List", want: "V"},
+		{name: "First", b: "V", want: "F"},
+		{name: "Last", a: "V", want: "k"},
+		{name: "Middle", a: "A", b: "C", want: "B"},
+		{name: "Adjacent Digits", a: "A", b: "B", want: "AV"},
+		{name: "Adjacent Digits With Tail", a: "A", b: "B5", want: "B"},
+		{name: "Common Prefix", a: "AB", b: "AD", want: "AC"},
+		{name: "Prefix Of Other", a: "A", b: "A1", want: "A0V"},
+		{name: "After Max Digit", a: "z", want: "zV"},
+		{name: "Equal", a: "A", b: "A", wantErr: ErrNoSpace},
+		{name: "Reversed", a: "B", b: "A", wantErr: ErrNoSpace},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := Between(testCase.a, testCase.b)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.True(t, got > testCase.a)
+			if testCase.b != "" {
+				assert.True(t, got < testCase.b)
+			}
+			assert.True(t, Valid(got))
+		})
+	}
+}
+
+func TestBetween_Invalid(t *testing.T) {
+	_, err := Between("A0", "")
+	assert.Error(t, err)
+
+	_, err = Between("", "A-")
+	assert.Error(t, err)
+}
+
+// Многократные вставки в случайные места сохраняют порядок
+func TestBetween_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ranks := []string{}
+
+	for i := 0; i < 2000; i++ {
+		pos := r.Intn(len(ranks) + 1)
+
+		a, b := "", ""
+		if pos > 0 {
+			a = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			b = ranks[pos]
+		}
+
+		got, err := Between(a, b)
+		assert.NoError(t, err)
+		if !assert.True(t, got > a && (b == "" || got < b), "%q not between %q and %q", got, a, b) {
+			return
+		}
+
+		ranks = append(ranks[:pos], append([]string{got}, ranks[pos:]...)...)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	for _, n := range []int{0, 1, 10, 61, 62, 1000} {
+		seq := Sequence(n)
+		assert.Len(t, seq, n)
+		for i := range seq {
+			assert.True(t, Valid(seq[i]) && seq[i] != "", "invalid rank %q", seq[i])
+			if i > 0 {
+				assert.True(t, seq[i-1] < seq[i], "%q >= %q", seq[i-1], seq[i])
+			}
+		}
+	}
+}
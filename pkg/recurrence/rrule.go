@@ -0,0 +1,462 @@
+// Правила повторения задач в формате RRULE (RFC 5545, раздел 3.3.10)
+//
+// Поддерживаемое подмножество:
+//
+// FREQ=DAILY|WEEKLY|MONTHLY|YEARLY - обязательная часть правила;
+// INTERVAL=n - шаг повторения (по умолчанию 1);
+// COUNT=n или UNTIL=дата - окончание серии (взаимоисключающие);
+// BYDAY=MO,WE,... - дни недели (для MONTHLY допускается номер: 1MO - первый понедельник, -1FR - последняя пятница);
+// BYMONTHDAY=1,15,-1 - дни месяца (только для MONTHLY);
+// WKST=MO - первый день недели (по умолчанию понедельник).
+//
+// Следующее повторение вычисляется по "настенному" времени в часовом поясе текущего повторения,
+// поэтому задача на 09:00 остается на 09:00 и после перехода на летнее/зимнее время.
+// Несуществующие даты (31 число в коротком месяце, 29 февраля в невисокосный год) пропускаются, как того требует RFC 5545.
+
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = map[Frequency]string{
+	Daily:   "DAILY",
+	Weekly:  "WEEKLY",
+	Monthly: "MONTHLY",
+	Yearly:  "YEARLY",
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+// Ограничение перебора кандидатов, чтобы правило без подходящих дат не зациклило вычисление
+const maxIterations = 1000
+
+// День недели с необязательным номером в месяце (0 - любой такой день)
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int // 0 - без ограничения
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	WeekStart  time.Weekday
+
+	untilSet      bool
+	untilFloating bool // UNTIL без "Z": время в поясе повторений
+	untilDate     bool // UNTIL задан датой: включительно до конца суток
+}
+
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1, WeekStart: time.Monday}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return rule, errors.New("empty recurrence rule")
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return rule, fmt.Errorf("invalid recurrence rule part: %q", part)
+		}
+
+		name, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		if seen[name] {
+			return rule, fmt.Errorf("duplicate recurrence rule part: %s", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			err = rule.parseFreq(value)
+		case "INTERVAL":
+			rule.Interval, err = parsePositive(name, value)
+		case "COUNT":
+			rule.Count, err = parsePositive(name, value)
+		case "UNTIL":
+			err = rule.parseUntil(value)
+		case "BYDAY":
+			err = rule.parseByDay(value)
+		case "BYMONTHDAY":
+			err = rule.parseByMonthDay(value)
+		case "WKST":
+			rule.WeekStart, err = parseWeekday(value)
+		default:
+			err = fmt.Errorf("unsupported recurrence rule part: %s", name)
+		}
+		if err != nil {
+			return rule, err
+		}
+	}
+
+	return rule, rule.validate()
+}
+
+func (r *Rule) parseFreq(value string) error {
+	for freq, name := range frequencyNames {
+		if name == value {
+			r.Freq = freq
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported FREQ: %s", value)
+}
+
+func (r *Rule) parseUntil(value string) error {
+	layouts := []struct {
+		layout   string
+		floating bool
+		date     bool
+	}{
+		{"20060102T150405Z", false, false},
+		{"20060102T150405", true, false},
+		{"20060102", true, true},
+	}
+
+	for _, l := range layouts {
+		if t, err := time.Parse(l.layout, value); err == nil {
+			r.Until, r.untilSet, r.untilFloating, r.untilDate = t, true, l.floating, l.date
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid UNTIL: %s", value)
+}
+
+func (r *Rule) parseByDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if len(item) < 2 {
+			return fmt.Errorf("invalid BYDAY: %s", item)
+		}
+
+		weekday, err := parseWeekday(item[len(item)-2:])
+		if err != nil {
+			return err
+		}
+
+		n := 0
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return fmt.Errorf("invalid BYDAY: %s", item)
+			}
+		}
+
+		r.ByDay = append(r.ByDay, WeekdayNum{N: n, Weekday: weekday})
+	}
+	return nil
+}
+
+func (r *Rule) parseByMonthDay(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(item)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return fmt.Errorf("invalid BYMONTHDAY: %s", item)
+		}
+		r.ByMonthDay = append(r.ByMonthDay, day)
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	if r.Freq == 0 {
+		return errors.New("recurrence rule has no FREQ")
+	}
+	if r.Count > 0 && r.untilSet {
+		return errors.New("COUNT and UNTIL must not occur in the same rule")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != Monthly {
+		return errors.New("BYMONTHDAY is supported only with FREQ=MONTHLY")
+	}
+	if len(r.ByDay) > 0 && r.Freq == Yearly {
+		return errors.New("BYDAY is not supported with FREQ=YEARLY")
+	}
+	for _, day := range r.ByDay {
+		if day.N != 0 && r.Freq != Monthly {
+			return errors.New("numeric BYDAY is supported only with FREQ=MONTHLY")
+		}
+	}
+	return nil
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return n, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	for weekday, name := range weekdayNames {
+		if name == value {
+			return weekday, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday: %s", value)
+}
+
+// Каноническая запись правила, пригодная для хранения и повторного разбора
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + frequencyNames[r.Freq]}
+
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.untilSet {
+		switch {
+		case r.untilDate:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		case r.untilFloating:
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		default:
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			if day.N != 0 {
+				days = append(days, strconv.Itoa(day.N)+weekdayNames[day.Weekday])
+			} else {
+				days = append(days, weekdayNames[day.Weekday])
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, 0, len(r.ByMonthDay))
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Следующее повторение после current, где occurrence - порядковый номер current в серии (с 1).
+// Второе значение false, если серия закончилась (исчерпан COUNT или пройден UNTIL)
+func (r Rule) Next(current time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	var ok bool
+	switch r.Freq {
+	case Daily:
+		next, ok = r.nextDaily(current)
+	case Weekly:
+		next, ok = r.nextWeekly(current)
+	case Monthly:
+		next, ok = r.nextMonthly(current)
+	case Yearly:
+		next, ok = r.nextYearly(current)
+	}
+
+	if !ok || r.afterUntil(next) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r Rule) afterUntil(t time.Time) bool {
+	if !r.untilSet {
+		return false
+	}
+
+	until := r.Until
+	if r.untilFloating {
+		until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, t.Location())
+		if r.untilDate {
+			until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+	return t.After(until)
+}
+
+// Дата с тем же временем суток, что и у current, в его часовом поясе
+func atClock(current time.Time, year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, current.Hour(), current.Minute(), current.Second(), current.Nanosecond(), current.Location())
+}
+
+func (r Rule) hasWeekday(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) nextDaily(current time.Time) (time.Time, bool) {
+	year, month, day := current.Date()
+	for i := 1; i <= maxIterations; i++ {
+		candidate := atClock(current, year, month, day+i*r.Interval)
+		if r.hasWeekday(candidate.Weekday()) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextWeekly(current time.Time) (time.Time, bool) {
+	byDay := r.ByDay
+	if len(byDay) == 0 {
+		byDay = []WeekdayNum{{Weekday: current.Weekday()}}
+	}
+
+	// Начало недели current с учетом WKST
+	year, month, day := current.Date()
+	day -= (int(current.Weekday()) - int(r.WeekStart) + 7) % 7
+
+	for week := 0; week <= maxIterations; week += r.Interval {
+		for offset := 0; offset < 7; offset++ {
+			weekday := time.Weekday((int(r.WeekStart) + offset) % 7)
+			for _, d := range byDay {
+				if d.Weekday != weekday {
+					continue
+				}
+				candidate := atClock(current, year, month, day+week*7+offset)
+				if candidate.After(current) {
+					return candidate, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextMonthly(current time.Time) (time.Time, bool) {
+	year, month, _ := current.Date()
+	for i := 0; i <= maxIterations; i += r.Interval {
+		y, m := addMonths(year, month, i)
+		for _, day := range r.monthDays(current, y, m) {
+			candidate := atClock(current, y, m, day)
+			if candidate.After(current) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Дни месяца, подходящие под правило, по возрастанию
+func (r Rule) monthDays(current time.Time, year int, month time.Month) []int {
+	last := daysIn(year, month)
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if day := current.Day(); day <= last {
+			return []int{day}
+		}
+		return nil
+	}
+
+	byMonthDay := make(map[int]bool)
+	for _, day := range r.ByMonthDay {
+		if day < 0 {
+			day = last + day + 1
+		}
+		if day >= 1 && day <= last {
+			byMonthDay[day] = true
+		}
+	}
+
+	byDay := make(map[int]bool)
+	for _, d := range r.ByDay {
+		first := (int(d.Weekday)-int(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday())+7)%7 + 1
+		days := make([]int, 0, 5)
+		for day := first; day <= last; day += 7 {
+			days = append(days, day)
+		}
+		switch {
+		case d.N == 0:
+			for _, day := range days {
+				byDay[day] = true
+			}
+		case d.N > 0 && d.N <= len(days):
+			byDay[days[d.N-1]] = true
+		case d.N < 0 && -d.N <= len(days):
+			byDay[days[len(days)+d.N]] = true
+		}
+	}
+
+	// Как в RFC 5545, BYDAY и BYMONTHDAY вместе ограничивают друг друга:
+	// BYDAY=FR;BYMONTHDAY=13 - только пятница, 13-е
+	set := byMonthDay
+	switch {
+	case len(r.ByMonthDay) == 0:
+		set = byDay
+	case len(r.ByDay) > 0:
+		set = make(map[int]bool)
+		for day := range byMonthDay {
+			if byDay[day] {
+				set[day] = true
+			}
+		}
+	}
+
+	days := make([]int, 0, len(set))
+	for day := range set {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
+
+func (r Rule) nextYearly(current time.Time) (time.Time, bool) {
+	year, month, day := current.Date()
+	for i := r.Interval; i <= maxIterations; i += r.Interval {
+		if day <= daysIn(year+i, month) {
+			return atClock(current, year+i, month, day), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func addMonths(year int, month time.Month, n int) (int, time.Month) {
+	total := int(month) - 1 + n
+	return year + total/12, time.Month(total%12 + 1)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
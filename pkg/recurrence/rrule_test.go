@@ -0,0 +1,236 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %s: %s", name, err.Error())
+	}
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	testTable := []struct {
+		name    string
+		input   string
+		want    string // каноническая запись
+		wantErr bool
+	}{
+		{name: "Daily", input: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "Prefix And Lower Case", input: "RRULE:freq=weekly;byday=mo,we", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{name: "Interval Count", input: "FREQ=WEEKLY;INTERVAL=2;COUNT=10", want: "FREQ=WEEKLY;INTERVAL=2;COUNT=10"},
+		{name: "Until UTC", input: "FREQ=MONTHLY;UNTIL=20221231T235959Z", want: "FREQ=MONTHLY;UNTIL=20221231T235959Z"},
+		{name: "Until Date", input: "FREQ=DAILY;UNTIL=20221231", want: "FREQ=DAILY;UNTIL=20221231"},
+		{name: "Monthly Last Friday", input: "FREQ=MONTHLY;BYDAY=-1FR", want: "FREQ=MONTHLY;BYDAY=-1FR"},
+		{name: "Monthly By Month Day", input: "FREQ=MONTHLY;BYMONTHDAY=1,-1", want: "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{name: "Week Start", input: "FREQ=WEEKLY;WKST=SU", want: "FREQ=WEEKLY;WKST=SU"},
+		{name: "Empty", input: "", wantErr: true},
+		{name: "No Freq", input: "INTERVAL=2", wantErr: true},
+		{name: "Unknown Freq", input: "FREQ=HOURLY", wantErr: true},
+		{name: "Zero Interval", input: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "Count And Until", input: "FREQ=DAILY;COUNT=2;UNTIL=20221231", wantErr: true},
+		{name: "Bad Until", input: "FREQ=DAILY;UNTIL=2022-12-31", wantErr: true},
+		{name: "Bad Weekday", input: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "Numeric Weekly By Day", input: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "By Month Day Not Monthly", input: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{name: "Unsupported Part", input: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "Duplicate Part", input: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "Malformed Part", input: "FREQ=DAILY;INTERVAL", wantErr: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule, err := Parse(testCase.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, rule.String())
+			}
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+	newYork := mustLoad(t, "America/New_York")
+	moscow := mustLoad(t, "Europe/Moscow")
+
+	testTable := []struct {
+		name       string
+		rule       string
+		current    time.Time
+		occurrence int
+		want       time.Time
+		wantEnd    bool
+	}{
+		{
+			// 27.03.2022 в Берлине переход на летнее время: 09:00 сохраняется, а между повторениями 167 часов
+			name:    "Weekly Across Spring DST",
+			rule:    "FREQ=WEEKLY",
+			current: time.Date(2022, 3, 21, 9, 0, 0, 0, berlin),
+			want:    time.Date(2022, 3, 28, 9, 0, 0, 0, berlin),
+		},
+		{
+			// 06.11.2022 в Нью-Йорке переход на зимнее время
+			name:    "Daily Across Autumn DST",
+			rule:    "FREQ=DAILY",
+			current: time.Date(2022, 11, 5, 8, 30, 0, 0, newYork),
+			want:    time.Date(2022, 11, 6, 8, 30, 0, 0, newYork),
+		},
+		{
+			name:    "Monthly Across Autumn DST",
+			rule:    "FREQ=MONTHLY",
+			current: time.Date(2022, 10, 15, 18, 0, 0, 0, berlin),
+			want:    time.Date(2022, 11, 15, 18, 0, 0, 0, berlin),
+		},
+		{
+			// Время попадает в "пропавший" час 02:00-03:00 и сдвигается вперед
+			name:    "Daily Into DST Gap",
+			rule:    "FREQ=DAILY",
+			current: time.Date(2022, 3, 26, 2, 30, 0, 0, berlin),
+			want:    time.Date(2022, 3, 27, 3, 30, 0, 0, berlin),
+		},
+		{
+			name:    "Daily Interval",
+			rule:    "FREQ=DAILY;INTERVAL=3",
+			current: time.Date(2022, 6, 29, 10, 0, 0, 0, moscow),
+			want:    time.Date(2022, 7, 2, 10, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Daily Weekdays Skip Weekend",
+			rule:    "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			current: time.Date(2022, 6, 3, 9, 0, 0, 0, moscow), // пятница
+			want:    time.Date(2022, 6, 6, 9, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Weekly By Day Same Week",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE",
+			current: time.Date(2022, 6, 6, 9, 0, 0, 0, moscow), // понедельник
+			want:    time.Date(2022, 6, 8, 9, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Weekly Interval By Day Next Period",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			current: time.Date(2022, 6, 8, 9, 0, 0, 0, moscow), // среда
+			want:    time.Date(2022, 6, 20, 9, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Weekly Week Start Sunday",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU,SA;WKST=SU",
+			current: time.Date(2022, 6, 5, 9, 0, 0, 0, moscow), // воскресенье
+			want:    time.Date(2022, 6, 11, 9, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Monthly Skips Short Months",
+			rule:    "FREQ=MONTHLY",
+			current: time.Date(2022, 1, 31, 12, 0, 0, 0, moscow),
+			want:    time.Date(2022, 3, 31, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Monthly Last Day",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			current: time.Date(2022, 1, 31, 12, 0, 0, 0, moscow),
+			want:    time.Date(2022, 2, 28, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Monthly Last Friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			current: time.Date(2022, 5, 27, 12, 0, 0, 0, moscow),
+			want:    time.Date(2022, 6, 24, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Monthly First Monday",
+			rule:    "FREQ=MONTHLY;BYDAY=1MO",
+			current: time.Date(2022, 6, 6, 12, 0, 0, 0, moscow),
+			want:    time.Date(2022, 7, 4, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Monthly Several Days",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=1,15",
+			current: time.Date(2022, 6, 1, 12, 0, 0, 0, moscow),
+			want:    time.Date(2022, 6, 15, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Monthly Friday The 13th",
+			rule:    "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			current: time.Date(2022, 5, 13, 12, 0, 0, 0, moscow),
+			want:    time.Date(2023, 1, 13, 12, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Yearly Leap Day",
+			rule:    "FREQ=YEARLY",
+			current: time.Date(2020, 2, 29, 0, 0, 0, 0, moscow),
+			want:    time.Date(2024, 2, 29, 0, 0, 0, 0, moscow),
+		},
+		{
+			name:       "Count Reached",
+			rule:       "FREQ=DAILY;COUNT=3",
+			current:    time.Date(2022, 6, 3, 9, 0, 0, 0, moscow),
+			occurrence: 3,
+			wantEnd:    true,
+		},
+		{
+			name:       "Count Not Reached",
+			rule:       "FREQ=DAILY;COUNT=3",
+			current:    time.Date(2022, 6, 2, 9, 0, 0, 0, moscow),
+			occurrence: 2,
+			want:       time.Date(2022, 6, 3, 9, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Until Passed",
+			rule:    "FREQ=WEEKLY;UNTIL=20220610T000000Z",
+			current: time.Date(2022, 6, 6, 9, 0, 0, 0, moscow),
+			wantEnd: true,
+		},
+		{
+			name:    "Until Date Inclusive",
+			rule:    "FREQ=WEEKLY;UNTIL=20220613",
+			current: time.Date(2022, 6, 6, 23, 0, 0, 0, moscow),
+			want:    time.Date(2022, 6, 13, 23, 0, 0, 0, moscow),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule, err := Parse(testCase.rule)
+			assert.NoError(t, err)
+
+			occurrence := testCase.occurrence
+			if occurrence == 0 {
+				occurrence = 1
+			}
+
+			got, ok := rule.Next(testCase.current, occurrence)
+			if testCase.wantEnd {
+				assert.False(t, ok)
+			} else {
+				assert.True(t, ok)
+				assert.True(t, testCase.want.Equal(got), "want %s, got %s", testCase.want, got)
+				assert.Equal(t, testCase.want.Format(time.RFC3339), got.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func TestRule_Next_DSTDuration(t *testing.T) {
+	berlin := mustLoad(t, "Europe/Berlin")
+
+	rule, err := Parse("FREQ=WEEKLY")
+	assert.NoError(t, err)
+
+	// Весной неделя короче на час, осенью длиннее на час
+	spring, ok := rule.Next(time.Date(2022, 3, 21, 9, 0, 0, 0, berlin), 1)
+	assert.True(t, ok)
+	assert.Equal(t, 167*time.Hour, spring.Sub(time.Date(2022, 3, 21, 9, 0, 0, 0, berlin)))
+
+	autumn, ok := rule.Next(time.Date(2022, 10, 24, 9, 0, 0, 0, berlin), 1)
+	assert.True(t, ok)
+	assert.Equal(t, 169*time.Hour, autumn.Sub(time.Date(2022, 10, 24, 9, 0, 0, 0, berlin)))
+}
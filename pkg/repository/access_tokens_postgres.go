@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type AccessTokensPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAccessTokensPostgres(db *sqlx.DB) *AccessTokensPostgres {
+	return &AccessTokensPostgres{db: db}
+}
+
+// expiresAt == nil - бессрочный токен
+func (r *AccessTokensPostgres) Create(userId int, name string, scopes []string, tokenHash string, expiresAt *time.Time) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, scopes, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		accessTokensTable)
+	err := r.db.QueryRow(query, userId, name, pq.Array(scopes), tokenHash, expiresAt).Scan(&id)
+
+	return id, err
+}
+
+func (r *AccessTokensPostgres) GetAll(userId int) ([]todo.AccessToken, error) {
+	var tokens []todo.AccessToken
+	query := fmt.Sprintf(`SELECT id, user_id, name, scopes, created_at, expires_at, last_used_at FROM %s
+							WHERE user_id = $1 ORDER BY created_at DESC`, accessTokensTable)
+	err := r.db.Select(&tokens, query, userId)
+
+	return tokens, err
+}
+
+// Отзыв (удаление) токена пользователя. Если токена нет - sql.ErrNoRows
+func (r *AccessTokensPostgres) Delete(userId, tokenId int) error {
+	var id int
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND user_id = $2 RETURNING id", accessTokensTable)
+
+	return r.db.QueryRow(query, tokenId, userId).Scan(&id)
+}
+
+func (r *AccessTokensPostgres) DeleteAll(userId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", accessTokensTable)
+	_, err := r.db.Exec(query, userId)
+
+	return err
+}
+
+// Действующий (не истекший) токен по хэшу. Время последнего использования обновляется
+// не чаще раза в минуту, чтобы не писать в БД на каждый запрос
+func (r *AccessTokensPostgres) GetByHash(tokenHash string) (todo.AccessToken, error) {
+	var token todo.AccessToken
+	query := fmt.Sprintf(`SELECT id, user_id, name, scopes, created_at, expires_at, last_used_at FROM %s
+							WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > now())`, accessTokensTable)
+	if err := r.db.Get(&token, query, tokenHash); err != nil {
+		return token, err
+	}
+
+	touchQuery := fmt.Sprintf(`UPDATE %s SET last_used_at = now()
+								WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`,
+		accessTokensTable)
+	_, err := r.db.Exec(touchQuery, token.Id)
+
+	return token, err
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestAccessTokensPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAccessTokensPostgres(db)
+
+	expiresAt := time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+	scopes := []string{todo.ScopeListsRead, todo.ScopeItemsWrite}
+
+	mock.ExpectQuery("INSERT INTO access_tokens").
+		WithArgs(1, "backup", pq.Array(scopes), "hash", &expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	got, err := r.Create(1, "backup", scopes, "hash", &expiresAt)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessTokensPostgres_GetByHash(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAccessTokensPostgres(db)
+
+	createdAt := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_id", "name", "scopes", "created_at", "expires_at", "last_used_at"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    todo.AccessToken
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM access_tokens WHERE token_hash (.+) AND \\(expires_at IS NULL OR expires_at > now\\(\\)\\)").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(4, 1, "backup", "{lists:read,items:write}", createdAt, nil, nil))
+				mock.ExpectExec("UPDATE access_tokens SET last_used_at").WithArgs(4).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: todo.AccessToken{Id: 4, UserId: 1, Name: "backup", Scopes: []string{"lists:read", "items:write"}, CreatedAt: createdAt},
+		},
+		{
+			name: "Unknown Or Expired",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM access_tokens WHERE token_hash").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetByHash("hash")
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAccessTokensPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAccessTokensPostgres(db)
+
+	mock.ExpectQuery("DELETE FROM access_tokens WHERE (.+) RETURNING id").WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	assert.Error(t, r.Delete(1, 4)) // чужой или уже отозванный токен
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccessTokensPostgres_DeleteAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAccessTokensPostgres(db)
+
+	mock.ExpectExec("DELETE FROM access_tokens WHERE user_id = ").WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, r.DeleteAll(1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
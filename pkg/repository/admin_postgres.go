@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"fmt"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AdminPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAdminPostgres(db *sqlx.DB) *AdminPostgres {
+	return &AdminPostgres{db: db}
+}
+
+// Поля пользователя для администратора: число списков, которыми он владеет, и задач в них
+var adminUserFields = fmt.Sprintf(`u.id, u.name, u.username, coalesce(u.email, '') AS email,
+									u.email_verified_at IS NOT NULL AS email_verified, u.is_admin, u.disabled_at,
+									(SELECT count(*) FROM %s ul WHERE ul.user_id = u.id AND ul.role = '%s') AS lists,
+									(SELECT count(*) FROM %s ul INNER JOIN %s li on li.list_id = ul.list_id
+										WHERE ul.user_id = u.id AND ul.role = '%s') AS items`,
+	usersListsTable, todo.ListRoleOwner, usersListsTable, listsItemsTable, todo.ListRoleOwner)
+
+func (r *AdminPostgres) GetUsers(query todo.AdminUsersQuery) ([]todo.AdminUser, error) {
+	users := make([]todo.AdminUser, 0)
+
+	usersQuery := fmt.Sprintf(`SELECT %s FROM %s u
+									WHERE $1 = '' OR u.username ILIKE $2 OR u.name ILIKE $2 OR u.email ILIKE $2
+									ORDER BY u.id LIMIT $3 OFFSET $4`, adminUserFields, usersTable)
+	err := r.db.Select(&users, usersQuery, query.Query, likePattern(query.Query), query.Limit, query.Offset)
+
+	return users, err
+}
+
+func (r *AdminPostgres) GetUser(userId int) (todo.AdminUser, error) {
+	var user todo.AdminUser
+	query := fmt.Sprintf("SELECT %s FROM %s u WHERE u.id = $1", adminUserFields, usersTable)
+	err := r.db.Get(&user, query, userId)
+
+	return user, err
+}
+
+// Отключение или включение учетной записи. Для неизвестного пользователя возвращает sql.ErrNoRows
+func (r *AdminPostgres) SetDisabled(userId int, disabled bool) error {
+	var id int
+	query := fmt.Sprintf("UPDATE %s SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, now()) END WHERE id = $1 RETURNING id",
+		usersTable)
+
+	return r.db.QueryRow(query, userId, disabled).Scan(&id)
+}
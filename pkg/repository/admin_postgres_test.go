@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestAdminPostgres_GetUsers(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAdminPostgres(db)
+
+	disabled := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+	columns := []string{"id", "name", "username", "email", "email_verified", "is_admin", "disabled_at", "lists", "items"}
+
+	testTable := []struct {
+		name         string
+		query        todo.AdminUsersQuery
+		mockBehavior func()
+		want         []todo.AdminUser
+	}{
+		{
+			name:  "Search",
+			query: todo.AdminUsersQuery{Query: "al_x%", Limit: 10, Offset: 20},
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT u.id, (.+) FROM users u WHERE (.+) ILIKE \\$2 (.+) ORDER BY u.id LIMIT \\$3 OFFSET \\$4").
+					WithArgs("al_x%", `%al\_x\%%`, 10, 20).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(7, "Alex", "al_x%", "alex@example.com", true, false, disabled, 2, 5))
+			},
+			want: []todo.AdminUser{{Id: 7, Name: "Alex", Username: "al_x%", Email: "alex@example.com", EmailVerified: true,
+				DisabledAt: &disabled, Lists: 2, Items: 5}},
+		},
+		{
+			name:  "Empty",
+			query: todo.AdminUsersQuery{Limit: 50},
+			mockBehavior: func() {
+				mock.ExpectQuery("SELECT (.+) FROM users u").WithArgs("", "%%", 50, 0).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			want: []todo.AdminUser{},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err := r.GetUsers(testCase.query)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAdminPostgres_SetDisabled(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAdminPostgres(db)
+
+	mock.ExpectQuery("UPDATE users SET disabled_at = CASE WHEN \\$2 THEN COALESCE\\(disabled_at, now\\(\\)\\) END WHERE id = \\$1 RETURNING id").
+		WithArgs(7, true).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	assert.NoError(t, r.SetDisabled(7, true))
+
+	mock.ExpectQuery("UPDATE users SET disabled_at").WithArgs(42, false).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	assert.ErrorIs(t, r.SetDisabled(42, false), sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"todo-app"
+	"todo-app/pkg/rank"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ArchivePostgres struct {
+	db *sqlx.DB
+}
+
+func NewArchivePostgres(db *sqlx.DB) *ArchivePostgres {
+	return &ArchivePostgres{db: db}
+}
+
+type archiveItemTag struct {
+	ItemId int `db:"item_id"`
+	TagId  int `db:"tag_id"`
+}
+
+// Метки пользователя и списки, которыми он владеет, с задачами, метками задач и напоминаниями пользователя.
+// Все выборки делаются в одной транзакции, чтобы архив был согласованным
+func (r *ArchivePostgres) Export(userId int) (todo.Archive, error) {
+	var archive todo.Archive
+
+	tx, err := r.db.BeginTxx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return archive, err
+	}
+	defer tx.Rollback() // только чтение, фиксировать нечего
+
+	archive.Tags = make([]todo.ArchiveTag, 0)
+	tagsQuery := fmt.Sprintf("SELECT id, name, color FROM %s WHERE user_id = $1 ORDER BY id", tagsTable)
+	if err := tx.Select(&archive.Tags, tagsQuery, userId); err != nil {
+		return archive, err
+	}
+
+	archive.Lists = make([]todo.ArchiveList, 0)
+	listsQuery := fmt.Sprintf(`SELECT tl.id, tl.title, COALESCE(tl.description, '') AS description FROM %s tl
+									INNER JOIN %s ul on ul.list_id = tl.id WHERE ul.user_id = $1 AND ul.role = '%s'
+									ORDER BY ul.position, tl.id`, todoListsTable, usersListsTable, todo.ListRoleOwner)
+	if err := tx.Select(&archive.Lists, listsQuery, userId); err != nil {
+		return archive, err
+	}
+
+	// Задачи, метки и напоминания выбираются по всем спискам сразу и раскладываются по спискам ниже
+	owned := fmt.Sprintf("SELECT list_id FROM %s WHERE user_id = $1 AND role = '%s'", usersListsTable, todo.ListRoleOwner)
+
+	var items []todo.ArchiveItem
+	itemsQuery := fmt.Sprintf(`SELECT li.list_id, ti.id, ti.title, COALESCE(ti.description, '') AS description, ti.done,
+									ti.due_date, ti.due_all_day, ti.due_timezone, ti.priority, ti.parent_id, ti.recurrence,
+									ti.occurrence, ti.created_at FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									WHERE li.list_id IN (%s) ORDER BY ti.position, ti.id`, todoItemsTable, listsItemsTable, owned)
+	if err := tx.Select(&items, itemsQuery, userId); err != nil {
+		return archive, err
+	}
+
+	var itemTags []archiveItemTag
+	itemTagsQuery := fmt.Sprintf(`SELECT it.item_id, it.tag_id FROM %s it INNER JOIN %s t on t.id = it.tag_id
+									INNER JOIN %s li on li.item_id = it.item_id
+									WHERE t.user_id = $1 AND li.list_id IN (%s) ORDER BY it.item_id, it.tag_id`,
+		itemTagsTable, tagsTable, listsItemsTable, owned)
+	if err := tx.Select(&itemTags, itemTagsQuery, userId); err != nil {
+		return archive, err
+	}
+
+	var reminders []todo.ArchiveReminder
+	remindersQuery := fmt.Sprintf(`SELECT r.item_id, r.remind_at, r.offset_minutes, r.channel, r.target, r.fired_at FROM %s r
+									INNER JOIN %s li on li.item_id = r.item_id
+									WHERE r.user_id = $1 AND li.list_id IN (%s) ORDER BY r.id`, remindersTable, listsItemsTable, owned)
+	if err := tx.Select(&reminders, remindersQuery, userId); err != nil {
+		return archive, err
+	}
+
+	tagIds := make(map[int][]int)
+	for _, it := range itemTags {
+		tagIds[it.ItemId] = append(tagIds[it.ItemId], it.TagId)
+	}
+	itemReminders := make(map[int][]todo.ArchiveReminder)
+	for _, reminder := range reminders {
+		itemReminders[reminder.ItemId] = append(itemReminders[reminder.ItemId], reminder)
+	}
+	listItems := make(map[int][]todo.ArchiveItem)
+	for _, item := range items {
+		item.TagIds = tagIds[item.Id]
+		item.Reminders = itemReminders[item.Id]
+		listItems[item.ListId] = append(listItems[item.ListId], item)
+	}
+	for i := range archive.Lists {
+		archive.Lists[i].Items = listItems[archive.Lists[i].Id]
+		if archive.Lists[i].Items == nil {
+			archive.Lists[i].Items = make([]todo.ArchiveItem, 0)
+		}
+	}
+
+	return archive, nil
+}
+
+// Импорт архива одной транзакцией: списки добавляются в конец списков пользователя в его личном пространстве,
+// метки с существующим именем объединяются с метками пользователя. Связи архива переводятся на новые id
+func (r *ArchivePostgres) Import(userId int, archive todo.Archive) (todo.ImportResult, error) {
+	var result todo.ImportResult
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return result, err
+	}
+
+	tagIds := make(map[int]int, len(archive.Tags))
+	for _, tag := range archive.Tags {
+		var id int
+		var created bool
+		// DO UPDATE, а не DO NOTHING, чтобы RETURNING вернул id существующей метки
+		tagQuery := fmt.Sprintf(`INSERT INTO %s (user_id, name, color) VALUES ($1, $2, $3)
+									ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+									RETURNING id, (xmax = 0) AS created`, tagsTable)
+		if err := tx.QueryRow(tagQuery, userId, tag.Name, tag.Color).Scan(&id, &created); err != nil {
+			tx.Rollback()
+			return result, err
+		}
+		tagIds[tag.Id] = id
+		if created {
+			result.Tags++
+		}
+	}
+
+	var workspaceId int
+	personalQuery := fmt.Sprintf("SELECT id FROM %s WHERE personal_user_id = $1", workspacesTable)
+	if err := tx.Get(&workspaceId, personalQuery, userId); err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	var last string
+	lastPositionQuery := fmt.Sprintf("SELECT COALESCE(max(position), '') FROM %s WHERE user_id = $1", usersListsTable)
+	if err := tx.QueryRow(lastPositionQuery, userId).Scan(&last); err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	itemIds := make(map[int]int)
+	for _, list := range archive.Lists {
+		var listId int
+		createListQuery := fmt.Sprintf("INSERT INTO %s (title, description, workspace_id) VALUES ($1, $2, $3) RETURNING id", todoListsTable)
+		if err := tx.QueryRow(createListQuery, list.Title, list.Description, workspaceId).Scan(&listId); err != nil {
+			tx.Rollback()
+			return result, err
+		}
+
+		last = nextPosition(last)
+		createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id, position, role) VALUES ($1, $2, $3, '%s')",
+			usersListsTable, todo.ListRoleOwner)
+		if _, err := tx.Exec(createUsersListQuery, userId, listId, last); err != nil {
+			tx.Rollback()
+			return result, err
+		}
+		result.Lists++
+
+		// Порядок задач в архиве и есть порядок сортировки, ранги выдаются заново
+		positions := rank.Sequence(len(list.Items))
+		for i, item := range list.Items {
+			if item.Occurrence == 0 {
+				item.Occurrence = 1
+			}
+			var createdAt *time.Time // без даты создания в архиве - время импорта
+			if !item.CreatedAt.IsZero() {
+				createdAt = &item.CreatedAt
+			}
+
+			var itemId int
+			createItemQuery := fmt.Sprintf(`INSERT INTO %s (title, description, done, due_date, due_all_day, due_timezone, priority,
+									recurrence, occurrence, position, created_at)
+									VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11, now())) RETURNING id`, todoItemsTable)
+			if err := tx.QueryRow(createItemQuery, item.Title, item.Description, item.Done, item.DueDate, item.DueAllDay,
+				item.DueTimezone, item.Priority, item.Recurrence, item.Occurrence, positions[i], createdAt).Scan(&itemId); err != nil {
+				tx.Rollback()
+				return result, err
+			}
+			itemIds[item.Id] = itemId
+
+			createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) VALUES ($1, $2)", listsItemsTable)
+			if _, err := tx.Exec(createListItemsQuery, listId, itemId); err != nil {
+				tx.Rollback()
+				return result, err
+			}
+			result.Items++
+		}
+	}
+
+	// Родители, метки и напоминания - после создания всех задач, когда известны все новые id
+	for _, list := range archive.Lists {
+		for _, item := range list.Items {
+			itemId := itemIds[item.Id]
+
+			if item.ParentId != nil {
+				parentQuery := fmt.Sprintf("UPDATE %s SET parent_id = $1 WHERE id = $2", todoItemsTable)
+				if _, err := tx.Exec(parentQuery, itemIds[*item.ParentId], itemId); err != nil {
+					tx.Rollback()
+					return result, err
+				}
+			}
+
+			for _, tagId := range item.TagIds {
+				attachQuery := fmt.Sprintf("INSERT INTO %s (item_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", itemTagsTable)
+				if _, err := tx.Exec(attachQuery, itemId, tagIds[tagId]); err != nil {
+					tx.Rollback()
+					return result, err
+				}
+			}
+
+			for _, reminder := range item.Reminders {
+				reminderQuery := fmt.Sprintf(`INSERT INTO %s (item_id, user_id, remind_at, offset_minutes, channel, target, fired_at)
+									VALUES ($1, $2, $3, $4, $5, $6, $7)`, remindersTable)
+				if _, err := tx.Exec(reminderQuery, itemId, userId, reminder.RemindAt, reminder.OffsetMinutes,
+					reminder.Channel, reminder.Target, reminder.FiredAt); err != nil {
+					tx.Rollback()
+					return result, err
+				}
+			}
+		}
+	}
+
+	return result, tx.Commit()
+}
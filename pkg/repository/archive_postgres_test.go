@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestArchivePostgres_Export(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewArchivePostgres(db)
+
+	created := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+	remindAt := time.Date(2022, 6, 12, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, name, color FROM tags WHERE user_id = (.+)").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).AddRow(3, "work", "#ff0000"))
+	mock.ExpectQuery("SELECT tl.id, tl.title, (.+) FROM todo_lists tl INNER JOIN user_lists ul (.+) ul.role = 'owner'").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description"}).AddRow(10, "Home", "").AddRow(11, "Empty", "no items"))
+	mock.ExpectQuery("SELECT li.list_id, ti.id, (.+) FROM todo_items ti").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"list_id", "id", "title", "description", "done", "due_date", "due_all_day",
+			"due_timezone", "priority", "parent_id", "recurrence", "occurrence", "created_at"}).
+			AddRow(10, 20, "Buy milk", "", false, nil, false, "", 2, nil, "", 1, created).
+			AddRow(10, 21, "Lactose free", "", true, nil, false, "", 0, 20, "", 1, created))
+	mock.ExpectQuery("SELECT it.item_id, it.tag_id FROM item_tags it").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "tag_id"}).AddRow(20, 3))
+	mock.ExpectQuery("SELECT r.item_id, (.+) FROM reminders r").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "remind_at", "offset_minutes", "channel", "target", "fired_at"}).
+			AddRow(20, remindAt, nil, "email", "alex@example.com", nil))
+	mock.ExpectRollback()
+
+	archive, err := r.Export(1)
+	assert.NoError(t, err)
+
+	parent := 20
+	assert.Equal(t, []todo.ArchiveTag{{Id: 3, Name: "work", Color: "#ff0000"}}, archive.Tags)
+	assert.Equal(t, []todo.ArchiveList{
+		{Id: 10, Title: "Home", Items: []todo.ArchiveItem{
+			{Id: 20, ListId: 10, Title: "Buy milk", Priority: todo.PriorityMedium, Occurrence: 1, CreatedAt: created, TagIds: []int{3},
+				Reminders: []todo.ArchiveReminder{{ItemId: 20, RemindAt: &remindAt, Channel: "email", Target: "alex@example.com"}}},
+			{Id: 21, ListId: 10, Title: "Lactose free", Done: true, ParentId: &parent, Occurrence: 1, CreatedAt: created},
+		}},
+		{Id: 11, Title: "Empty", Description: "no items", Items: []todo.ArchiveItem{}},
+	}, archive.Lists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchivePostgres_Import(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewArchivePostgres(db)
+
+	parent := 20
+	offset := 30
+	archive := todo.Archive{
+		Version: todo.ArchiveVersion,
+		Tags:    []todo.ArchiveTag{{Id: 3, Name: "work"}, {Id: 4, Name: "home"}},
+		Lists: []todo.ArchiveList{{Id: 10, Title: "Home", Items: []todo.ArchiveItem{
+			{Id: 20, Title: "Buy milk", TagIds: []int{4}},
+			{Id: 21, Title: "Lactose free", ParentId: &parent,
+				Reminders: []todo.ArchiveReminder{{OffsetMinutes: &offset, Channel: "email", Target: "alex@example.com"}}},
+		}}},
+	}
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		want         todo.ImportResult
+		wantErr      bool
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO tags (.+) ON CONFLICT \\(user_id, name\\) DO UPDATE").WithArgs(1, "work", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(7, false))
+				mock.ExpectQuery("INSERT INTO tags").WithArgs(1, "home", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(8, true))
+				mock.ExpectQuery("SELECT id FROM workspaces WHERE personal_user_id = (.+)").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT COALESCE\\(max\\(position\\), ''\\) FROM user_lists").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+				mock.ExpectQuery("INSERT INTO todo_lists").WithArgs("Home", "", 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(50))
+				mock.ExpectExec("INSERT INTO user_lists").WithArgs(1, 50, "k").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("INSERT INTO todo_items").WithArgs("Buy milk", "", false, nil, false, "", todo.PriorityNone, "", 1, "K", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(60))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(50, 60).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("INSERT INTO todo_items").WithArgs("Lactose free", "", false, nil, false, "", todo.PriorityNone, "", 1, "e", nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(61))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(50, 61).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO item_tags").WithArgs(60, 8).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE todo_items SET parent_id").WithArgs(60, 61).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO reminders").WithArgs(61, 1, nil, &offset, "email", "alex@example.com", nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: todo.ImportResult{Lists: 1, Items: 2, Tags: 1},
+		},
+		{
+			name: "Rollback On Failure",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO tags").WithArgs(1, "work", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(7, true))
+				mock.ExpectQuery("INSERT INTO tags").WithArgs(1, "home", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(8, true))
+				mock.ExpectQuery("SELECT id FROM workspaces").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT COALESCE").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+				mock.ExpectQuery("INSERT INTO todo_lists").WithArgs("Home", "", 2).WillReturnError(errors.New("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			got, err := r.Import(1, archive)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"fmt"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditPostgres struct {
+	db *sqlx.DB
+}
+
+func NewAuditPostgres(db *sqlx.DB) *AuditPostgres {
+	return &AuditPostgres{db: db}
+}
+
+// Запись в журнал аудита, пустые details сохраняются как {}
+func (r *AuditPostgres) Create(record todo.AuditRecord) error {
+	if len(record.Details) == 0 {
+		record.Details = []byte("{}")
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, action, ip, details) VALUES ($1, $2, $3, $4)", auditLogTable)
+	_, err := r.db.Exec(query, record.UserId, record.Action, record.Ip, record.Details)
+
+	return err
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestAuditPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAuditPostgres(db)
+
+	userId := 7
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(&userId, todo.AuditLoginLockout, "192.0.2.1", []byte(`{"failures":10}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(nil, todo.AuditLoginLockout, "", []byte("{}")).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	assert.NoError(t, r.Create(todo.AuditRecord{
+		UserId: &userId, Action: todo.AuditLoginLockout, Ip: "192.0.2.1", Details: []byte(`{"failures":10}`),
+	}))
+	assert.NoError(t, r.Create(todo.AuditRecord{Action: todo.AuditLoginLockout})) // пользователь неизвестен
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 	"todo-app"
 
 	"github.com/jmoiron/sqlx"
@@ -16,19 +18,195 @@ func NewAuthPostgres(db *sqlx.DB) *AuthPostgres {
 }
 
 func (r *AuthPostgres) CreateUser(user todo.User) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := createUser(tx, user)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// Вместе с пользователем создается его личное пространство
+func createUser(tx *sql.Tx, user todo.User) (int, error) {
 	var id int
-	query := fmt.Sprintf("INSERT INTO %s (name, username, password_hash) values ($1, $2, $3) RETURNING id", usersTable)
-	row := r.db.QueryRow(query, user.Name, user.Username, user.Password)
+	query := fmt.Sprintf("INSERT INTO %s (name, username, password_hash, email) values ($1, $2, $3, NULLIF($4, '')) RETURNING id",
+		usersTable)
+	row := tx.QueryRow(query, user.Name, user.Username, user.Password, user.Email)
 	if err := row.Scan(&id); err != nil {
 		return 0, err
 	}
+
+	var workspaceId int
+	workspaceQuery := fmt.Sprintf("INSERT INTO %s (name, personal_user_id) values ('Personal', $1) RETURNING id", workspacesTable)
+	if err := tx.QueryRow(workspaceQuery, id).Scan(&workspaceId); err != nil {
+		return 0, err
+	}
+
+	memberQuery := fmt.Sprintf("INSERT INTO %s (workspace_id, user_id, role) values ($1, $2, '%s')", workspaceMembersTable, todo.ListRoleOwner)
+	if _, err := tx.Exec(memberQuery, workspaceId, id); err != nil {
+		return 0, err
+	}
+
 	return id, nil
 }
 
-func (r *AuthPostgres) GetUser(username, password string) (todo.User, error) { // поиск id пользователя по username и password
+// Поиск пользователя по username вместе с хэшем пароля, пароль проверяется в сервисе
+func (r *AuthPostgres) GetUser(username string) (todo.User, error) {
+	var user todo.User
+	query := fmt.Sprintf("SELECT id, password_hash FROM %s WHERE username=$1", usersTable)
+	err := r.db.Get(&user, query, username)
+
+	return user, err
+}
+
+func (r *AuthPostgres) UpdatePasswordHash(userId int, hash string) error {
+	query := fmt.Sprintf("UPDATE %s SET password_hash=$1 WHERE id=$2", usersTable)
+	_, err := r.db.Exec(query, hash, userId)
+
+	return err
+}
+
+// Пользователь без хэша пароля
+func (r *AuthPostgres) GetUserById(userId int) (todo.User, error) {
+	var user todo.User
+	query := fmt.Sprintf(`SELECT id, name, username, coalesce(email, '') AS email, email_verified_at IS NOT NULL AS email_verified,
+							is_admin, disabled_at IS NOT NULL AS disabled FROM %s WHERE id=$1`, usersTable)
+	err := r.db.Get(&user, query, userId)
+
+	return user, err
+}
+
+// Поиск по почте без учета регистра, пользователь без хэша пароля
+func (r *AuthPostgres) GetUserByEmail(email string) (todo.User, error) {
 	var user todo.User
-	query := fmt.Sprintf("SELECT id FROM %s WHERE username=$1 AND password_hash=$2", usersTable)
-	err := r.db.Get(&user, query, username, password)
+	query := fmt.Sprintf(`SELECT id, name, username, email, email_verified_at IS NOT NULL AS email_verified
+							FROM %s WHERE lower(email)=lower($1)`, usersTable)
+	err := r.db.Get(&user, query, email)
 
 	return user, err
 }
+
+// Отметка о подтверждении почты. Если почта пользователя с тех пор изменилась - sql.ErrNoRows
+func (r *AuthPostgres) SetEmailVerified(userId int, email string) error {
+	var id int
+	query := fmt.Sprintf("UPDATE %s SET email_verified_at=now() WHERE id=$1 AND lower(email)=lower($2) RETURNING id", usersTable)
+
+	return r.db.QueryRow(query, userId, email).Scan(&id)
+}
+
+// Обновление данных пользователя. При смене почты отметка о подтверждении снимается, пустая строка удаляет адрес
+func (r *AuthPostgres) UpdateUser(userId int, input todo.UpdateUserInput) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+	if input.Username != nil {
+		setValues = append(setValues, fmt.Sprintf("username=$%d", argId))
+		args = append(args, *input.Username)
+		argId++
+	}
+	if input.Email != nil {
+		setValues = append(setValues, fmt.Sprintf(
+			"email=NULLIF($%d, ''), email_verified_at=CASE WHEN lower(email) = lower($%d) THEN email_verified_at END", argId, argId))
+		args = append(args, *input.Email)
+		argId++
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id=$%d", usersTable, strings.Join(setValues, ", "), argId)
+	args = append(args, userId)
+	_, err := r.db.Exec(query, args...)
+
+	return err
+}
+
+// Удаление пользователя. Общие списки остаются у других участников: списки из личного пространства
+// переходят в личное пространство нового владельца, владельцем становится участник с наибольшей ролью.
+// Списки и задачи, к которым больше ни у кого нет доступа, удаляются. Остальные данные пользователя
+// (сессии, токены, метки, личное пространство) удаляются каскадно
+func (r *AuthPostgres) DeleteUser(userId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Новые владельцы списков, в которых пользователь был единственным владельцем
+	ownersQuery := fmt.Sprintf(`UPDATE %s ul SET role = '%s', inherited = false FROM (
+									SELECT DISTINCT ON (other.list_id) other.id FROM %s mine
+									INNER JOIN %s other on other.list_id = mine.list_id AND other.user_id <> $1
+									WHERE mine.user_id = $1 AND mine.role = '%s' AND NOT EXISTS (
+										SELECT 1 FROM %s o WHERE o.list_id = mine.list_id AND o.user_id <> $1 AND o.role = '%s')
+									ORDER BY other.list_id, (other.role = '%s') DESC, other.id) heir
+								WHERE ul.id = heir.id`,
+		usersListsTable, todo.ListRoleOwner, usersListsTable, usersListsTable, todo.ListRoleOwner,
+		usersListsTable, todo.ListRoleOwner, todo.ListRoleEditor)
+	if _, err := tx.Exec(ownersQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Общие списки из личного пространства переходят в личное пространство владельца
+	moveQuery := fmt.Sprintf(`UPDATE %s tl SET workspace_id = pw.id FROM (
+									SELECT DISTINCT ON (ul.list_id) ul.list_id, ul.user_id FROM %s ul
+									INNER JOIN %s l on l.id = ul.list_id
+									INNER JOIN %s w on w.id = l.workspace_id AND w.personal_user_id = $1
+									WHERE ul.user_id <> $1
+									ORDER BY ul.list_id, (ul.role = '%s') DESC, ul.id) heir
+								INNER JOIN %s pw on pw.personal_user_id = heir.user_id
+								WHERE tl.id = heir.list_id`,
+		todoListsTable, usersListsTable, todoListsTable, workspacesTable, todo.ListRoleOwner, workspacesTable)
+	if _, err := tx.Exec(moveQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Новые владельцы общих пространств, в которых пользователь был единственным владельцем
+	workspaceOwnersQuery := fmt.Sprintf(`UPDATE %s wm SET role = '%s' FROM (
+									SELECT DISTINCT ON (other.workspace_id) other.id FROM %s mine
+									INNER JOIN %s other on other.workspace_id = mine.workspace_id AND other.user_id <> $1
+									WHERE mine.user_id = $1 AND mine.role = '%s' AND NOT EXISTS (
+										SELECT 1 FROM %s o WHERE o.workspace_id = mine.workspace_id AND o.user_id <> $1 AND o.role = '%s')
+									ORDER BY other.workspace_id, (other.role = '%s') DESC, other.id) heir
+								WHERE wm.id = heir.id`,
+		workspaceMembersTable, todo.ListRoleOwner, workspaceMembersTable, workspaceMembersTable, todo.ListRoleOwner,
+		workspaceMembersTable, todo.ListRoleOwner, todo.ListRoleEditor)
+	if _, err := tx.Exec(workspaceOwnersQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Списки, к которым больше ни у кого нет доступа, вместе с задачами
+	orphans := fmt.Sprintf(`SELECT ul.list_id FROM %s ul WHERE ul.user_id = $1 AND NOT EXISTS (
+									SELECT 1 FROM %s other WHERE other.list_id = ul.list_id AND other.user_id <> $1)`,
+		usersListsTable, usersListsTable)
+	itemsQuery := fmt.Sprintf("DELETE FROM %s ti USING %s li WHERE ti.id = li.item_id AND li.list_id IN (%s)",
+		todoItemsTable, listsItemsTable, orphans)
+	if _, err := tx.Exec(itemsQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+	listsQuery := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", todoListsTable, orphans)
+	if _, err := tx.Exec(listsQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var id int
+	userQuery := fmt.Sprintf("DELETE FROM %s WHERE id = $1 RETURNING id", usersTable)
+	if err := tx.QueryRow(userQuery, userId).Scan(&id); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
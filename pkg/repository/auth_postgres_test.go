@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"testing"
 	"todo-app"
@@ -33,12 +34,23 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 				Name:     "Alex",
 				Username: "alexkomzzz",
 				Password: "qwerty",
+				Email:    "alex@example.com",
 			},
 			id: 55,
 			mockBehavior: func(user todo.User, id int) {
+				mock.ExpectBegin()
+
 				row := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO users").
-					WithArgs(user.Name, user.Username, user.Password).WillReturnRows(row)
+					WithArgs(user.Name, user.Username, user.Password, user.Email).WillReturnRows(row)
+
+				mock.ExpectQuery("INSERT INTO workspaces").WithArgs(id).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+				mock.ExpectExec("INSERT INTO workspace_members").WithArgs(3, id).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
 			},
 		},
 		{
@@ -49,8 +61,12 @@ func TestAuthPostgres_CreateUser(t *testing.T) {
 				Password: "qwerty",
 			},
 			mockBehavior: func(user todo.User, id int) {
+				mock.ExpectBegin()
+
 				mock.ExpectQuery("INSERT INTO users").
-					WithArgs(user.Name, user.Username, user.Password).WillReturnError(errors.New("Error Create"))
+					WithArgs(user.Name, user.Username, user.Password, user.Email).WillReturnError(errors.New("Error Create"))
+
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -80,17 +96,12 @@ func TestAuthPostgres_GeteUser(t *testing.T) {
 
 	r := NewAuthPostgres(db)
 
-	type input struct {
-		username string
-		password string
-	}
-
-	type mockBehavior func(input input, user todo.User)
+	type mockBehavior func(username string, user todo.User)
 
 	testTable := []struct {
 		name         string
 		user         todo.User
-		input        input
+		username     string
 		mockBehavior mockBehavior
 		wantErr      bool
 	}{
@@ -98,30 +109,22 @@ func TestAuthPostgres_GeteUser(t *testing.T) {
 			name: "OK",
 			user: todo.User{
 				Id:       22,
-				Name:     "Alex",
-				Username: "alexkomzzz",
-				Password: "qwerty",
-			},
-			input: input{
-				username: "alexkomzzz",
-				password: "qwerty",
+				Password: "$2a$10$hash",
 			},
-			mockBehavior: func(input input, user todo.User) {
-				rows := sqlmock.NewRows([]string{"id", "name", "username", "password"}).
-					AddRow(user.Id, user.Name, user.Username, user.Password)
-				mock.ExpectQuery("SELECT id FROM users").
-					WithArgs(input.username, input.password).WillReturnRows(rows)
+			username: "alexkomzzz",
+			mockBehavior: func(username string, user todo.User) {
+				rows := sqlmock.NewRows([]string{"id", "password_hash"}).
+					AddRow(user.Id, user.Password)
+				mock.ExpectQuery("SELECT id, password_hash FROM users").
+					WithArgs(username).WillReturnRows(rows)
 			},
 		},
 		{
-			name: "Error GetUser",
-			input: input{
-				username: "alexkomzzz",
-				password: "qwerty",
-			},
-			mockBehavior: func(input input, user todo.User) {
-				mock.ExpectQuery("SELECT id FROM users").
-					WithArgs(input.username, input.password).WillReturnError(errors.New("Error GetUser"))
+			name:     "Error GetUser",
+			username: "alexkomzzz",
+			mockBehavior: func(username string, user todo.User) {
+				mock.ExpectQuery("SELECT id, password_hash FROM users").
+					WithArgs(username).WillReturnError(errors.New("Error GetUser"))
 			},
 			wantErr: true,
 		},
@@ -129,9 +132,9 @@ func TestAuthPostgres_GeteUser(t *testing.T) {
 
 	for _, testCase := range testTable {
 		t.Run(testCase.name, func(t *testing.T) {
-			testCase.mockBehavior(testCase.input, testCase.user)
+			testCase.mockBehavior(testCase.username, testCase.user)
 
-			gotUser, err := r.GetUser(testCase.input.username, testCase.input.password)
+			gotUser, err := r.GetUser(testCase.username)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -141,3 +144,130 @@ func TestAuthPostgres_GeteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthPostgres_UpdatePasswordHash(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAuthPostgres(db)
+
+	mock.ExpectExec("UPDATE users SET password_hash").WithArgs("$2a$10$hash", 22).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.UpdatePasswordHash(22, "$2a$10$hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_SetEmailVerified(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAuthPostgres(db)
+
+	mock.ExpectQuery("UPDATE users SET email_verified_at=now\\(\\) WHERE id=(.+) AND lower\\(email\\)=lower").
+		WithArgs(1, "alex@example.com").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery("UPDATE users SET email_verified_at").
+		WithArgs(1, "old@example.com").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	assert.NoError(t, r.SetEmailVerified(1, "alex@example.com"))
+	assert.ErrorIs(t, r.SetEmailVerified(1, "old@example.com"), sql.ErrNoRows) // почта изменилась после отправки письма
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_UpdateUser(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAuthPostgres(db)
+
+	name, email := "Alexey", "new@example.com"
+
+	mock.ExpectExec("UPDATE users SET name=\\$1 WHERE id=\\$2").
+		WithArgs("Alexey", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users SET name=\\$1, email=NULLIF\\(\\$2, ''\\), email_verified_at=CASE (.+) WHERE id=\\$3").
+		WithArgs("Alexey", "new@example.com", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, r.UpdateUser(1, todo.UpdateUserInput{Name: &name}))
+	assert.NoError(t, r.UpdateUser(1, todo.UpdateUserInput{Name: &name, Email: &email}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthPostgres_DeleteUser(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewAuthPostgres(db)
+
+	testTable := []struct {
+		name         string
+		mockBehavior func()
+		wantErr      error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_lists ul SET role = 'owner'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE todo_lists tl SET workspace_id").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE workspace_members wm SET role = 'owner'").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM todo_items ti USING lists_items li").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec("DELETE FROM todo_lists WHERE id IN").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery("DELETE FROM users WHERE id = (.+) RETURNING id").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Found",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_lists").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE todo_lists").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE workspace_members").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM todo_items").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM todo_lists").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("DELETE FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name: "Failed To Delete Lists",
+			mockBehavior: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE user_lists").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE todo_lists").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE workspace_members").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM todo_items").WithArgs(1).WillReturnError(errors.New("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: errors.New("some error"),
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mockBehavior()
+
+			err := r.DeleteUser(1)
+			if testCase.wantErr != nil {
+				assert.Equal(t, testCase.wantErr, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type EmailTokensPostgres struct {
+	db *sqlx.DB
+}
+
+func NewEmailTokensPostgres(db *sqlx.DB) *EmailTokensPostgres {
+	return &EmailTokensPostgres{db: db}
+}
+
+// Новый токен, прежние неиспользованные токены того же назначения аннулируются,
+// так что действует только ссылка из последнего письма
+func (r *EmailTokensPostgres) Create(userId int, purpose, email, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	expireQuery := fmt.Sprintf("UPDATE %s SET used_at = now() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL",
+		emailTokensTable)
+	if _, err := tx.Exec(expireQuery, userId, purpose); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	createQuery := fmt.Sprintf("INSERT INTO %s (user_id, purpose, email, token_hash, expires_at) VALUES ($1, $2, $3, $4, $5)",
+		emailTokensTable)
+	if _, err := tx.Exec(createQuery, userId, purpose, email, tokenHash, expiresAt); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Одноразовое использование токена, возвращает id пользователя и адрес письма.
+// Неизвестный, истекший или использованный токен - sql.ErrNoRows
+func (r *EmailTokensPostgres) Use(purpose, tokenHash string) (int, string, error) {
+	var userId int
+	var email string
+	query := fmt.Sprintf(`UPDATE %s SET used_at = now()
+							WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+							RETURNING user_id, email`, emailTokensTable)
+	err := r.db.QueryRow(query, tokenHash, purpose).Scan(&userId, &email)
+
+	return userId, email, err
+}
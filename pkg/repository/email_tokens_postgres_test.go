@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestEmailTokensPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewEmailTokensPostgres(db)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE email_tokens SET used_at (.+) WHERE user_id (.+) AND used_at IS NULL").
+		WithArgs(1, "reset").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO email_tokens").WithArgs(1, "reset", "alex@example.com", "hash", expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Create(1, "reset", "alex@example.com", "hash", expiresAt))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEmailTokensPostgres_Use(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewEmailTokensPostgres(db)
+
+	mock.ExpectQuery("UPDATE email_tokens SET used_at (.+) AND used_at IS NULL AND expires_at > now\\(\\) RETURNING user_id, email").
+		WithArgs("hash", "verify").WillReturnRows(sqlmock.NewRows([]string{"user_id", "email"}).AddRow(1, "alex@example.com"))
+	mock.ExpectQuery("UPDATE email_tokens SET used_at").
+		WithArgs("hash", "verify").WillReturnRows(sqlmock.NewRows([]string{"user_id", "email"}))
+
+	userId, email, err := r.Use("verify", "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, userId)
+	assert.Equal(t, "alex@example.com", email)
+
+	_, _, err = r.Use("verify", "hash") // повторное использование
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
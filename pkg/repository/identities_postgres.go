@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdentitiesPostgres struct {
+	db *sqlx.DB
+}
+
+func NewIdentitiesPostgres(db *sqlx.DB) *IdentitiesPostgres {
+	return &IdentitiesPostgres{db: db}
+}
+
+// Пользователь, связанный с учетной записью провайдера, sql.ErrNoRows - связи нет
+func (r *IdentitiesPostgres) GetUserId(provider, subject string) (int, error) {
+	var userId int
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE provider=$1 AND subject=$2", userIdentitiesTable)
+	err := r.db.Get(&userId, query, provider, subject)
+
+	return userId, err
+}
+
+func (r *IdentitiesPostgres) Link(userId int, provider, subject, email string) error {
+	query := fmt.Sprintf("INSERT INTO %s (user_id, provider, subject, email) VALUES ($1, $2, $3, $4)", userIdentitiesTable)
+	_, err := r.db.Exec(query, userId, provider, subject, email)
+
+	return err
+}
+
+// Новый пользователь, связанный с учетной записью провайдера. Почта пользователя считается подтвержденной
+// (ее подтвердил провайдер), пустой пароль не подходит ни к одному хэшу, так что вход возможен только через провайдер
+func (r *IdentitiesPostgres) CreateUser(user todo.User, provider, subject string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	user.Password = ""
+	id, err := createUser(tx, user)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if user.Email != "" {
+		verifyQuery := fmt.Sprintf("UPDATE %s SET email_verified_at = now() WHERE id = $1", usersTable)
+		if _, err := tx.Exec(verifyQuery, id); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	linkQuery := fmt.Sprintf("INSERT INTO %s (user_id, provider, subject, email) VALUES ($1, $2, $3, $4)", userIdentitiesTable)
+	if _, err := tx.Exec(linkQuery, id, provider, subject, user.Email); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
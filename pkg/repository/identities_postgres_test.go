@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestIdentitiesPostgres_GetUserId(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewIdentitiesPostgres(db)
+
+	mock.ExpectQuery("SELECT user_id FROM user_identities WHERE provider=(.+) AND subject=").
+		WithArgs("corp", "sub-1").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(7))
+	mock.ExpectQuery("SELECT user_id FROM user_identities").
+		WithArgs("corp", "sub-2").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	userId, err := r.GetUserId("corp", "sub-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, userId)
+
+	_, err = r.GetUserId("corp", "sub-2")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdentitiesPostgres_CreateUser(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewIdentitiesPostgres(db)
+
+	user := todo.User{Name: "Alex", Username: "alex", Password: "ignored", Email: "alex@example.com"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO users").WithArgs("Alex", "alex", "", "alex@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery("INSERT INTO workspaces").WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectExec("INSERT INTO workspace_members").WithArgs(3, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE users SET email_verified_at").WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO user_identities").WithArgs(7, "corp", "sub-1", "alex@example.com").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := r.CreateUser(user, "corp", "sub-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type InvitationsPostgres struct {
+	db *sqlx.DB
+}
+
+func NewInvitationsPostgres(db *sqlx.DB) *InvitationsPostgres {
+	return &InvitationsPostgres{db: db}
+}
+
+// Общая часть выборки приглашений вместе с названием списка и username участников
+var invitationsQuery = fmt.Sprintf(`SELECT i.id, i.list_id, tl.title AS list_title, inv.username AS inviter, u.username AS invitee,
+									i.role, i.status, i.expires_at, i.created_at FROM %s i
+									INNER JOIN %s tl on tl.id = i.list_id
+									INNER JOIN %s inv on inv.id = i.inviter_id
+									LEFT JOIN %s u on u.id = i.invitee_id`,
+	invitationsTable, todoListsTable, usersTable, usersTable)
+
+// Права пригласившего проверяются в сервисе. Для приглашения по ссылке username пустой,
+// а в БД хранится только хэш токена
+func (r *InvitationsPostgres) Create(inviterId, listId int, username string, role todo.ListRole, expiresAt time.Time, tokenHash *string) (int, error) {
+	var id int
+	var row *sql.Row
+
+	if username == "" {
+		query := fmt.Sprintf(`INSERT INTO %s (list_id, inviter_id, role, token_hash, expires_at)
+									VALUES ($1, $2, $3, $4, $5) RETURNING id`, invitationsTable)
+		row = r.db.QueryRow(query, listId, inviterId, role, tokenHash, expiresAt)
+	} else {
+		query := fmt.Sprintf(`INSERT INTO %s (list_id, inviter_id, invitee_id, role, token_hash, expires_at)
+									SELECT $1, $2, u.id, $4, $5, $6 FROM %s u WHERE u.username = $3 RETURNING id`,
+			invitationsTable, usersTable)
+		row = r.db.QueryRow(query, listId, inviterId, username, role, tokenHash, expiresAt)
+	}
+
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Действующие (не истекшие) приглашения в список
+func (r *InvitationsPostgres) GetByList(listId int) ([]todo.Invitation, error) {
+	var invitations []todo.Invitation
+	query := fmt.Sprintf("%s WHERE i.list_id = $1 AND i.status = '%s' AND i.expires_at > now() ORDER BY i.id",
+		invitationsQuery, todo.InvitationPending)
+	err := r.db.Select(&invitations, query, listId)
+
+	return invitations, err
+}
+
+// Действующие приглашения, адресованные пользователю
+func (r *InvitationsPostgres) GetPending(userId int) ([]todo.Invitation, error) {
+	var invitations []todo.Invitation
+	query := fmt.Sprintf("%s WHERE i.invitee_id = $1 AND i.status = '%s' AND i.expires_at > now() ORDER BY i.id",
+		invitationsQuery, todo.InvitationPending)
+	err := r.db.Select(&invitations, query, userId)
+
+	return invitations, err
+}
+
+func (r *InvitationsPostgres) Revoke(listId, invitationId int) error {
+	var id int
+	query := fmt.Sprintf("UPDATE %s SET status = $1, responded_at = now() WHERE id = $2 AND list_id = $3 AND status = $4 RETURNING id",
+		invitationsTable)
+
+	return r.db.QueryRow(query, todo.InvitationRevoked, invitationId, listId, todo.InvitationPending).Scan(&id)
+}
+
+func (r *InvitationsPostgres) Decline(userId, invitationId int) error {
+	var id int
+	query := fmt.Sprintf(`UPDATE %s SET status = $1, responded_at = now()
+									WHERE id = $2 AND invitee_id = $3 AND status = $4 AND expires_at > now() RETURNING id`,
+		invitationsTable)
+
+	return r.db.QueryRow(query, todo.InvitationDeclined, invitationId, userId, todo.InvitationPending).Scan(&id)
+}
+
+// Принятие адресованного пользователю приглашения, возвращает id списка
+func (r *InvitationsPostgres) Accept(userId, invitationId int) (int, error) {
+	return r.accept(userId, "id = $1 AND invitee_id = $2", invitationId, userId)
+}
+
+// Принятие приглашения по ссылке, возвращает id списка
+func (r *InvitationsPostgres) AcceptToken(userId int, tokenHash string) (int, error) {
+	return r.accept(userId, "token_hash = $1", tokenHash)
+}
+
+// Приглашение блокируется до конца транзакции, поэтому одно приглашение (в том числе ссылку)
+// нельзя принять дважды. Пользователь добавляется в конец своих списков с ролью из приглашения
+func (r *InvitationsPostgres) accept(userId int, filter string, args ...interface{}) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var invitation struct {
+		Id     int           `db:"id"`
+		ListId int           `db:"list_id"`
+		Role   todo.ListRole `db:"role"`
+	}
+	selectQuery := fmt.Sprintf(`SELECT id, list_id, role FROM %s
+									WHERE %s AND status = '%s' AND expires_at > now() FOR UPDATE`,
+		invitationsTable, filter, todo.InvitationPending)
+	if err := tx.Get(&invitation, selectQuery, args...); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var last string
+	lastPositionQuery := fmt.Sprintf("SELECT COALESCE(max(position), '') FROM %s WHERE user_id = $1", usersListsTable)
+	if err := tx.Get(&last, lastPositionQuery, userId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var memberRowId int
+	addQuery := fmt.Sprintf(`INSERT INTO %s (user_id, list_id, position, role) VALUES ($1, $2, $3, $4)
+									ON CONFLICT (user_id, list_id) DO NOTHING RETURNING id`, usersListsTable)
+	err = tx.QueryRow(addQuery, userId, invitation.ListId, nextPosition(last), invitation.Role).Scan(&memberRowId)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, errors.New("already a member of this list")
+		}
+		return 0, err
+	}
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET status = $1, invitee_id = $2, responded_at = now() WHERE id = $3", invitationsTable)
+	if _, err := tx.Exec(updateQuery, todo.InvitationAccepted, userId, invitation.Id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return invitation.ListId, tx.Commit()
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestInvitationsPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewInvitationsPostgres(db)
+
+	expiresAt := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	type args struct {
+		username  string
+		tokenHash *string
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		input   args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "By Username",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO invitations (.+) SELECT (.+) FROM users u WHERE u.username").
+					WithArgs(3, 1, "bob", todo.ListRoleEditor, nil, expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+			},
+			input: args{username: "bob"},
+			want:  5,
+		},
+		{
+			name: "Unknown Username",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO invitations (.+) SELECT (.+) FROM users u WHERE u.username").
+					WithArgs(3, 1, "bob", todo.ListRoleEditor, nil, expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			input:   args{username: "bob"},
+			wantErr: true,
+		},
+		{
+			name: "Invite Link",
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO invitations (.+) VALUES").
+					WithArgs(3, 1, todo.ListRoleEditor, "hash", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+			},
+			input: args{tokenHash: stringPointer("hash")},
+			want:  6,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Create(1, 3, testCase.input.username, todo.ListRoleEditor, expiresAt, testCase.input.tokenHash)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInvitationsPostgres_Accept(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewInvitationsPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, list_id, role FROM invitations WHERE (.+) FOR UPDATE").WithArgs(5, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "role"}).AddRow(5, 3, "viewer"))
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM user_lists").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				mock.ExpectQuery("INSERT INTO user_lists (.+) ON CONFLICT \\(user_id, list_id\\) DO NOTHING").
+					WithArgs(2, 3, "V", todo.ListRoleViewer).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+
+				mock.ExpectExec("UPDATE invitations SET status").WithArgs(todo.InvitationAccepted, 2, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+			want: 3,
+		},
+		{
+			name: "Expired Or Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, list_id, role FROM invitations WHERE (.+) FOR UPDATE").WithArgs(5, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "role"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Already Member",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, list_id, role FROM invitations WHERE (.+) FOR UPDATE").WithArgs(5, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "role"}).AddRow(5, 3, "viewer"))
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM user_lists").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
+				mock.ExpectQuery("INSERT INTO user_lists (.+) ON CONFLICT \\(user_id, list_id\\) DO NOTHING").
+					WithArgs(2, 3, "k", todo.ListRoleViewer).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Failed Update",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id, list_id, role FROM invitations WHERE (.+) FOR UPDATE").WithArgs(5, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "role"}).AddRow(5, 3, "viewer"))
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM user_lists").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				mock.ExpectQuery("INSERT INTO user_lists (.+) ON CONFLICT \\(user_id, list_id\\) DO NOTHING").
+					WithArgs(2, 3, "V", todo.ListRoleViewer).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+
+				mock.ExpectExec("UPDATE invitations SET status").WithArgs(todo.InvitationAccepted, 2, 5).
+					WillReturnError(errors.New("update error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Accept(2, 5)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInvitationsPostgres_Revoke(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewInvitationsPostgres(db)
+
+	mock.ExpectQuery("UPDATE invitations SET status (.+) RETURNING id").
+		WithArgs(todo.InvitationRevoked, 5, 3, todo.InvitationPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	assert.NoError(t, r.Revoke(3, 5))
+
+	mock.ExpectQuery("UPDATE invitations SET status (.+) RETURNING id").
+		WithArgs(todo.InvitationRevoked, 6, 3, todo.InvitationPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	assert.Error(t, r.Revoke(3, 6))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
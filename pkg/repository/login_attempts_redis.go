@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// Счетчики неудачных попыток входа и блокировки входа по username и ip.
+// Все ключи живут ограниченное время, поэтому отдельная очистка не нужна
+type LoginAttemptsRedis struct {
+	context     *gin.Context
+	redisClient *redis.Client
+}
+
+func NewLoginAttemptsRedis(context *gin.Context, redisClient *redis.Client) *LoginAttemptsRedis {
+	return &LoginAttemptsRedis{
+		context:     context,
+		redisClient: redisClient,
+	}
+}
+
+func loginFailsUserKey(username string) string { return "login:fails:user:" + username }
+func loginFailsIpKey(ip string) string         { return "login:fails:ip:" + ip }
+func loginDelayUserKey(username string) string { return "login:delay:user:" + username }
+func loginDelayIpKey(ip string) string         { return "login:delay:ip:" + ip }
+func loginLockKey(username string) string      { return "login:lock:" + username }
+func loginChallengeKey(id string) string       { return "login:2fa:" + id }
+
+// Оставшееся время блокировки учетной записи, задержки по username и задержки по ip (0 - нет)
+func (r *LoginAttemptsRedis) Blocked(username, ip string) (time.Duration, time.Duration, time.Duration, error) {
+	pipe := r.redisClient.Pipeline()
+	lock := pipe.PTTL(r.context, loginLockKey(username))
+	userDelay := pipe.PTTL(r.context, loginDelayUserKey(username))
+	ipDelay := pipe.PTTL(r.context, loginDelayIpKey(ip))
+	if _, err := pipe.Exec(r.context); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return positive(lock.Val()), positive(userDelay.Val()), positive(ipDelay.Val()), nil
+}
+
+// Учет неудачной попытки, возвращает число неудач по username и по ip за окно window
+// (окно продлевается с каждой неудачей)
+func (r *LoginAttemptsRedis) Fail(username, ip string, window time.Duration) (int64, int64, error) {
+	pipe := r.redisClient.TxPipeline()
+	userFails := pipe.Incr(r.context, loginFailsUserKey(username))
+	pipe.Expire(r.context, loginFailsUserKey(username), window)
+	ipFails := pipe.Incr(r.context, loginFailsIpKey(ip))
+	pipe.Expire(r.context, loginFailsIpKey(ip), window)
+	if _, err := pipe.Exec(r.context); err != nil {
+		return 0, 0, err
+	}
+
+	return userFails.Val(), ipFails.Val(), nil
+}
+
+// Задержка перед следующей попыткой по username и/или ip (ttl 0 - без задержки)
+func (r *LoginAttemptsRedis) Delay(username, ip string, userTTL, ipTTL time.Duration) error {
+	pipe := r.redisClient.Pipeline()
+	if userTTL > 0 {
+		pipe.Set(r.context, loginDelayUserKey(username), 1, userTTL)
+	}
+	if ipTTL > 0 {
+		pipe.Set(r.context, loginDelayIpKey(ip), 1, ipTTL)
+	}
+	_, err := pipe.Exec(r.context)
+
+	return err
+}
+
+// Блокировка учетной записи, счетчик неудач по username начинается заново после снятия блокировки
+func (r *LoginAttemptsRedis) Lock(username string, ttl time.Duration) error {
+	pipe := r.redisClient.TxPipeline()
+	pipe.Set(r.context, loginLockKey(username), 1, ttl)
+	pipe.Del(r.context, loginFailsUserKey(username), loginDelayUserKey(username))
+	_, err := pipe.Exec(r.context)
+
+	return err
+}
+
+// Сброс счетчика по username после успешного входа. Счетчик по ip не сбрасывается,
+// чтобы вход в свою учетную запись не позволял продолжать подбор чужих
+func (r *LoginAttemptsRedis) Reset(username string) error {
+	return r.redisClient.Del(r.context, loginFailsUserKey(username), loginDelayUserKey(username)).Err()
+}
+
+// Учет попытки ввода кода 2FA, возвращает число попыток с токеном второго шага challengeId.
+// ttl - не меньше времени жизни токена
+func (r *LoginAttemptsRedis) ChallengeAttempt(challengeId string, ttl time.Duration) (int64, error) {
+	pipe := r.redisClient.TxPipeline()
+	attempts := pipe.Incr(r.context, loginChallengeKey(challengeId))
+	pipe.Expire(r.context, loginChallengeKey(challengeId), ttl)
+	if _, err := pipe.Exec(r.context); err != nil {
+		return 0, err
+	}
+
+	return attempts.Val(), nil
+}
+
+// Использованный токен второго шага помечается исчерпавшим все попытки
+func (r *LoginAttemptsRedis) ChallengeDone(challengeId string, ttl time.Duration) error {
+	return r.redisClient.Set(r.context, loginChallengeKey(challengeId), math.MaxInt32, ttl).Err()
+}
+
+// PTTL возвращает -1/-2 для ключа без срока или отсутствующего ключа
+func positive(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptsRedis_Blocked(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	r := NewLoginAttemptsRedis(&gin.Context{}, db)
+
+	mock.ExpectPTTL("login:lock:alex").SetVal(-2) // ключа нет
+	mock.ExpectPTTL("login:delay:user:alex").SetVal(4 * time.Second)
+	mock.ExpectPTTL("login:delay:ip:192.0.2.1").SetVal(-2)
+
+	lock, userDelay, ipDelay, err := r.Blocked("alex", "192.0.2.1")
+	assert.NoError(t, err)
+	assert.Zero(t, lock)
+	assert.Equal(t, 4*time.Second, userDelay)
+	assert.Zero(t, ipDelay)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptsRedis_Fail(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	r := NewLoginAttemptsRedis(&gin.Context{}, db)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectIncr("login:fails:user:alex").SetVal(3)
+	mock.ExpectExpire("login:fails:user:alex", 15*time.Minute).SetVal(true)
+	mock.ExpectIncr("login:fails:ip:192.0.2.1").SetVal(7)
+	mock.ExpectExpire("login:fails:ip:192.0.2.1", 15*time.Minute).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	userFails, ipFails, err := r.Fail("alex", "192.0.2.1", 15*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), userFails)
+	assert.Equal(t, int64(7), ipFails)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptsRedis_Lock(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	r := NewLoginAttemptsRedis(&gin.Context{}, db)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectSet("login:lock:alex", 1, 15*time.Minute).SetVal("OK")
+	mock.ExpectDel("login:fails:user:alex", "login:delay:user:alex").SetVal(2)
+	mock.ExpectTxPipelineExec()
+
+	assert.NoError(t, r.Lock("alex", 15*time.Minute))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptsRedis_Challenge(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	r := NewLoginAttemptsRedis(&gin.Context{}, db)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectIncr("login:2fa:abc").SetVal(2)
+	mock.ExpectExpire("login:2fa:abc", 5*time.Minute).SetVal(true)
+	mock.ExpectTxPipelineExec()
+
+	attempts, err := r.ChallengeAttempt("abc", 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), attempts)
+
+	mock.ExpectSet("login:2fa:abc", math.MaxInt32, 5*time.Minute).SetVal("OK")
+	assert.NoError(t, r.ChallengeDone("abc", 5*time.Minute))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
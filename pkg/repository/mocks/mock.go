@@ -0,0 +1,2037 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+	time "time"
+	todo "todo-app"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuthorization is a mock of Authorization interface.
+type MockAuthorization struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationMockRecorder
+}
+
+// MockAuthorizationMockRecorder is the mock recorder for MockAuthorization.
+type MockAuthorizationMockRecorder struct {
+	mock *MockAuthorization
+}
+
+// NewMockAuthorization creates a new mock instance.
+func NewMockAuthorization(ctrl *gomock.Controller) *MockAuthorization {
+	mock := &MockAuthorization{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorization) EXPECT() *MockAuthorizationMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockAuthorization) CreateUser(user todo.User) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockAuthorizationMockRecorder) CreateUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthorization)(nil).CreateUser), user)
+}
+
+// DeleteUser mocks base method.
+func (m *MockAuthorization) DeleteUser(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockAuthorizationMockRecorder) DeleteUser(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAuthorization)(nil).DeleteUser), userId)
+}
+
+// GetUser mocks base method.
+func (m *MockAuthorization) GetUser(username string) (todo.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", username)
+	ret0, _ := ret[0].(todo.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockAuthorizationMockRecorder) GetUser(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockAuthorization)(nil).GetUser), username)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockAuthorization) GetUserByEmail(email string) (todo.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", email)
+	ret0, _ := ret[0].(todo.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockAuthorizationMockRecorder) GetUserByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockAuthorization)(nil).GetUserByEmail), email)
+}
+
+// GetUserById mocks base method.
+func (m *MockAuthorization) GetUserById(userId int) (todo.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", userId)
+	ret0, _ := ret[0].(todo.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockAuthorizationMockRecorder) GetUserById(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockAuthorization)(nil).GetUserById), userId)
+}
+
+// SetEmailVerified mocks base method.
+func (m *MockAuthorization) SetEmailVerified(userId int, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmailVerified", userId, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEmailVerified indicates an expected call of SetEmailVerified.
+func (mr *MockAuthorizationMockRecorder) SetEmailVerified(userId, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmailVerified", reflect.TypeOf((*MockAuthorization)(nil).SetEmailVerified), userId, email)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockAuthorization) UpdatePasswordHash(userId int, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", userId, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockAuthorizationMockRecorder) UpdatePasswordHash(userId, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockAuthorization)(nil).UpdatePasswordHash), userId, hash)
+}
+
+// UpdateUser mocks base method.
+func (m *MockAuthorization) UpdateUser(userId int, input todo.UpdateUserInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", userId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockAuthorizationMockRecorder) UpdateUser(userId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockAuthorization)(nil).UpdateUser), userId, input)
+}
+
+// MockTodoList is a mock of TodoList interface.
+type MockTodoList struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoListMockRecorder
+}
+
+// MockTodoListMockRecorder is the mock recorder for MockTodoList.
+type MockTodoListMockRecorder struct {
+	mock *MockTodoList
+}
+
+// NewMockTodoList creates a new mock instance.
+func NewMockTodoList(ctrl *gomock.Controller) *MockTodoList {
+	mock := &MockTodoList{ctrl: ctrl}
+	mock.recorder = &MockTodoListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoList) EXPECT() *MockTodoListMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockTodoList) AddMember(listId int, username string, role todo.ListRole) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", listId, username, role)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockTodoListMockRecorder) AddMember(listId, username, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockTodoList)(nil).AddMember), listId, username, role)
+}
+
+// Create mocks base method.
+func (m *MockTodoList) Create(userId int, list todo.TodoList) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, list)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTodoListMockRecorder) Create(userId, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoList)(nil).Create), userId, list)
+}
+
+// DeleteById mocks base method.
+func (m *MockTodoList) DeleteById(userId, listId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteById", userId, listId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteById indicates an expected call of DeleteById.
+func (mr *MockTodoListMockRecorder) DeleteById(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockTodoList)(nil).DeleteById), userId, listId)
+}
+
+// GetAll mocks base method.
+func (m *MockTodoList) GetAll(userId int, query todo.ListsQuery) ([]todo.TodoList, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId, query)
+	ret0, _ := ret[0].([]todo.TodoList)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTodoListMockRecorder) GetAll(userId, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoList)(nil).GetAll), userId, query)
+}
+
+// GetById mocks base method.
+func (m *MockTodoList) GetById(userId, listId int) (todo.TodoList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", userId, listId)
+	ret0, _ := ret[0].(todo.TodoList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTodoListMockRecorder) GetById(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoList)(nil).GetById), userId, listId)
+}
+
+// GetCoMemberIds mocks base method.
+func (m *MockTodoList) GetCoMemberIds(userId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoMemberIds", userId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoMemberIds indicates an expected call of GetCoMemberIds.
+func (mr *MockTodoListMockRecorder) GetCoMemberIds(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoMemberIds", reflect.TypeOf((*MockTodoList)(nil).GetCoMemberIds), userId)
+}
+
+// GetMemberIds mocks base method.
+func (m *MockTodoList) GetMemberIds(listId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberIds", listId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberIds indicates an expected call of GetMemberIds.
+func (mr *MockTodoListMockRecorder) GetMemberIds(listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberIds", reflect.TypeOf((*MockTodoList)(nil).GetMemberIds), listId)
+}
+
+// GetMembers mocks base method.
+func (m *MockTodoList) GetMembers(listId int) ([]todo.ListMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", listId)
+	ret0, _ := ret[0].([]todo.ListMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockTodoListMockRecorder) GetMembers(listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockTodoList)(nil).GetMembers), listId)
+}
+
+// GetRole mocks base method.
+func (m *MockTodoList) GetRole(userId, listId int) (todo.ListRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRole", userId, listId)
+	ret0, _ := ret[0].(todo.ListRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockTodoListMockRecorder) GetRole(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockTodoList)(nil).GetRole), userId, listId)
+}
+
+// Move mocks base method.
+func (m *MockTodoList) Move(userId, listId int, input todo.MoveInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", userId, listId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTodoListMockRecorder) Move(userId, listId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTodoList)(nil).Move), userId, listId, input)
+}
+
+// RemoveMember mocks base method.
+func (m *MockTodoList) RemoveMember(listId int, username string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", listId, username)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockTodoListMockRecorder) RemoveMember(listId, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockTodoList)(nil).RemoveMember), listId, username)
+}
+
+// UpdateById mocks base method.
+func (m *MockTodoList) UpdateById(userId, listId int, list todo.UpdateListInput) (todo.TodoList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateById", userId, listId, list)
+	ret0, _ := ret[0].(todo.TodoList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateById indicates an expected call of UpdateById.
+func (mr *MockTodoListMockRecorder) UpdateById(userId, listId, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateById", reflect.TypeOf((*MockTodoList)(nil).UpdateById), userId, listId, list)
+}
+
+// MockTodoItem is a mock of TodoItem interface.
+type MockTodoItem struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoItemMockRecorder
+}
+
+// MockTodoItemMockRecorder is the mock recorder for MockTodoItem.
+type MockTodoItemMockRecorder struct {
+	mock *MockTodoItem
+}
+
+// NewMockTodoItem creates a new mock instance.
+func NewMockTodoItem(ctrl *gomock.Controller) *MockTodoItem {
+	mock := &MockTodoItem{ctrl: ctrl}
+	mock.recorder = &MockTodoItemMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoItem) EXPECT() *MockTodoItemMockRecorder {
+	return m.recorder
+}
+
+// CopyToList mocks base method.
+func (m *MockTodoItem) CopyToList(userId, itemId, listId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyToList", userId, itemId, listId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyToList indicates an expected call of CopyToList.
+func (mr *MockTodoItemMockRecorder) CopyToList(userId, itemId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyToList", reflect.TypeOf((*MockTodoItem)(nil).CopyToList), userId, itemId, listId)
+}
+
+// Create mocks base method.
+func (m *MockTodoItem) Create(listId int, item todo.TodoItem) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", listId, item)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTodoItemMockRecorder) Create(listId, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTodoItem)(nil).Create), listId, item)
+}
+
+// Delete mocks base method.
+func (m *MockTodoItem) Delete(userId, itemId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, itemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTodoItemMockRecorder) Delete(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItem)(nil).Delete), userId, itemId)
+}
+
+// GetAll mocks base method.
+func (m *MockTodoItem) GetAll(userId, listId int, query todo.ItemsQuery) ([]todo.TodoItem, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId, listId, query)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTodoItemMockRecorder) GetAll(userId, listId, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTodoItem)(nil).GetAll), userId, listId, query)
+}
+
+// GetById mocks base method.
+func (m *MockTodoItem) GetById(userId, itemId int) (todo.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", userId, itemId)
+	ret0, _ := ret[0].(todo.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTodoItemMockRecorder) GetById(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTodoItem)(nil).GetById), userId, itemId)
+}
+
+// GetOverdue mocks base method.
+func (m *MockTodoItem) GetOverdue(userId, listId int) ([]todo.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdue", userId, listId)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdue indicates an expected call of GetOverdue.
+func (mr *MockTodoItemMockRecorder) GetOverdue(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdue", reflect.TypeOf((*MockTodoItem)(nil).GetOverdue), userId, listId)
+}
+
+// GetSubtreeIds mocks base method.
+func (m *MockTodoItem) GetSubtreeIds(userId, itemId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubtreeIds", userId, itemId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubtreeIds indicates an expected call of GetSubtreeIds.
+func (mr *MockTodoItemMockRecorder) GetSubtreeIds(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtreeIds", reflect.TypeOf((*MockTodoItem)(nil).GetSubtreeIds), userId, itemId)
+}
+
+// Move mocks base method.
+func (m *MockTodoItem) Move(userId, listId, itemId int, input todo.MoveInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", userId, listId, itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockTodoItemMockRecorder) Move(userId, listId, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockTodoItem)(nil).Move), userId, listId, itemId, input)
+}
+
+// MoveToList mocks base method.
+func (m *MockTodoItem) MoveToList(userId, itemId, listId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToList", userId, itemId, listId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveToList indicates an expected call of MoveToList.
+func (mr *MockTodoItemMockRecorder) MoveToList(userId, itemId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToList", reflect.TypeOf((*MockTodoItem)(nil).MoveToList), userId, itemId, listId)
+}
+
+// Update mocks base method.
+func (m *MockTodoItem) Update(userId, itemId int, input todo.UpdateItemInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, itemId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTodoItemMockRecorder) Update(userId, itemId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTodoItem)(nil).Update), userId, itemId, input)
+}
+
+// MockTags is a mock of Tags interface.
+type MockTags struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagsMockRecorder
+}
+
+// MockTagsMockRecorder is the mock recorder for MockTags.
+type MockTagsMockRecorder struct {
+	mock *MockTags
+}
+
+// NewMockTags creates a new mock instance.
+func NewMockTags(ctrl *gomock.Controller) *MockTags {
+	mock := &MockTags{ctrl: ctrl}
+	mock.recorder = &MockTagsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTags) EXPECT() *MockTagsMockRecorder {
+	return m.recorder
+}
+
+// Attach mocks base method.
+func (m *MockTags) Attach(itemId, tagId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attach", itemId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attach indicates an expected call of Attach.
+func (mr *MockTagsMockRecorder) Attach(itemId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attach", reflect.TypeOf((*MockTags)(nil).Attach), itemId, tagId)
+}
+
+// Create mocks base method.
+func (m *MockTags) Create(userId int, tag todo.Tag) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, tag)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagsMockRecorder) Create(userId, tag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTags)(nil).Create), userId, tag)
+}
+
+// Delete mocks base method.
+func (m *MockTags) Delete(userId, tagId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagsMockRecorder) Delete(userId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTags)(nil).Delete), userId, tagId)
+}
+
+// Detach mocks base method.
+func (m *MockTags) Detach(userId, itemId, tagId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detach", userId, itemId, tagId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Detach indicates an expected call of Detach.
+func (mr *MockTagsMockRecorder) Detach(userId, itemId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detach", reflect.TypeOf((*MockTags)(nil).Detach), userId, itemId, tagId)
+}
+
+// GetAll mocks base method.
+func (m *MockTags) GetAll(userId int) ([]todo.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]todo.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockTagsMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockTags)(nil).GetAll), userId)
+}
+
+// GetById mocks base method.
+func (m *MockTags) GetById(userId, tagId int) (todo.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", userId, tagId)
+	ret0, _ := ret[0].(todo.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockTagsMockRecorder) GetById(userId, tagId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockTags)(nil).GetById), userId, tagId)
+}
+
+// GetByItem mocks base method.
+func (m *MockTags) GetByItem(userId, itemId int) ([]todo.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByItem", userId, itemId)
+	ret0, _ := ret[0].([]todo.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByItem indicates an expected call of GetByItem.
+func (mr *MockTagsMockRecorder) GetByItem(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByItem", reflect.TypeOf((*MockTags)(nil).GetByItem), userId, itemId)
+}
+
+// GetItems mocks base method.
+func (m *MockTags) GetItems(userId int, names []string, matchAll bool) ([]todo.TodoItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", userId, names, matchAll)
+	ret0, _ := ret[0].([]todo.TodoItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockTagsMockRecorder) GetItems(userId, names, matchAll interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockTags)(nil).GetItems), userId, names, matchAll)
+}
+
+// Update mocks base method.
+func (m *MockTags) Update(userId, tagId int, input todo.UpdateTagInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", userId, tagId, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagsMockRecorder) Update(userId, tagId, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTags)(nil).Update), userId, tagId, input)
+}
+
+// MockReminders is a mock of Reminders interface.
+type MockReminders struct {
+	ctrl     *gomock.Controller
+	recorder *MockRemindersMockRecorder
+}
+
+// MockRemindersMockRecorder is the mock recorder for MockReminders.
+type MockRemindersMockRecorder struct {
+	mock *MockReminders
+}
+
+// NewMockReminders creates a new mock instance.
+func NewMockReminders(ctrl *gomock.Controller) *MockReminders {
+	mock := &MockReminders{ctrl: ctrl}
+	mock.recorder = &MockRemindersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReminders) EXPECT() *MockRemindersMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockReminders) ClaimDue(limit int) ([]todo.DueReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", limit)
+	ret0, _ := ret[0].([]todo.DueReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockRemindersMockRecorder) ClaimDue(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockReminders)(nil).ClaimDue), limit)
+}
+
+// Create mocks base method.
+func (m *MockReminders) Create(userId, itemId int, reminder todo.Reminder) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, itemId, reminder)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRemindersMockRecorder) Create(userId, itemId, reminder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockReminders)(nil).Create), userId, itemId, reminder)
+}
+
+// Delete mocks base method.
+func (m *MockReminders) Delete(userId, reminderId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, reminderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRemindersMockRecorder) Delete(userId, reminderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReminders)(nil).Delete), userId, reminderId)
+}
+
+// Fail mocks base method.
+func (m *MockReminders) Fail(reminderId int, message string, retry bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", reminderId, message, retry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockRemindersMockRecorder) Fail(reminderId, message, retry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockReminders)(nil).Fail), reminderId, message, retry)
+}
+
+// GetByItem mocks base method.
+func (m *MockReminders) GetByItem(userId, itemId int) ([]todo.Reminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByItem", userId, itemId)
+	ret0, _ := ret[0].([]todo.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByItem indicates an expected call of GetByItem.
+func (mr *MockRemindersMockRecorder) GetByItem(userId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByItem", reflect.TypeOf((*MockReminders)(nil).GetByItem), userId, itemId)
+}
+
+// MockInvitations is a mock of Invitations interface.
+type MockInvitations struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationsMockRecorder
+}
+
+// MockInvitationsMockRecorder is the mock recorder for MockInvitations.
+type MockInvitationsMockRecorder struct {
+	mock *MockInvitations
+}
+
+// NewMockInvitations creates a new mock instance.
+func NewMockInvitations(ctrl *gomock.Controller) *MockInvitations {
+	mock := &MockInvitations{ctrl: ctrl}
+	mock.recorder = &MockInvitationsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitations) EXPECT() *MockInvitationsMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockInvitations) Accept(userId, invitationId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", userId, invitationId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockInvitationsMockRecorder) Accept(userId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockInvitations)(nil).Accept), userId, invitationId)
+}
+
+// AcceptToken mocks base method.
+func (m *MockInvitations) AcceptToken(userId int, tokenHash string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptToken", userId, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptToken indicates an expected call of AcceptToken.
+func (mr *MockInvitationsMockRecorder) AcceptToken(userId, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptToken", reflect.TypeOf((*MockInvitations)(nil).AcceptToken), userId, tokenHash)
+}
+
+// Create mocks base method.
+func (m *MockInvitations) Create(inviterId, listId int, username string, role todo.ListRole, expiresAt time.Time, tokenHash *string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", inviterId, listId, username, role, expiresAt, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitationsMockRecorder) Create(inviterId, listId, username, role, expiresAt, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitations)(nil).Create), inviterId, listId, username, role, expiresAt, tokenHash)
+}
+
+// Decline mocks base method.
+func (m *MockInvitations) Decline(userId, invitationId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", userId, invitationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockInvitationsMockRecorder) Decline(userId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockInvitations)(nil).Decline), userId, invitationId)
+}
+
+// GetByList mocks base method.
+func (m *MockInvitations) GetByList(listId int) ([]todo.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByList", listId)
+	ret0, _ := ret[0].([]todo.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByList indicates an expected call of GetByList.
+func (mr *MockInvitationsMockRecorder) GetByList(listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByList", reflect.TypeOf((*MockInvitations)(nil).GetByList), listId)
+}
+
+// GetPending mocks base method.
+func (m *MockInvitations) GetPending(userId int) ([]todo.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", userId)
+	ret0, _ := ret[0].([]todo.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockInvitationsMockRecorder) GetPending(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockInvitations)(nil).GetPending), userId)
+}
+
+// Revoke mocks base method.
+func (m *MockInvitations) Revoke(listId, invitationId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", listId, invitationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockInvitationsMockRecorder) Revoke(listId, invitationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockInvitations)(nil).Revoke), listId, invitationId)
+}
+
+// MockWorkspaces is a mock of Workspaces interface.
+type MockWorkspaces struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkspacesMockRecorder
+}
+
+// MockWorkspacesMockRecorder is the mock recorder for MockWorkspaces.
+type MockWorkspacesMockRecorder struct {
+	mock *MockWorkspaces
+}
+
+// NewMockWorkspaces creates a new mock instance.
+func NewMockWorkspaces(ctrl *gomock.Controller) *MockWorkspaces {
+	mock := &MockWorkspaces{ctrl: ctrl}
+	mock.recorder = &MockWorkspacesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkspaces) EXPECT() *MockWorkspacesMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockWorkspaces) AddMember(workspaceId int, username string, role todo.ListRole) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", workspaceId, username, role)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockWorkspacesMockRecorder) AddMember(workspaceId, username, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockWorkspaces)(nil).AddMember), workspaceId, username, role)
+}
+
+// Create mocks base method.
+func (m *MockWorkspaces) Create(userId int, workspace todo.Workspace) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, workspace)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWorkspacesMockRecorder) Create(userId, workspace interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWorkspaces)(nil).Create), userId, workspace)
+}
+
+// GetAll mocks base method.
+func (m *MockWorkspaces) GetAll(userId int) ([]todo.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]todo.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWorkspacesMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWorkspaces)(nil).GetAll), userId)
+}
+
+// GetById mocks base method.
+func (m *MockWorkspaces) GetById(userId, workspaceId int) (todo.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", userId, workspaceId)
+	ret0, _ := ret[0].(todo.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockWorkspacesMockRecorder) GetById(userId, workspaceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockWorkspaces)(nil).GetById), userId, workspaceId)
+}
+
+// GetLists mocks base method.
+func (m *MockWorkspaces) GetLists(userId, workspaceId int) ([]todo.TodoList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLists", userId, workspaceId)
+	ret0, _ := ret[0].([]todo.TodoList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLists indicates an expected call of GetLists.
+func (mr *MockWorkspacesMockRecorder) GetLists(userId, workspaceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLists", reflect.TypeOf((*MockWorkspaces)(nil).GetLists), userId, workspaceId)
+}
+
+// GetMembers mocks base method.
+func (m *MockWorkspaces) GetMembers(workspaceId int) ([]todo.ListMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", workspaceId)
+	ret0, _ := ret[0].([]todo.ListMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockWorkspacesMockRecorder) GetMembers(workspaceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockWorkspaces)(nil).GetMembers), workspaceId)
+}
+
+// RemoveMember mocks base method.
+func (m *MockWorkspaces) RemoveMember(workspaceId int, username string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", workspaceId, username)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockWorkspacesMockRecorder) RemoveMember(workspaceId, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockWorkspaces)(nil).RemoveMember), workspaceId, username)
+}
+
+// MockSessions is a mock of Sessions interface.
+type MockSessions struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsMockRecorder
+}
+
+// MockSessionsMockRecorder is the mock recorder for MockSessions.
+type MockSessionsMockRecorder struct {
+	mock *MockSessions
+}
+
+// NewMockSessions creates a new mock instance.
+func NewMockSessions(ctrl *gomock.Controller) *MockSessions {
+	mock := &MockSessions{ctrl: ctrl}
+	mock.recorder = &MockSessionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessions) EXPECT() *MockSessionsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSessions) Create(userId int, client todo.SessionClient, tokenHash string, expiresAt time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, client, tokenHash, expiresAt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionsMockRecorder) Create(userId, client, tokenHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessions)(nil).Create), userId, client, tokenHash, expiresAt)
+}
+
+// GetAll mocks base method.
+func (m *MockSessions) GetAll(userId int) ([]todo.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]todo.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSessionsMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSessions)(nil).GetAll), userId)
+}
+
+// Revoke mocks base method.
+func (m *MockSessions) Revoke(userId, sessionId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", userId, sessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionsMockRecorder) Revoke(userId, sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessions)(nil).Revoke), userId, sessionId)
+}
+
+// RevokeAll mocks base method.
+func (m *MockSessions) RevokeAll(userId, exceptSessionId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", userId, exceptSessionId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockSessionsMockRecorder) RevokeAll(userId, exceptSessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockSessions)(nil).RevokeAll), userId, exceptSessionId)
+}
+
+// Rotate mocks base method.
+func (m *MockSessions) Rotate(tokenHash, newTokenHash, ip string, expiresAt time.Time) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", tokenHash, newTokenHash, ip, expiresAt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockSessionsMockRecorder) Rotate(tokenHash, newTokenHash, ip, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockSessions)(nil).Rotate), tokenHash, newTokenHash, ip, expiresAt)
+}
+
+// MockAccessTokens is a mock of AccessTokens interface.
+type MockAccessTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokensMockRecorder
+}
+
+// MockAccessTokensMockRecorder is the mock recorder for MockAccessTokens.
+type MockAccessTokensMockRecorder struct {
+	mock *MockAccessTokens
+}
+
+// NewMockAccessTokens creates a new mock instance.
+func NewMockAccessTokens(ctrl *gomock.Controller) *MockAccessTokens {
+	mock := &MockAccessTokens{ctrl: ctrl}
+	mock.recorder = &MockAccessTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokens) EXPECT() *MockAccessTokensMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAccessTokens) Create(userId int, name string, scopes []string, tokenHash string, expiresAt *time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, name, scopes, tokenHash, expiresAt)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAccessTokensMockRecorder) Create(userId, name, scopes, tokenHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAccessTokens)(nil).Create), userId, name, scopes, tokenHash, expiresAt)
+}
+
+// Delete mocks base method.
+func (m *MockAccessTokens) Delete(userId, tokenId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId, tokenId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAccessTokensMockRecorder) Delete(userId, tokenId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAccessTokens)(nil).Delete), userId, tokenId)
+}
+
+// DeleteAll mocks base method.
+func (m *MockAccessTokens) DeleteAll(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockAccessTokensMockRecorder) DeleteAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockAccessTokens)(nil).DeleteAll), userId)
+}
+
+// GetAll mocks base method.
+func (m *MockAccessTokens) GetAll(userId int) ([]todo.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", userId)
+	ret0, _ := ret[0].([]todo.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockAccessTokensMockRecorder) GetAll(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockAccessTokens)(nil).GetAll), userId)
+}
+
+// GetByHash mocks base method.
+func (m *MockAccessTokens) GetByHash(tokenHash string) (todo.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", tokenHash)
+	ret0, _ := ret[0].(todo.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAccessTokensMockRecorder) GetByHash(tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAccessTokens)(nil).GetByHash), tokenHash)
+}
+
+// MockTwoFactor is a mock of TwoFactor interface.
+type MockTwoFactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorMockRecorder
+}
+
+// MockTwoFactorMockRecorder is the mock recorder for MockTwoFactor.
+type MockTwoFactorMockRecorder struct {
+	mock *MockTwoFactor
+}
+
+// NewMockTwoFactor creates a new mock instance.
+func NewMockTwoFactor(ctrl *gomock.Controller) *MockTwoFactor {
+	mock := &MockTwoFactor{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactor) EXPECT() *MockTwoFactorMockRecorder {
+	return m.recorder
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactor) Disable(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorMockRecorder) Disable(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactor)(nil).Disable), userId)
+}
+
+// Enable mocks base method.
+func (m *MockTwoFactor) Enable(userId int, step int64, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enable", userId, step, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enable indicates an expected call of Enable.
+func (mr *MockTwoFactorMockRecorder) Enable(userId, step, codeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enable", reflect.TypeOf((*MockTwoFactor)(nil).Enable), userId, step, codeHashes)
+}
+
+// Get mocks base method.
+func (m *MockTwoFactor) Get(userId int) (todo.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", userId)
+	ret0, _ := ret[0].(todo.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTwoFactorMockRecorder) Get(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTwoFactor)(nil).Get), userId)
+}
+
+// SetPendingSecret mocks base method.
+func (m *MockTwoFactor) SetPendingSecret(userId int, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingSecret", userId, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingSecret indicates an expected call of SetPendingSecret.
+func (mr *MockTwoFactorMockRecorder) SetPendingSecret(userId, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingSecret", reflect.TypeOf((*MockTwoFactor)(nil).SetPendingSecret), userId, secret)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactor) UseRecoveryCode(userId int, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userId, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorMockRecorder) UseRecoveryCode(userId, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactor)(nil).UseRecoveryCode), userId, codeHash)
+}
+
+// UseStep mocks base method.
+func (m *MockTwoFactor) UseStep(userId int, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseStep", userId, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseStep indicates an expected call of UseStep.
+func (mr *MockTwoFactorMockRecorder) UseStep(userId, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseStep", reflect.TypeOf((*MockTwoFactor)(nil).UseStep), userId, step)
+}
+
+// MockEmailTokens is a mock of EmailTokens interface.
+type MockEmailTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailTokensMockRecorder
+}
+
+// MockEmailTokensMockRecorder is the mock recorder for MockEmailTokens.
+type MockEmailTokensMockRecorder struct {
+	mock *MockEmailTokens
+}
+
+// NewMockEmailTokens creates a new mock instance.
+func NewMockEmailTokens(ctrl *gomock.Controller) *MockEmailTokens {
+	mock := &MockEmailTokens{ctrl: ctrl}
+	mock.recorder = &MockEmailTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailTokens) EXPECT() *MockEmailTokensMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEmailTokens) Create(userId int, purpose, email, tokenHash string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userId, purpose, email, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailTokensMockRecorder) Create(userId, purpose, email, tokenHash, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailTokens)(nil).Create), userId, purpose, email, tokenHash, expiresAt)
+}
+
+// Use mocks base method.
+func (m *MockEmailTokens) Use(purpose, tokenHash string) (int, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", purpose, tokenHash)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockEmailTokensMockRecorder) Use(purpose, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockEmailTokens)(nil).Use), purpose, tokenHash)
+}
+
+// MockAudit is a mock of Audit interface.
+type MockAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditMockRecorder
+}
+
+// MockAuditMockRecorder is the mock recorder for MockAudit.
+type MockAuditMockRecorder struct {
+	mock *MockAudit
+}
+
+// NewMockAudit creates a new mock instance.
+func NewMockAudit(ctrl *gomock.Controller) *MockAudit {
+	mock := &MockAudit{ctrl: ctrl}
+	mock.recorder = &MockAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudit) EXPECT() *MockAuditMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAudit) Create(record todo.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditMockRecorder) Create(record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAudit)(nil).Create), record)
+}
+
+// MockIdentities is a mock of Identities interface.
+type MockIdentities struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentitiesMockRecorder
+}
+
+// MockIdentitiesMockRecorder is the mock recorder for MockIdentities.
+type MockIdentitiesMockRecorder struct {
+	mock *MockIdentities
+}
+
+// NewMockIdentities creates a new mock instance.
+func NewMockIdentities(ctrl *gomock.Controller) *MockIdentities {
+	mock := &MockIdentities{ctrl: ctrl}
+	mock.recorder = &MockIdentitiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentities) EXPECT() *MockIdentitiesMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockIdentities) CreateUser(user todo.User, provider, subject string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", user, provider, subject)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockIdentitiesMockRecorder) CreateUser(user, provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockIdentities)(nil).CreateUser), user, provider, subject)
+}
+
+// GetUserId mocks base method.
+func (m *MockIdentities) GetUserId(provider, subject string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserId", provider, subject)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserId indicates an expected call of GetUserId.
+func (mr *MockIdentitiesMockRecorder) GetUserId(provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*MockIdentities)(nil).GetUserId), provider, subject)
+}
+
+// Link mocks base method.
+func (m *MockIdentities) Link(userId int, provider, subject, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", userId, provider, subject, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link.
+func (mr *MockIdentitiesMockRecorder) Link(userId, provider, subject, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockIdentities)(nil).Link), userId, provider, subject, email)
+}
+
+// MockArchive is a mock of Archive interface.
+type MockArchive struct {
+	ctrl     *gomock.Controller
+	recorder *MockArchiveMockRecorder
+}
+
+// MockArchiveMockRecorder is the mock recorder for MockArchive.
+type MockArchiveMockRecorder struct {
+	mock *MockArchive
+}
+
+// NewMockArchive creates a new mock instance.
+func NewMockArchive(ctrl *gomock.Controller) *MockArchive {
+	mock := &MockArchive{ctrl: ctrl}
+	mock.recorder = &MockArchiveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArchive) EXPECT() *MockArchiveMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockArchive) Export(userId int) (todo.Archive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", userId)
+	ret0, _ := ret[0].(todo.Archive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockArchiveMockRecorder) Export(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockArchive)(nil).Export), userId)
+}
+
+// Import mocks base method.
+func (m *MockArchive) Import(userId int, archive todo.Archive) (todo.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", userId, archive)
+	ret0, _ := ret[0].(todo.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockArchiveMockRecorder) Import(userId, archive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockArchive)(nil).Import), userId, archive)
+}
+
+// MockAdmin is a mock of Admin interface.
+type MockAdmin struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminMockRecorder
+}
+
+// MockAdminMockRecorder is the mock recorder for MockAdmin.
+type MockAdminMockRecorder struct {
+	mock *MockAdmin
+}
+
+// NewMockAdmin creates a new mock instance.
+func NewMockAdmin(ctrl *gomock.Controller) *MockAdmin {
+	mock := &MockAdmin{ctrl: ctrl}
+	mock.recorder = &MockAdminMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdmin) EXPECT() *MockAdminMockRecorder {
+	return m.recorder
+}
+
+// GetUser mocks base method.
+func (m *MockAdmin) GetUser(userId int) (todo.AdminUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", userId)
+	ret0, _ := ret[0].(todo.AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockAdminMockRecorder) GetUser(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockAdmin)(nil).GetUser), userId)
+}
+
+// GetUsers mocks base method.
+func (m *MockAdmin) GetUsers(query todo.AdminUsersQuery) ([]todo.AdminUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", query)
+	ret0, _ := ret[0].([]todo.AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockAdminMockRecorder) GetUsers(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockAdmin)(nil).GetUsers), query)
+}
+
+// SetDisabled mocks base method.
+func (m *MockAdmin) SetDisabled(userId int, disabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", userId, disabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockAdminMockRecorder) SetDisabled(userId, disabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockAdmin)(nil).SetDisabled), userId, disabled)
+}
+
+// MockSearch is a mock of Search interface.
+type MockSearch struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchMockRecorder
+}
+
+// MockSearchMockRecorder is the mock recorder for MockSearch.
+type MockSearchMockRecorder struct {
+	mock *MockSearch
+}
+
+// NewMockSearch creates a new mock instance.
+func NewMockSearch(ctrl *gomock.Controller) *MockSearch {
+	mock := &MockSearch{ctrl: ctrl}
+	mock.recorder = &MockSearchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearch) EXPECT() *MockSearchMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearch) Search(userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", userId, query)
+	ret0, _ := ret[0].([]todo.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchMockRecorder) Search(userId, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearch)(nil).Search), userId, query)
+}
+
+// MockTodoListCach is a mock of TodoListCach interface.
+type MockTodoListCach struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoListCachMockRecorder
+}
+
+// MockTodoListCachMockRecorder is the mock recorder for MockTodoListCach.
+type MockTodoListCachMockRecorder struct {
+	mock *MockTodoListCach
+}
+
+// NewMockTodoListCach creates a new mock instance.
+func NewMockTodoListCach(ctrl *gomock.Controller) *MockTodoListCach {
+	mock := &MockTodoListCach{ctrl: ctrl}
+	mock.recorder = &MockTodoListCachMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoListCach) EXPECT() *MockTodoListCachMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTodoListCach) Delete(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTodoListCachMockRecorder) Delete(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoListCach)(nil).Delete), userId)
+}
+
+// HDelete mocks base method.
+func (m *MockTodoListCach) HDelete(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDelete", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HDelete indicates an expected call of HDelete.
+func (mr *MockTodoListCachMockRecorder) HDelete(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDelete", reflect.TypeOf((*MockTodoListCach)(nil).HDelete), userId)
+}
+
+// HGet mocks base method.
+func (m *MockTodoListCach) HGet(userId, listId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", userId, listId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockTodoListCachMockRecorder) HGet(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockTodoListCach)(nil).HGet), userId, listId)
+}
+
+// HSet mocks base method.
+func (m *MockTodoListCach) HSet(userId, listId int, data string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", userId, listId, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockTodoListCachMockRecorder) HSet(userId, listId, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockTodoListCach)(nil).HSet), userId, listId, data)
+}
+
+// MockTodoItemCach is a mock of TodoItemCach interface.
+type MockTodoItemCach struct {
+	ctrl     *gomock.Controller
+	recorder *MockTodoItemCachMockRecorder
+}
+
+// MockTodoItemCachMockRecorder is the mock recorder for MockTodoItemCach.
+type MockTodoItemCachMockRecorder struct {
+	mock *MockTodoItemCach
+}
+
+// NewMockTodoItemCach creates a new mock instance.
+func NewMockTodoItemCach(ctrl *gomock.Controller) *MockTodoItemCach {
+	mock := &MockTodoItemCach{ctrl: ctrl}
+	mock.recorder = &MockTodoItemCachMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTodoItemCach) EXPECT() *MockTodoItemCachMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockTodoItemCach) Delete(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTodoItemCachMockRecorder) Delete(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTodoItemCach)(nil).Delete), userId)
+}
+
+// HDelete mocks base method.
+func (m *MockTodoItemCach) HDelete(userId, listId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDelete", userId, listId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HDelete indicates an expected call of HDelete.
+func (mr *MockTodoItemCachMockRecorder) HDelete(userId, listId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDelete", reflect.TypeOf((*MockTodoItemCach)(nil).HDelete), userId, listId)
+}
+
+// HGet mocks base method.
+func (m *MockTodoItemCach) HGet(userId, listId, itemId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", userId, listId, itemId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockTodoItemCachMockRecorder) HGet(userId, listId, itemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockTodoItemCach)(nil).HGet), userId, listId, itemId)
+}
+
+// HSet mocks base method.
+func (m *MockTodoItemCach) HSet(userId, listId, itemId int, data string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", userId, listId, itemId, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockTodoItemCachMockRecorder) HSet(userId, listId, itemId, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockTodoItemCach)(nil).HSet), userId, listId, itemId, data)
+}
+
+// MockSessionsCach is a mock of SessionsCach interface.
+type MockSessionsCach struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionsCachMockRecorder
+}
+
+// MockSessionsCachMockRecorder is the mock recorder for MockSessionsCach.
+type MockSessionsCachMockRecorder struct {
+	mock *MockSessionsCach
+}
+
+// NewMockSessionsCach creates a new mock instance.
+func NewMockSessionsCach(ctrl *gomock.Controller) *MockSessionsCach {
+	mock := &MockSessionsCach{ctrl: ctrl}
+	mock.recorder = &MockSessionsCachMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionsCach) EXPECT() *MockSessionsCachMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockSessionsCach) IsRevoked(sessionId int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", sessionId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockSessionsCachMockRecorder) IsRevoked(sessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockSessionsCach)(nil).IsRevoked), sessionId)
+}
+
+// Revoke mocks base method.
+func (m *MockSessionsCach) Revoke(sessionIds []int, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", sessionIds, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionsCachMockRecorder) Revoke(sessionIds, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionsCach)(nil).Revoke), sessionIds, ttl)
+}
+
+// MockLoginAttempts is a mock of LoginAttempts interface.
+type MockLoginAttempts struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptsMockRecorder
+}
+
+// MockLoginAttemptsMockRecorder is the mock recorder for MockLoginAttempts.
+type MockLoginAttemptsMockRecorder struct {
+	mock *MockLoginAttempts
+}
+
+// NewMockLoginAttempts creates a new mock instance.
+func NewMockLoginAttempts(ctrl *gomock.Controller) *MockLoginAttempts {
+	mock := &MockLoginAttempts{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttempts) EXPECT() *MockLoginAttemptsMockRecorder {
+	return m.recorder
+}
+
+// Blocked mocks base method.
+func (m *MockLoginAttempts) Blocked(username, ip string) (time.Duration, time.Duration, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Blocked", username, ip)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(time.Duration)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// Blocked indicates an expected call of Blocked.
+func (mr *MockLoginAttemptsMockRecorder) Blocked(username, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Blocked", reflect.TypeOf((*MockLoginAttempts)(nil).Blocked), username, ip)
+}
+
+// ChallengeAttempt mocks base method.
+func (m *MockLoginAttempts) ChallengeAttempt(challengeId string, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChallengeAttempt", challengeId, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChallengeAttempt indicates an expected call of ChallengeAttempt.
+func (mr *MockLoginAttemptsMockRecorder) ChallengeAttempt(challengeId, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChallengeAttempt", reflect.TypeOf((*MockLoginAttempts)(nil).ChallengeAttempt), challengeId, ttl)
+}
+
+// ChallengeDone mocks base method.
+func (m *MockLoginAttempts) ChallengeDone(challengeId string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChallengeDone", challengeId, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChallengeDone indicates an expected call of ChallengeDone.
+func (mr *MockLoginAttemptsMockRecorder) ChallengeDone(challengeId, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChallengeDone", reflect.TypeOf((*MockLoginAttempts)(nil).ChallengeDone), challengeId, ttl)
+}
+
+// Delay mocks base method.
+func (m *MockLoginAttempts) Delay(username, ip string, userTTL, ipTTL time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delay", username, ip, userTTL, ipTTL)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delay indicates an expected call of Delay.
+func (mr *MockLoginAttemptsMockRecorder) Delay(username, ip, userTTL, ipTTL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delay", reflect.TypeOf((*MockLoginAttempts)(nil).Delay), username, ip, userTTL, ipTTL)
+}
+
+// Fail mocks base method.
+func (m *MockLoginAttempts) Fail(username, ip string, window time.Duration) (int64, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", username, ip, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockLoginAttemptsMockRecorder) Fail(username, ip, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockLoginAttempts)(nil).Fail), username, ip, window)
+}
+
+// Lock mocks base method.
+func (m *MockLoginAttempts) Lock(username string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", username, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginAttemptsMockRecorder) Lock(username, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginAttempts)(nil).Lock), username, ttl)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttempts) Reset(username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptsMockRecorder) Reset(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttempts)(nil).Reset), username)
+}
+
+// MockOIDCStates is a mock of OIDCStates interface.
+type MockOIDCStates struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCStatesMockRecorder
+}
+
+// MockOIDCStatesMockRecorder is the mock recorder for MockOIDCStates.
+type MockOIDCStatesMockRecorder struct {
+	mock *MockOIDCStates
+}
+
+// NewMockOIDCStates creates a new mock instance.
+func NewMockOIDCStates(ctrl *gomock.Controller) *MockOIDCStates {
+	mock := &MockOIDCStates{ctrl: ctrl}
+	mock.recorder = &MockOIDCStatesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCStates) EXPECT() *MockOIDCStatesMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockOIDCStates) Save(state string, login todo.OIDCLogin, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", state, login, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockOIDCStatesMockRecorder) Save(state, login, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockOIDCStates)(nil).Save), state, login, ttl)
+}
+
+// Take mocks base method.
+func (m *MockOIDCStates) Take(state string) (todo.OIDCLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", state)
+	ret0, _ := ret[0].(todo.OIDCLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockOIDCStatesMockRecorder) Take(state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockOIDCStates)(nil).Take), state)
+}
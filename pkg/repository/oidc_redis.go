@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+	"todo-app"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// Незавершенные входы через OIDC между перенаправлением к провайдеру и возвратом на callback
+type OIDCStatesRedis struct {
+	context     *gin.Context
+	redisClient *redis.Client
+}
+
+func NewOIDCStatesRedis(context *gin.Context, redisClient *redis.Client) *OIDCStatesRedis {
+	return &OIDCStatesRedis{
+		context:     context,
+		redisClient: redisClient,
+	}
+}
+
+func oidcStateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+func (r *OIDCStatesRedis) Save(state string, login todo.OIDCLogin, ttl time.Duration) error {
+	data, err := json.Marshal(login)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.Set(r.context, oidcStateKey(state), data, ttl).Err()
+}
+
+// Данные входа читаются и удаляются одной транзакцией, поэтому state одноразовый.
+// Неизвестный или истекший state - redis.Nil
+func (r *OIDCStatesRedis) Take(state string) (todo.OIDCLogin, error) {
+	pipe := r.redisClient.TxPipeline()
+	get := pipe.Get(r.context, oidcStateKey(state))
+	pipe.Del(r.context, oidcStateKey(state))
+	if _, err := pipe.Exec(r.context); err != nil {
+		return todo.OIDCLogin{}, err
+	}
+
+	var login todo.OIDCLogin
+	err := json.Unmarshal([]byte(get.Val()), &login)
+
+	return login, err
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo-app"
+	"unicode/utf8"
+)
+
+// Тип значения поля сортировки в курсоре
+type cursorKind int
+
+const (
+	cursorText cursorKind = iota
+	cursorInt
+	cursorTime // время в формате RFC 3339
+)
+
+// Значение из курсора передается в запрос уже разобранным: курсор приходит от клиента,
+// и значение не того типа должно давать ErrInvalidCursor, а не ошибку БД
+func (k cursorKind) parse(value string) (interface{}, error) {
+	switch k {
+	case cursorInt:
+		return strconv.Atoi(value)
+	case cursorTime:
+		return time.Parse(time.RFC3339Nano, value)
+	default:
+		if !utf8.ValidString(value) || strings.ContainsRune(value, 0) {
+			return nil, fmt.Errorf("invalid text value")
+		}
+		return value, nil
+	}
+}
+
+type pageColumn struct {
+	name string
+	kind cursorKind
+}
+
+// Сортировка постраничной выборки. Первое поле упорядочивается в заданном направлении,
+// остальные (для равных значений, последним - id) всегда по возрастанию
+type pageSort struct {
+	columns     []pageColumn
+	defaultDesc bool // естественный порядок поля - по убыванию
+}
+
+func (s pageSort) desc(order string) bool {
+	return order == todo.SortOrderDesc || (order == "" && s.defaultDesc)
+}
+
+func (s pageSort) orderBy(order string) string {
+	columns := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		columns[i] = column.name
+	}
+	if s.desc(order) {
+		columns[0] += " DESC"
+	}
+
+	return " ORDER BY " + strings.Join(columns, ", ")
+}
+
+// Условие keyset-пагинации: записи строго после курсора в порядке orderBy.
+// Значения курсора добавляются в args, номера параметров продолжают args
+func (s pageSort) after(order string, values []string, args *[]interface{}) (string, error) {
+	if len(values) != len(s.columns) {
+		return "", todo.ErrInvalidCursor
+	}
+
+	parsed := make([]interface{}, len(values))
+	for i, value := range values {
+		v, err := s.columns[i].kind.parse(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid %s value", todo.ErrInvalidCursor, s.columns[i].name)
+		}
+		parsed[i] = v
+	}
+
+	params := make([]string, len(values))
+	for i, value := range parsed {
+		*args = append(*args, value)
+		params[i] = fmt.Sprintf("$%d", len(*args))
+	}
+
+	branches := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		op := ">"
+		if i == 0 && s.desc(order) {
+			op = "<"
+		}
+
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", s.columns[j].name, params[j]))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", column.name, op, params[i]))
+		branches[i] = strings.Join(parts, " AND ")
+	}
+
+	return "(" + strings.Join(branches, " OR ") + ")", nil
+}
+
+// Лишняя запись сверх limit означает, что есть следующая страница.
+// Возвращает число записей страницы и курсор следующей страницы ("" - страница последняя)
+func nextPage(n, limit int, sort, order string, values func(last int) []string) (int, string) {
+	if limit == 0 || n <= limit {
+		return n, ""
+	}
+
+	return limit, todo.Cursor{Sort: sort, Order: order, Values: values(limit - 1)}.Encode()
+}
+
+// Шаблон ILIKE для поиска подстроки: символы шаблона в строке ищутся как обычные символы
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+}
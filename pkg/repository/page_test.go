@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageSort(t *testing.T) {
+	priority := pageSort{columns: []pageColumn{{"ti.priority", cursorInt}, {"ti.id", cursorInt}}, defaultDesc: true}
+	title := pageSort{columns: []pageColumn{{"tl.title", cursorText}, {"tl.id", cursorInt}}}
+
+	testTable := []struct {
+		name        string
+		sort        pageSort
+		order       string
+		wantOrderBy string
+		wantAfter   string
+	}{
+		{
+			name:        "Natural Asc",
+			sort:        title,
+			wantOrderBy: " ORDER BY tl.title, tl.id",
+			wantAfter:   "(tl.title > $2 OR tl.title = $2 AND tl.id > $3)",
+		},
+		{
+			name:        "Desc",
+			sort:        title,
+			order:       todo.SortOrderDesc,
+			wantOrderBy: " ORDER BY tl.title DESC, tl.id",
+			wantAfter:   "(tl.title < $2 OR tl.title = $2 AND tl.id > $3)",
+		},
+		{
+			name:        "Natural Desc",
+			sort:        priority,
+			wantOrderBy: " ORDER BY ti.priority DESC, ti.id",
+			wantAfter:   "(ti.priority < $2 OR ti.priority = $2 AND ti.id > $3)",
+		},
+		{
+			name:        "Asc Overrides Natural",
+			sort:        priority,
+			order:       todo.SortOrderAsc,
+			wantOrderBy: " ORDER BY ti.priority, ti.id",
+			wantAfter:   "(ti.priority > $2 OR ti.priority = $2 AND ti.id > $3)",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.wantOrderBy, testCase.sort.orderBy(testCase.order))
+
+			args := []interface{}{1}
+			after, err := testCase.sort.after(testCase.order, []string{"3", "5"}, &args)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantAfter, after)
+			if testCase.sort.columns[0].kind == cursorText {
+				assert.Equal(t, []interface{}{1, "3", 5}, args)
+			} else {
+				assert.Equal(t, []interface{}{1, 3, 5}, args)
+			}
+		})
+	}
+
+	args := []interface{}{1}
+	_, err := title.after("", []string{"a"}, &args)
+	assert.ErrorIs(t, err, todo.ErrInvalidCursor)
+}
+
+func TestPageSort_after_InvalidValues(t *testing.T) {
+	created := pageSort{columns: []pageColumn{{"ti.created_at", cursorTime}, {"ti.id", cursorInt}}}
+	title := pageSort{columns: []pageColumn{{"tl.title", cursorText}, {"tl.id", cursorInt}}}
+
+	testTable := []struct {
+		name   string
+		sort   pageSort
+		values []string
+	}{
+		{name: "Non-numeric Id", sort: title, values: []string{"a", "x"}},
+		{name: "Invalid Time", sort: created, values: []string{"yesterday", "5"}},
+		{name: "Zero Byte", sort: title, values: []string{"a\x00", "5"}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			args := []interface{}{1}
+			_, err := testCase.sort.after("", testCase.values, &args)
+			assert.ErrorIs(t, err, todo.ErrInvalidCursor)
+			assert.Equal(t, []interface{}{1}, args)
+		})
+	}
+
+	args := []interface{}{}
+	_, err := created.after("", []string{"2022-06-13T09:00:00.5Z", "5"}, &args)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{time.Date(2022, 6, 13, 9, 0, 0, 500000000, time.UTC), 5}, args)
+}
+
+func TestLikePattern(t *testing.T) {
+	assert.Equal(t, `%50\% off\_now\\%`, likePattern(`50% off_now\`))
+}
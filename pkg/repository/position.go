@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"todo-app"
+	"todo-app/pkg/rank"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Элемент ручной сортировки: id списка или задачи и его ранг
+type rankedRow struct {
+	Id       int    `db:"id"`
+	Position string `db:"position"`
+}
+
+// Новый ранг элемента id при перемещении по input среди rows (rows отсортированы по рангу).
+// Обычно меняется только ранг перемещаемого элемента. Если между соседями нет места,
+// возвращаются новые ранги для всех элементов в renumber
+func placeRanked(rows []rankedRow, id int, input todo.MoveInput) (position string, renumber []rankedRow, err error) {
+	others := make([]rankedRow, 0, len(rows))
+	found := false
+	for _, row := range rows {
+		if row.Id == id {
+			found = true
+			continue
+		}
+		others = append(others, row)
+	}
+	if !found {
+		return "", nil, errors.New("moved element not found")
+	}
+
+	anchorId := 0
+	if input.BeforeId != nil {
+		anchorId = *input.BeforeId
+	} else {
+		anchorId = *input.AfterId
+	}
+
+	idx := -1
+	for i, row := range others {
+		if row.Id == anchorId {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return "", nil, fmt.Errorf("element %d not found", anchorId)
+	}
+	if input.AfterId != nil {
+		idx++
+	}
+
+	var prev, next string
+	if idx > 0 {
+		prev = others[idx-1].Position
+	}
+	if idx < len(others) {
+		next = others[idx].Position
+	}
+
+	if position, err := rank.Between(prev, next); err == nil {
+		return position, nil, nil
+	}
+
+	// Совпадающие (например, после одновременных вставок) или поврежденные ранги:
+	// перенумеровываем все элементы в новом порядке
+	ordered := make([]rankedRow, 0, len(rows))
+	ordered = append(ordered, others[:idx]...)
+	ordered = append(ordered, rankedRow{Id: id})
+	ordered = append(ordered, others[idx:]...)
+
+	for i, position := range rank.Sequence(len(ordered)) {
+		ordered[i].Position = position
+	}
+
+	return "", ordered, nil
+}
+
+// Ранг для добавления элемента в конец списка после last (пустой last - список пуст)
+func nextPosition(last string) string {
+	position, err := rank.Between(last, "")
+	if err != nil {
+		// Поврежденный ранг: любое продолжение last больше него самого
+		return last + "V"
+	}
+	return position
+}
+
+// Запись новых рангов одной командой. table - таблица, keyColumn - колонка с id элемента,
+// filter - дополнительное условие с параметром $3 (например, принадлежность пользователю)
+func updateRanks(tx *sqlx.Tx, table, keyColumn, filter string, filterArg interface{}, rows []rankedRow) error {
+	ids := make([]int64, len(rows))
+	positions := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = int64(row.Id)
+		positions[i] = row.Position
+	}
+
+	query := fmt.Sprintf(`UPDATE %s t SET position = v.position FROM unnest($1::int[], $2::text[]) AS v(id, position)
+									WHERE t.%s = v.id AND %s`, table, keyColumn, filter)
+	_, err := tx.Exec(query, pq.Array(ids), pq.Array(positions), filterArg)
+
+	return err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceRanked(t *testing.T) {
+	rows := []rankedRow{{Id: 1, Position: "F"}, {Id: 2, Position: "V"}, {Id: 3, Position: "k"}}
+
+	testTable := []struct {
+		name         string
+		rows         []rankedRow
+		id           int
+		input        todo.MoveInput
+		wantPosition string
+		wantRenumber []int // ожидаемый порядок id после перенумерации
+		wantErr      bool
+	}{
+		{
+			name:         "Before First",
+			rows:         rows,
+			id:           3,
+			input:        todo.MoveInput{BeforeId: intPointer(1)},
+			wantPosition: "7",
+		},
+		{
+			name:         "After Last",
+			rows:         rows,
+			id:           1,
+			input:        todo.MoveInput{AfterId: intPointer(3)},
+			wantPosition: "s",
+		},
+		{
+			name:         "Between",
+			rows:         rows,
+			id:           3,
+			input:        todo.MoveInput{AfterId: intPointer(1)},
+			wantPosition: "N",
+		},
+		{
+			name:         "Equal Neighbours Renumber",
+			rows:         []rankedRow{{Id: 1, Position: "V"}, {Id: 2, Position: "V"}, {Id: 3, Position: "k"}},
+			id:           3,
+			input:        todo.MoveInput{AfterId: intPointer(1)},
+			wantRenumber: []int{1, 3, 2},
+		},
+		{
+			name:    "Element Not In List",
+			rows:    rows,
+			id:      9,
+			input:   todo.MoveInput{AfterId: intPointer(1)},
+			wantErr: true,
+		},
+		{
+			name:    "Neighbour Not In List",
+			rows:    rows,
+			id:      1,
+			input:   todo.MoveInput{AfterId: intPointer(9)},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			position, renumber, err := placeRanked(testCase.rows, testCase.id, testCase.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			if testCase.wantRenumber == nil {
+				assert.Equal(t, testCase.wantPosition, position)
+				assert.Nil(t, renumber)
+				return
+			}
+
+			ids := make([]int, len(renumber))
+			for i, row := range renumber {
+				ids[i] = row.Id
+				if i > 0 {
+					assert.True(t, renumber[i-1].Position < row.Position)
+				}
+			}
+			assert.Equal(t, testCase.wantRenumber, ids)
+		})
+	}
+}
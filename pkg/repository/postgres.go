@@ -9,11 +9,24 @@ import (
 )
 
 const (
-	usersTable      = "users"
-	todoListsTable  = "todo_lists"
-	usersListsTable = "user_lists"
-	todoItemsTable  = "todo_items"
-	listsItemsTable = "lists_items"
+	usersTable            = "users"
+	todoListsTable        = "todo_lists"
+	usersListsTable       = "user_lists"
+	todoItemsTable        = "todo_items"
+	listsItemsTable       = "lists_items"
+	tagsTable             = "tags"
+	itemTagsTable         = "item_tags"
+	remindersTable        = "reminders"
+	invitationsTable      = "invitations"
+	workspacesTable       = "workspaces"
+	workspaceMembersTable = "workspace_members"
+	sessionsTable         = "sessions"
+	refreshTokensTable    = "refresh_tokens"
+	accessTokensTable     = "access_tokens"
+	recoveryCodesTable    = "recovery_codes"
+	emailTokensTable      = "email_tokens"
+	auditLogTable         = "audit_log"
+	userIdentitiesTable   = "user_identities"
 )
 
 type Config struct {
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RemindersPostgres struct {
+	db *sqlx.DB
+}
+
+func NewRemindersPostgres(db *sqlx.DB) *RemindersPostgres {
+	return &RemindersPostgres{db: db}
+}
+
+// Принадлежность задачи пользователю проверяется в сервисе
+func (r *RemindersPostgres) Create(userId, itemId int, reminder todo.Reminder) (int, error) {
+	var id int
+	query := fmt.Sprintf(`INSERT INTO %s (item_id, user_id, remind_at, offset_minutes, channel, target)
+									VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`, remindersTable)
+	row := r.db.QueryRow(query, itemId, userId, reminder.RemindAt, reminder.OffsetMinutes, reminder.Channel, reminder.Target)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *RemindersPostgres) GetByItem(userId, itemId int) ([]todo.Reminder, error) {
+	var reminders []todo.Reminder
+	query := fmt.Sprintf(`SELECT id, item_id, remind_at, offset_minutes, channel, target, fired_at, last_error FROM %s
+									WHERE user_id = $1 AND item_id = $2 ORDER BY id`, remindersTable)
+	err := r.db.Select(&reminders, query, userId, itemId)
+
+	return reminders, err
+}
+
+func (r *RemindersPostgres) Delete(userId, reminderId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND id = $2", remindersTable)
+	_, err := r.db.Exec(query, userId, reminderId)
+
+	return err
+}
+
+// Атомарный захват подошедших напоминаний: fired_at выставляется в той же команде, что и выборка,
+// а FOR UPDATE SKIP LOCKED не дает двум экземплярам API захватить одно напоминание.
+// Поэтому после перезапуска или при нескольких репликах напоминание не отправляется повторно.
+// Напоминания пользователей, которые больше не участвуют в списке задачи, не отправляются
+func (r *RemindersPostgres) ClaimDue(limit int) ([]todo.DueReminder, error) {
+	var reminders []todo.DueReminder
+	query := fmt.Sprintf(`UPDATE %s r SET fired_at = now(), attempts = r.attempts + 1
+									FROM (SELECT r2.id FROM %s r2 INNER JOIN %s ti on ti.id = r2.item_id
+										WHERE r2.fired_at IS NULL AND ti.done = false
+										AND COALESCE(r2.remind_at, ti.due_date - r2.offset_minutes * interval '1 minute') <= now()
+										AND EXISTS (SELECT 1 FROM %s li INNER JOIN %s ul on ul.list_id = li.list_id
+											WHERE li.item_id = ti.id AND ul.user_id = r2.user_id)
+										ORDER BY r2.id LIMIT $1 FOR UPDATE OF r2 SKIP LOCKED) due, %s ti
+									WHERE r.id = due.id AND ti.id = r.item_id
+									RETURNING r.id, r.item_id, r.user_id, r.channel, r.target, r.attempts, ti.title, ti.description, ti.due_date`,
+		remindersTable, remindersTable, todoItemsTable, listsItemsTable, usersListsTable, todoItemsTable)
+	err := r.db.Select(&reminders, query, limit)
+
+	return reminders, err
+}
+
+// Фиксация ошибки отправки. При retry напоминание возвращается в очередь
+func (r *RemindersPostgres) Fail(reminderId int, message string, retry bool) error {
+	if runes := []rune(message); len(runes) > 255 {
+		message = string(runes[:255])
+	}
+
+	firedQuery := ""
+	if retry {
+		firedQuery = ", fired_at = NULL"
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_error = $1%s WHERE id = $2", remindersTable, firedQuery)
+	_, err := r.db.Exec(query, message, reminderId)
+
+	return err
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestRemindersPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewRemindersPostgres(db)
+
+	type args struct {
+		userId   int
+		itemId   int
+		reminder todo.Reminder
+	}
+
+	remindAt := time.Date(2022, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func(args args, id int)
+		args    args
+		id      int
+		wantErr bool
+	}{
+		{
+			name: "OK Remind At",
+			args: args{
+				userId:   1,
+				itemId:   2,
+				reminder: todo.Reminder{RemindAt: &remindAt, Channel: "email", Target: "user@example.com"},
+			},
+			id: 3,
+			mock: func(args args, id int) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO reminders").
+					WithArgs(args.itemId, args.userId, args.reminder.RemindAt, args.reminder.OffsetMinutes, args.reminder.Channel, args.reminder.Target).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "OK Offset",
+			args: args{
+				userId:   1,
+				itemId:   2,
+				reminder: todo.Reminder{OffsetMinutes: intPointer(15), Channel: "webhook", Target: "https://example.com"},
+			},
+			id: 4,
+			mock: func(args args, id int) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO reminders").
+					WithArgs(args.itemId, args.userId, args.reminder.RemindAt, args.reminder.OffsetMinutes, args.reminder.Channel, args.reminder.Target).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Insert Error",
+			args: args{
+				userId:   1,
+				itemId:   2,
+				reminder: todo.Reminder{RemindAt: &remindAt, Channel: "email", Target: "user@example.com"},
+			},
+			mock: func(args args, id int) {
+				mock.ExpectQuery("INSERT INTO reminders").
+					WithArgs(args.itemId, args.userId, args.reminder.RemindAt, args.reminder.OffsetMinutes, args.reminder.Channel, args.reminder.Target).
+					WillReturnError(errors.New("insert error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock(testCase.args, testCase.id)
+
+			got, err := r.Create(testCase.args.userId, testCase.args.itemId, testCase.args.reminder)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRemindersPostgres_ClaimDue(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewRemindersPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    []todo.DueReminder
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "item_id", "user_id", "channel", "target", "attempts", "title", "description", "due_date"}).
+					AddRow(1, 2, 3, "email", "user@example.com", 1, "title", "description", nil)
+				mock.ExpectQuery("UPDATE reminders r SET fired_at = now\\(\\)(.+)INNER JOIN user_lists ul (.+)ul.user_id = r2.user_id(.+)FOR UPDATE OF r2 SKIP LOCKED").
+					WithArgs(10).WillReturnRows(rows)
+			},
+			want: []todo.DueReminder{
+				{Id: 1, ItemId: 2, UserId: 3, Channel: "email", Target: "user@example.com", Attempts: 1, ItemTitle: "title", ItemDescription: "description"},
+			},
+		},
+		{
+			name: "Nothing Due",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "item_id", "user_id", "channel", "target", "attempts", "title", "description", "due_date"})
+				mock.ExpectQuery("UPDATE reminders r SET fired_at = now\\(\\)").
+					WithArgs(10).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Error",
+			mock: func() {
+				mock.ExpectQuery("UPDATE reminders r SET fired_at = now\\(\\)").
+					WithArgs(10).WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.ClaimDue(10)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRemindersPostgres_Fail(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewRemindersPostgres(db)
+
+	testTable := []struct {
+		name  string
+		retry bool
+		mock  func()
+	}{
+		{
+			name:  "Retry",
+			retry: true,
+			mock: func() {
+				mock.ExpectExec("UPDATE reminders SET last_error = (.+), fired_at = NULL WHERE id = (.+)").
+					WithArgs("timeout", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "No Retry",
+			mock: func() {
+				mock.ExpectExec("UPDATE reminders SET last_error = (.+) WHERE id = (.+)").
+					WithArgs("timeout", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Fail(1, "timeout", testCase.retry)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
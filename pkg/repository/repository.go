@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"time"
 	"todo-app"
 
 	"github.com/gin-gonic/gin"
@@ -10,23 +11,162 @@ import (
 
 type Authorization interface {
 	CreateUser(user todo.User) (int, error)
-	GetUser(username, password string) (todo.User, error)
+	// Пользователь с хэшем пароля в поле Password
+	GetUser(username string) (todo.User, error)
+	UpdatePasswordHash(userId int, hash string) error
+	GetUserById(userId int) (todo.User, error)
+	GetUserByEmail(email string) (todo.User, error)
+	SetEmailVerified(userId int, email string) error
+	UpdateUser(userId int, input todo.UpdateUserInput) error
+	DeleteUser(userId int) error
 }
 
 type TodoList interface {
 	Create(userId int, list todo.TodoList) (int, error)
-	GetAll(userId int) ([]todo.TodoList, error)
+	// Возвращает курсор следующей страницы ("" - страница последняя)
+	GetAll(userId int, query todo.ListsQuery) ([]todo.TodoList, string, error)
 	GetById(userId, listId int) (todo.TodoList, error)
 	DeleteById(userId, listId int) error
 	UpdateById(userId, listId int, list todo.UpdateListInput) (todo.TodoList, error)
+	Move(userId, listId int, input todo.MoveInput) error
+	GetRole(userId, listId int) (todo.ListRole, error)
+	GetMembers(listId int) ([]todo.ListMember, error)
+	GetMemberIds(listId int) ([]int, error)
+	// id пользователя и всех, с кем у него есть общие списки
+	GetCoMemberIds(userId int) ([]int, error)
+	AddMember(listId int, username string, role todo.ListRole) (int, error)
+	RemoveMember(listId int, username string) (int, error)
 }
 
 type TodoItem interface {
 	Create(listId int, item todo.TodoItem) (int, error)
-	GetAll(userId, listId int) ([]todo.TodoItem, error)
+	// sort - порядок выдачи (todo.ItemSortDefault или todo.ItemSortPriority)
+	// Возвращает курсор следующей страницы ("" - страница последняя)
+	GetAll(userId, listId int, query todo.ItemsQuery) ([]todo.TodoItem, string, error)
+	// Если listId использовать не нужно (поиск по всем спискам), передать -1
+	GetOverdue(userId, listId int) ([]todo.TodoItem, error)
 	GetById(userId, itemId int) (todo.TodoItem, error)
+	// Удаляет задачу вместе со всеми подзадачами
 	Delete(userId, itemId int) error
 	Update(userId, itemId int, input todo.UpdateItemInput) error
+	// id всех подзадач (любой вложенности), без самой задачи
+	GetSubtreeIds(userId, itemId int) ([]int, error)
+	Move(userId, listId, itemId int, input todo.MoveInput) error
+	// Перенос задачи с подзадачами в другой список
+	MoveToList(userId, itemId, listId int) error
+	// Копирование задачи с подзадачами и метками в список, возвращает id копии
+	CopyToList(userId, itemId, listId int) (int, error)
+}
+
+type Tags interface {
+	Create(userId int, tag todo.Tag) (int, error)
+	GetAll(userId int) ([]todo.Tag, error)
+	GetById(userId, tagId int) (todo.Tag, error)
+	Update(userId, tagId int, input todo.UpdateTagInput) error
+	Delete(userId, tagId int) error
+	Attach(itemId, tagId int) error
+	Detach(userId, itemId, tagId int) error
+	GetByItem(userId, itemId int) ([]todo.Tag, error)
+	GetItems(userId int, names []string, matchAll bool) ([]todo.TodoItem, error)
+}
+
+type Reminders interface {
+	Create(userId, itemId int, reminder todo.Reminder) (int, error)
+	GetByItem(userId, itemId int) ([]todo.Reminder, error)
+	Delete(userId, reminderId int) error
+	// Захват подошедших напоминаний для отправки (не более limit)
+	ClaimDue(limit int) ([]todo.DueReminder, error)
+	// Фиксация ошибки отправки, при retry напоминание возвращается в очередь
+	Fail(reminderId int, message string, retry bool) error
+}
+
+type Invitations interface {
+	// username пустой для приглашения по ссылке
+	Create(inviterId, listId int, username string, role todo.ListRole, expiresAt time.Time, tokenHash *string) (int, error)
+	GetByList(listId int) ([]todo.Invitation, error)
+	GetPending(userId int) ([]todo.Invitation, error)
+	Revoke(listId, invitationId int) error
+	Decline(userId, invitationId int) error
+	// Принятие приглашения добавляет пользователя в список, возвращает id списка
+	Accept(userId, invitationId int) (int, error)
+	AcceptToken(userId int, tokenHash string) (int, error)
+}
+
+type Workspaces interface {
+	Create(userId int, workspace todo.Workspace) (int, error)
+	GetAll(userId int) ([]todo.Workspace, error)
+	GetById(userId, workspaceId int) (todo.Workspace, error)
+	GetLists(userId, workspaceId int) ([]todo.TodoList, error)
+	GetMembers(workspaceId int) ([]todo.ListMember, error)
+	// Участник получает доступ ко всем спискам пространства, возвращает id участника
+	AddMember(workspaceId int, username string, role todo.ListRole) (int, error)
+	RemoveMember(workspaceId int, username string) (int, error)
+}
+
+type Sessions interface {
+	// Новая сессия с первым refresh-токеном, возвращает id сессии
+	Create(userId int, client todo.SessionClient, tokenHash string, expiresAt time.Time) (int, error)
+	// Обмен refresh-токена, возвращает id пользователя и сессии
+	Rotate(tokenHash, newTokenHash, ip string, expiresAt time.Time) (int, int, error)
+	GetAll(userId int) ([]todo.Session, error)
+	Revoke(userId, sessionId int) error
+	// Возвращает id отозванных сессий. Если exceptSessionId использовать не нужно, передать -1
+	RevokeAll(userId, exceptSessionId int) ([]int, error)
+}
+
+type AccessTokens interface {
+	Create(userId int, name string, scopes []string, tokenHash string, expiresAt *time.Time) (int, error)
+	GetAll(userId int) ([]todo.AccessToken, error)
+	Delete(userId, tokenId int) error
+	// Отзыв всех токенов пользователя (например, при смене пароля)
+	DeleteAll(userId int) error
+	// Действующий токен по хэшу
+	GetByHash(tokenHash string) (todo.AccessToken, error)
+}
+
+type TwoFactor interface {
+	Get(userId int) (todo.TwoFactor, error)
+	// Новый секрет до подтверждения (только если 2FA не включена)
+	SetPendingSecret(userId int, secret string) error
+	Enable(userId int, step int64, codeHashes []string) error
+	Disable(userId int) error
+	// Принятие кода шага step, false - повтор уже использованного кода
+	UseStep(userId int, step int64) (bool, error)
+	UseRecoveryCode(userId int, codeHash string) error
+}
+
+type EmailTokens interface {
+	// Новый токен, прежние неиспользованные токены того же назначения аннулируются
+	Create(userId int, purpose, email, tokenHash string, expiresAt time.Time) error
+	// Одноразовое использование токена, возвращает id пользователя и адрес письма
+	Use(purpose, tokenHash string) (int, string, error)
+}
+
+type Audit interface {
+	Create(record todo.AuditRecord) error
+}
+
+type Identities interface {
+	// Пользователь, связанный с учетной записью провайдера, sql.ErrNoRows - связи нет
+	GetUserId(provider, subject string) (int, error)
+	Link(userId int, provider, subject, email string) error
+	// Новый пользователь без пароля, связанный с учетной записью провайдера
+	CreateUser(user todo.User, provider, subject string) (int, error)
+}
+
+type Archive interface {
+	Export(userId int) (todo.Archive, error)
+	Import(userId int, archive todo.Archive) (todo.ImportResult, error)
+}
+
+type Admin interface {
+	GetUsers(query todo.AdminUsersQuery) ([]todo.AdminUser, error)
+	GetUser(userId int) (todo.AdminUser, error)
+	SetDisabled(userId int, disabled bool) error
+}
+
+type Search interface {
+	Search(userId int, query todo.SearchQuery) ([]todo.SearchResult, error)
 }
 
 type TodoListCach interface {
@@ -43,12 +183,55 @@ type TodoItemCach interface {
 	Delete(userId int) error
 }
 
+type SessionsCach interface {
+	Revoke(sessionIds []int, ttl time.Duration) error
+	IsRevoked(sessionId int) (bool, error)
+}
+
+type LoginAttempts interface {
+	// Оставшееся время блокировки учетной записи, задержки по username и задержки по ip (0 - нет)
+	Blocked(username, ip string) (time.Duration, time.Duration, time.Duration, error)
+	// Учет неудачной попытки, возвращает число неудач по username и по ip за окно window
+	Fail(username, ip string, window time.Duration) (int64, int64, error)
+	// Задержка перед следующей попыткой (ttl 0 - без задержки)
+	Delay(username, ip string, userTTL, ipTTL time.Duration) error
+	Lock(username string, ttl time.Duration) error
+	// Сброс счетчика по username после успешного входа
+	Reset(username string) error
+	// Учет попытки ввода кода 2FA по токену второго шага, возвращает число попыток с этим токеном
+	ChallengeAttempt(challengeId string, ttl time.Duration) (int64, error)
+	// Токен второго шага использован, следующие попытки с ним отклоняются
+	ChallengeDone(challengeId string, ttl time.Duration) error
+}
+
+type OIDCStates interface {
+	Save(state string, login todo.OIDCLogin, ttl time.Duration) error
+	// Одноразовое чтение, неизвестный или истекший state - redis.Nil
+	Take(state string) (todo.OIDCLogin, error)
+}
+
 type Repository struct {
 	Authorization
 	TodoList
 	TodoItem
+	Tags
+	Reminders
+	Invitations
+	Workspaces
+	Sessions
+	AccessTokens
+	TwoFactor
+	EmailTokens
+	Audit
+	Identities
+	Archive
+	Admin
+	Search
 	TodoListCach
 	TodoItemCach
+	SessionsCach
+	LoginAttempts
+	OIDCStates
 }
 
 func NewRepository(db *sqlx.DB, context *gin.Context, redisClient *redis.Client) *Repository {
@@ -56,8 +239,24 @@ func NewRepository(db *sqlx.DB, context *gin.Context, redisClient *redis.Client)
 		Authorization: NewAuthPostgres(db),
 		TodoList:      NewTodoListPostgres(db),
 		TodoItem:      NewTodoItemPostgres(db),
+		Tags:          NewTagsPostgres(db),
+		Reminders:     NewRemindersPostgres(db),
+		Invitations:   NewInvitationsPostgres(db),
+		Workspaces:    NewWorkspacesPostgres(db),
+		Sessions:      NewSessionsPostgres(db),
+		AccessTokens:  NewAccessTokensPostgres(db),
+		TwoFactor:     NewTwoFactorPostgres(db),
+		EmailTokens:   NewEmailTokensPostgres(db),
+		Audit:         NewAuditPostgres(db),
+		Identities:    NewIdentitiesPostgres(db),
+		Archive:       NewArchivePostgres(db),
+		Admin:         NewAdminPostgres(db),
+		Search:        NewSearchPostgres(db),
 		TodoListCach:  NewTodoListRedis(context, redisClient),
 		TodoItemCach:  NewTodoItemRedis(context, redisClient),
+		SessionsCach:  NewSessionsRedis(context, redisClient),
+		LoginAttempts: NewLoginAttemptsRedis(context, redisClient),
+		OIDCStates:    NewOIDCStatesRedis(context, redisClient),
 	}
 
 }
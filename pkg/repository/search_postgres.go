@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Границы совпадений в ts_headline. Символы из области частного использования Unicode не встречаются в тексте,
+// поэтому текст можно экранировать для HTML и только затем заменить их тегами
+const (
+	searchStartSel = "\ue000"
+	searchStopSel  = "\ue001"
+)
+
+var (
+	searchTitleOptions   = fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", searchStartSel, searchStopSel)
+	searchSnippetOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" … \"",
+		searchStartSel, searchStopSel)
+	searchHighlighter = strings.NewReplacer(searchStartSel, "<b>", searchStopSel, "</b>")
+)
+
+type SearchPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSearchPostgres(db *sqlx.DB) *SearchPostgres {
+	return &SearchPostgres{db: db}
+}
+
+// Поиск по спискам пользователя и задачам в них. Фрагменты с подсветкой строятся только для записей страницы
+func (r *SearchPostgres) Search(userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	results := make([]todo.SearchResult, 0)
+
+	searchQuery := fmt.Sprintf(`WITH q AS (SELECT websearch_to_tsquery('russian', $2) AS query)
+		SELECT r.type, r.id, r.list_id, r.list_title, ts_headline('russian', r.title, q.query, $5) AS title,
+				coalesce(ts_headline('russian', r.description, q.query, $6), '') AS snippet, r.rank
+			FROM (
+				SELECT '%s' AS type, tl.id, tl.id AS list_id, '' AS list_title, tl.title, tl.description,
+						ts_rank(tl.search, q.query) AS rank
+					FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id, q
+					WHERE ul.user_id = $1 AND tl.search @@ q.query
+				UNION ALL
+				SELECT '%s', ti.id, tl.id, tl.title, ti.title, ti.description, ts_rank(ti.search, q.query)
+					FROM %s ti INNER JOIN %s li on li.item_id = ti.id INNER JOIN %s tl on tl.id = li.list_id
+						INNER JOIN %s ul on tl.id = ul.list_id, q
+					WHERE ul.user_id = $1 AND ti.search @@ q.query
+				ORDER BY rank DESC, type, id LIMIT $3 OFFSET $4
+			) r, q
+			ORDER BY r.rank DESC, r.type, r.id`,
+		todo.SearchTypeList, todoListsTable, usersListsTable,
+		todo.SearchTypeItem, todoItemsTable, listsItemsTable, todoListsTable, usersListsTable)
+	err := r.db.Select(&results, searchQuery, userId, query.Query, query.Limit, query.Offset,
+		searchTitleOptions, searchSnippetOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Title = highlight(results[i].Title)
+		results[i].Snippet = highlight(results[i].Snippet)
+	}
+
+	return results, nil
+}
+
+func highlight(s string) string {
+	return searchHighlighter.Replace(html.EscapeString(s))
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestSearchPostgres_Search(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewSearchPostgres(db)
+
+	columns := []string{"type", "id", "list_id", "list_title", "title", "snippet", "rank"}
+
+	mock.ExpectQuery("WITH q AS \\(SELECT websearch_to_tsquery\\('russian', \\$2\\) (.+) FROM todo_lists tl (.+) "+
+		"WHERE ul.user_id = \\$1 AND tl.search @@ q.query UNION ALL (.+) WHERE ul.user_id = \\$1 AND ti.search @@ q.query "+
+		"ORDER BY rank DESC, type, id LIMIT \\$3 OFFSET \\$4").
+		WithArgs(1, "milk <b>", 20, 40, searchTitleOptions, searchSnippetOptions).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("list", 2, 2, "", "Shopping & milk", "", 0.6).
+			AddRow("item", 5, 2, "Shopping & milk", "Buy milk <b>", "2 bottles of milk", 0.2))
+
+	got, err := r.Search(1, todo.SearchQuery{Query: "milk <b>", Limit: 20, Offset: 40})
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.SearchResult{
+		{Type: todo.SearchTypeList, Id: 2, ListId: 2, Title: "Shopping &amp; <b>milk</b>", Rank: 0.6},
+		{Type: todo.SearchTypeItem, Id: 5, ListId: 2, ListTitle: "Shopping & milk", Title: "Buy <b>milk</b> &lt;b&gt;",
+			Snippet: "2 bottles of <b>milk</b>", Rank: 0.2},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
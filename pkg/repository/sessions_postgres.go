@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrSessionRevoked     = errors.New("session revoked")
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+type SessionsPostgres struct {
+	db *sqlx.DB
+}
+
+func NewSessionsPostgres(db *sqlx.DB) *SessionsPostgres {
+	return &SessionsPostgres{db: db}
+}
+
+// Новая сессия с первым refresh-токеном, возвращает id сессии
+func (r *SessionsPostgres) Create(userId int, client todo.SessionClient, tokenHash string, expiresAt time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	createQuery := fmt.Sprintf("INSERT INTO %s (user_id, device_name, user_agent, ip) VALUES ($1, $2, $3, $4) RETURNING id",
+		sessionsTable)
+	if err := tx.QueryRow(createQuery, userId, client.DeviceName, client.UserAgent, client.Ip).Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	tokenQuery := fmt.Sprintf("INSERT INTO %s (session_id, token_hash, expires_at) VALUES ($1, $2, $3)", refreshTokensTable)
+	if _, err := tx.Exec(tokenQuery, id, tokenHash, expiresAt); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// Обмен refresh-токена на новый в той же сессии, возвращает id пользователя и сессии.
+// Время последней активности и ip сессии обновляются.
+// Повторное предъявление уже обмененного токена означает его утечку: сессия (все семейство токенов)
+// отзывается и возвращается ErrRefreshTokenReused вместе с id сессии. Неизвестный или истекший токен - sql.ErrNoRows
+func (r *SessionsPostgres) Rotate(tokenHash, newTokenHash, ip string, expiresAt time.Time) (int, int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var token struct {
+		Id        int        `db:"id"`
+		SessionId int        `db:"session_id"`
+		UserId    int        `db:"user_id"`
+		UsedAt    *time.Time `db:"used_at"`
+		RevokedAt *time.Time `db:"revoked_at"`
+	}
+	selectQuery := fmt.Sprintf(`SELECT rt.id, rt.session_id, s.user_id, rt.used_at, s.revoked_at FROM %s rt
+									INNER JOIN %s s on s.id = rt.session_id
+									WHERE rt.token_hash = $1 AND rt.expires_at > now() FOR UPDATE OF rt, s`,
+		refreshTokensTable, sessionsTable)
+	if err := tx.Get(&token, selectQuery, tokenHash); err != nil {
+		tx.Rollback()
+		return 0, 0, err
+	}
+
+	if token.RevokedAt != nil {
+		tx.Rollback()
+		return token.UserId, token.SessionId, ErrSessionRevoked
+	}
+
+	if token.UsedAt != nil {
+		revokeQuery := fmt.Sprintf("UPDATE %s SET revoked_at = now() WHERE id = $1", sessionsTable)
+		if _, err := tx.Exec(revokeQuery, token.SessionId); err != nil {
+			tx.Rollback()
+			return 0, 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, 0, err
+		}
+		return token.UserId, token.SessionId, ErrRefreshTokenReused
+	}
+
+	useQuery := fmt.Sprintf("UPDATE %s SET used_at = now() WHERE id = $1", refreshTokensTable)
+	if _, err := tx.Exec(useQuery, token.Id); err != nil {
+		tx.Rollback()
+		return 0, 0, err
+	}
+
+	newQuery := fmt.Sprintf("INSERT INTO %s (session_id, token_hash, expires_at) VALUES ($1, $2, $3)", refreshTokensTable)
+	if _, err := tx.Exec(newQuery, token.SessionId, newTokenHash, expiresAt); err != nil {
+		tx.Rollback()
+		return 0, 0, err
+	}
+
+	seenQuery := fmt.Sprintf("UPDATE %s SET last_seen_at = now(), ip = $1 WHERE id = $2", sessionsTable)
+	if _, err := tx.Exec(seenQuery, ip, token.SessionId); err != nil {
+		tx.Rollback()
+		return 0, 0, err
+	}
+
+	return token.UserId, token.SessionId, tx.Commit()
+}
+
+// Действующие сессии пользователя: не отозваны и имеют неиспользованный и не истекший refresh-токен
+func (r *SessionsPostgres) GetAll(userId int) ([]todo.Session, error) {
+	var sessions []todo.Session
+	query := fmt.Sprintf(`SELECT s.id, s.device_name, s.user_agent, s.ip, s.created_at, s.last_seen_at FROM %s s
+							WHERE s.user_id = $1 AND s.revoked_at IS NULL AND EXISTS (
+								SELECT 1 FROM %s rt WHERE rt.session_id = s.id AND rt.used_at IS NULL AND rt.expires_at > now())
+							ORDER BY s.last_seen_at DESC`,
+		sessionsTable, refreshTokensTable)
+	err := r.db.Select(&sessions, query, userId)
+
+	return sessions, err
+}
+
+// Отзыв сессии пользователя. Если сессии нет или она уже отозвана - sql.ErrNoRows
+func (r *SessionsPostgres) Revoke(userId, sessionId int) error {
+	var id int
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL RETURNING id",
+		sessionsTable)
+
+	return r.db.QueryRow(query, sessionId, userId).Scan(&id)
+}
+
+// Отзыв всех сессий пользователя, кроме exceptSessionId, возвращает id отозванных сессий.
+// Если exceptSessionId использовать не нужно, передать -1
+func (r *SessionsPostgres) RevokeAll(userId, exceptSessionId int) ([]int, error) {
+	ids := make([]int, 0)
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = now() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL RETURNING id",
+		sessionsTable)
+	err := r.db.Select(&ids, query, userId, exceptSessionId)
+
+	return ids, err
+}
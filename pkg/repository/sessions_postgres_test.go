@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestSessionsPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	expiresAt := time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO sessions").WithArgs(1, "Pixel 6", "okhttp/4.9", "192.0.2.1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec("INSERT INTO refresh_tokens").WithArgs(3, "hash", expiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			want: 3,
+		},
+		{
+			name: "Failed Token Insert",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO sessions").WithArgs(1, "Pixel 6", "okhttp/4.9", "192.0.2.1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec("INSERT INTO refresh_tokens").WithArgs(3, "hash", expiresAt).
+					WillReturnError(errors.New("insert error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.Create(1, todo.SessionClient{DeviceName: "Pixel 6", UserAgent: "okhttp/4.9", Ip: "192.0.2.1"}, "hash", expiresAt)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionsPostgres_Rotate(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	expiresAt := time.Date(2022, 7, 10, 12, 0, 0, 0, time.UTC)
+	usedAt := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "session_id", "user_id", "used_at", "revoked_at"}
+
+	testTable := []struct {
+		name          string
+		mock          func()
+		wantSessionId int
+		wantErr       error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens rt INNER JOIN sessions s (.+) FOR UPDATE").WithArgs("old").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 3, 1, nil, nil))
+				mock.ExpectExec("UPDATE refresh_tokens SET used_at").WithArgs(10).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("INSERT INTO refresh_tokens").WithArgs(3, "new", expiresAt).
+					WillReturnResult(sqlmock.NewResult(11, 1))
+				mock.ExpectExec("UPDATE sessions SET last_seen_at").WithArgs("192.0.2.1", 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantSessionId: 3,
+		},
+		{
+			name: "Reused Token Revokes Session",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens rt INNER JOIN sessions s (.+) FOR UPDATE").WithArgs("old").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 3, 1, usedAt, nil))
+				mock.ExpectExec("UPDATE sessions SET revoked_at").WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantSessionId: 3,
+			wantErr:       ErrRefreshTokenReused,
+		},
+		{
+			name: "Revoked Session",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens rt INNER JOIN sessions s (.+) FOR UPDATE").WithArgs("old").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(10, 3, 1, nil, usedAt))
+				mock.ExpectRollback()
+			},
+			wantSessionId: 3,
+			wantErr:       ErrSessionRevoked,
+		},
+		{
+			name: "Unknown Or Expired Token",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM refresh_tokens rt INNER JOIN sessions s (.+) FOR UPDATE").WithArgs("old").
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			_, sessionId, err := r.Rotate("old", "new", "192.0.2.1", expiresAt)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.wantSessionId, sessionId)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSessionsPostgres_RevokeAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	mock.ExpectQuery("UPDATE sessions SET revoked_at (.+) WHERE user_id (.+) RETURNING id").WithArgs(1, -1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+
+	got, err := r.RevokeAll(1, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionsPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewSessionsPostgres(db)
+
+	createdAt := time.Date(2022, 6, 10, 12, 0, 0, 0, time.UTC)
+	lastSeenAt := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM sessions s WHERE s.user_id (.+) AND s.revoked_at IS NULL AND EXISTS (.+) ORDER BY s.last_seen_at DESC").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "device_name", "user_agent", "ip", "created_at", "last_seen_at"}).
+			AddRow(3, "Pixel 6", "okhttp/4.9", "192.0.2.1", createdAt, lastSeenAt))
+
+	got, err := r.GetAll(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.Session{
+		{Id: 3, DeviceName: "Pixel 6", UserAgent: "okhttp/4.9", Ip: "192.0.2.1", CreatedAt: createdAt, LastSeenAt: lastSeenAt},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// Отозванные сессии для быстрой проверки access-токенов. Ключ хранится, пока могут быть живы
+// access-токены сессии, источник истины - таблица sessions
+type SessionsRedis struct {
+	context     *gin.Context
+	redisClient *redis.Client
+}
+
+func NewSessionsRedis(context *gin.Context, redisClient *redis.Client) *SessionsRedis {
+	return &SessionsRedis{
+		context:     context,
+		redisClient: redisClient,
+	}
+}
+
+func revokedSessionKey(sessionId int) string {
+	return fmt.Sprintf("session:%d:revoked", sessionId)
+}
+
+func (r *SessionsRedis) Revoke(sessionIds []int, ttl time.Duration) error {
+	if len(sessionIds) == 0 {
+		return nil
+	}
+
+	pipe := r.redisClient.Pipeline()
+	for _, id := range sessionIds {
+		pipe.Set(r.context, revokedSessionKey(id), 1, ttl)
+	}
+	_, err := pipe.Exec(r.context)
+
+	return err
+}
+
+func (r *SessionsRedis) IsRevoked(sessionId int) (bool, error) {
+	n, err := r.redisClient.Exists(r.context, revokedSessionKey(sessionId)).Result()
+	return n > 0, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redismock/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsRedis_Revoke(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	r := NewSessionsRedis(&gin.Context{}, db)
+
+	mock.ExpectSet("session:3:revoked", 1, 15*time.Minute).SetVal("OK")
+	mock.ExpectSet("session:4:revoked", 1, 15*time.Minute).SetVal("OK")
+
+	assert.NoError(t, r.Revoke([]int{3, 4}, 15*time.Minute))
+	assert.NoError(t, r.Revoke(nil, 15*time.Minute)) // без сессий запрос в Redis не отправляется
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionsRedis_IsRevoked(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	defer db.Close()
+
+	r := NewSessionsRedis(&gin.Context{}, db)
+
+	testTable := []struct {
+		name   string
+		exists int64
+		want   bool
+	}{
+		{name: "Revoked", exists: 1, want: true},
+		{name: "Active", exists: 0, want: false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock.ExpectExists("session:3:revoked").SetVal(testCase.exists)
+
+			got, err := r.IsRevoked(3)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
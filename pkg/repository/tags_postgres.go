@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TagsPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTagsPostgres(db *sqlx.DB) *TagsPostgres {
+	return &TagsPostgres{db: db}
+}
+
+func (r *TagsPostgres) Create(userId int, tag todo.Tag) (int, error) {
+	var id int
+	query := fmt.Sprintf("INSERT INTO %s (user_id, name, color) VALUES ($1, $2, $3) RETURNING id", tagsTable)
+	row := r.db.QueryRow(query, userId, tag.Name, tag.Color)
+	if err := row.Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *TagsPostgres) GetAll(userId int) ([]todo.Tag, error) {
+	var tags []todo.Tag
+	query := fmt.Sprintf("SELECT id, name, color FROM %s WHERE user_id = $1 ORDER BY name", tagsTable)
+	err := r.db.Select(&tags, query, userId)
+
+	return tags, err
+}
+
+func (r *TagsPostgres) GetById(userId, tagId int) (todo.Tag, error) {
+	var tag todo.Tag
+	query := fmt.Sprintf("SELECT id, name, color FROM %s WHERE user_id = $1 AND id = $2", tagsTable)
+	err := r.db.Get(&tag, query, userId, tagId)
+
+	return tag, err
+}
+
+func (r *TagsPostgres) Update(userId, tagId int, input todo.UpdateTagInput) error {
+	setValues := make([]string, 0)
+	args := make([]interface{}, 0)
+	argId := 1
+
+	if input.Name != nil {
+		setValues = append(setValues, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+
+	if input.Color != nil {
+		setValues = append(setValues, fmt.Sprintf("color=$%d", argId))
+		args = append(args, *input.Color)
+		argId++
+	}
+
+	setQuery := strings.Join(setValues, ", ")
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE user_id = $%d AND id = $%d", tagsTable, setQuery, argId, argId+1)
+	args = append(args, userId, tagId)
+
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+func (r *TagsPostgres) Delete(userId, tagId int) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND id = $2", tagsTable)
+	_, err := r.db.Exec(query, userId, tagId)
+
+	return err
+}
+
+// Принадлежность задачи и метки пользователю проверяется в сервисе
+func (r *TagsPostgres) Attach(itemId, tagId int) error {
+	query := fmt.Sprintf("INSERT INTO %s (item_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", itemTagsTable)
+	_, err := r.db.Exec(query, itemId, tagId)
+
+	return err
+}
+
+func (r *TagsPostgres) Detach(userId, itemId, tagId int) error {
+	query := fmt.Sprintf("DELETE FROM %s it USING %s t WHERE it.tag_id = t.id AND t.user_id = $1 AND it.item_id = $2 AND it.tag_id = $3",
+		itemTagsTable, tagsTable)
+	_, err := r.db.Exec(query, userId, itemId, tagId)
+
+	return err
+}
+
+func (r *TagsPostgres) GetByItem(userId, itemId int) ([]todo.Tag, error) {
+	var tags []todo.Tag
+	query := fmt.Sprintf(`SELECT t.id, t.name, t.color FROM %s t INNER JOIN %s it on it.tag_id = t.id
+									WHERE t.user_id = $1 AND it.item_id = $2 ORDER BY t.name`,
+		tagsTable, itemTagsTable)
+	err := r.db.Select(&tags, query, userId, itemId)
+
+	return tags, err
+}
+
+// Задачи из всех списков пользователя, помеченные метками с именами names.
+// При matchAll задача должна иметь все метки, иначе - хотя бы одну
+func (r *TagsPostgres) GetItems(userId int, names []string, matchAll bool) ([]todo.TodoItem, error) {
+	var items []todo.TodoItem
+
+	having := "1"
+	if matchAll {
+		having = "cardinality($2::varchar[])"
+	}
+
+	query := fmt.Sprintf(`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id
+									WHERE ul.user_id = $1 AND ti.id IN (
+										SELECT it.item_id FROM %s it INNER JOIN %s t on t.id = it.tag_id
+										WHERE t.user_id = $1 AND t.name = ANY($2)
+										GROUP BY it.item_id HAVING count(DISTINCT t.id) >= %s)
+									ORDER BY li.list_id, ti.id`,
+		todoItemFields, todoItemsTable, listsItemsTable, usersListsTable, itemTagsTable, tagsTable, having)
+	if err := r.db.Select(&items, query, userId, pq.Array(names)); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"todo-app"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestTagsPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTagsPostgres(db)
+
+	type args struct {
+		userId int
+		tag    todo.Tag
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func(args args, id int)
+		args    args
+		id      int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			args: args{
+				userId: 1,
+				tag:    todo.Tag{Name: "work", Color: "#ff0000"},
+			},
+			id: 3,
+			mock: func(args args, id int) {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO tags").
+					WithArgs(args.userId, args.tag.Name, args.tag.Color).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "Duplicate Name",
+			args: args{
+				userId: 1,
+				tag:    todo.Tag{Name: "work"},
+			},
+			mock: func(args args, id int) {
+				mock.ExpectQuery("INSERT INTO tags").
+					WithArgs(args.userId, args.tag.Name, args.tag.Color).WillReturnError(errors.New("duplicate key value"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock(testCase.args, testCase.id)
+
+			got, err := r.Create(testCase.args.userId, testCase.args.tag)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagsPostgres_GetAll(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTagsPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		userId  int
+		want    []todo.Tag
+		wantErr bool
+	}{
+		{
+			name:   "OK",
+			userId: 1,
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "color"}).
+					AddRow(2, "home", "").
+					AddRow(1, "work", "#ff0000")
+				mock.ExpectQuery("SELECT id, name, color FROM tags WHERE user_id = (.+) ORDER BY name").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			want: []todo.Tag{
+				{Id: 2, Name: "home"},
+				{Id: 1, Name: "work", Color: "#ff0000"},
+			},
+		},
+		{
+			name:   "Error Select",
+			userId: 1,
+			mock: func() {
+				mock.ExpectQuery("SELECT id, name, color FROM tags").
+					WithArgs(1).WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetAll(testCase.userId)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagsPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTagsPostgres(db)
+
+	type args struct {
+		userId int
+		tagId  int
+		input  todo.UpdateTagInput
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "OK_AllFields",
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET name=\\$1, color=\\$2 WHERE user_id = \\$3 AND id = \\$4").
+					WithArgs("new name", "#000000", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			args: args{
+				userId: 1,
+				tagId:  2,
+				input:  todo.UpdateTagInput{Name: stringPointer("new name"), Color: stringPointer("#000000")},
+			},
+		},
+		{
+			name: "OK_OnlyColor",
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET color=\\$1 WHERE user_id = \\$2 AND id = \\$3").
+					WithArgs("#000000", 1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			args: args{
+				userId: 1,
+				tagId:  2,
+				input:  todo.UpdateTagInput{Color: stringPointer("#000000")},
+			},
+		},
+		{
+			name: "Error Exec",
+			mock: func() {
+				mock.ExpectExec("UPDATE tags SET").
+					WithArgs("new name", 1, 2).WillReturnError(errors.New("error update"))
+			},
+			args: args{
+				userId: 1,
+				tagId:  2,
+				input:  todo.UpdateTagInput{Name: stringPointer("new name")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Update(testCase.args.userId, testCase.args.tagId, testCase.args.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTagsPostgres_AttachDetach(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTagsPostgres(db)
+
+	mock.ExpectExec("INSERT INTO item_tags \\(item_id, tag_id\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT DO NOTHING").
+		WithArgs(5, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.Attach(5, 7))
+
+	mock.ExpectExec("DELETE FROM item_tags it USING tags t").
+		WithArgs(1, 5, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, r.Detach(1, 5, 7))
+
+	mock.ExpectExec("DELETE FROM item_tags it USING tags t").
+		WithArgs(1, 5, 7).WillReturnError(errors.New("some error"))
+	assert.Error(t, r.Detach(1, 5, 7))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagsPostgres_GetItems(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTagsPostgres(db)
+
+	type args struct {
+		userId   int
+		names    []string
+		matchAll bool
+	}
+
+	testTable := []struct {
+		name    string
+		mock    func(args args)
+		args    args
+		want    []todo.TodoItem
+		wantErr bool
+	}{
+		{
+			name: "OK Match All",
+			args: args{userId: 1, names: []string{"work", "urgent"}, matchAll: true},
+			mock: func(args args) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "list_id"}).
+					AddRow(3, "title3", "description3", false, 1).
+					AddRow(8, "title8", "description8", true, 4)
+				mock.ExpectQuery("SELECT (.+), li.list_id FROM todo_items ti (.+) HAVING count\\(DISTINCT t.id\\) >= cardinality").
+					WithArgs(args.userId, pq.Array(args.names)).WillReturnRows(rows)
+			},
+			want: []todo.TodoItem{
+				{Id: 3, Title: "title3", Description: "description3", ListId: 1},
+				{Id: 8, Title: "title8", Description: "description8", Done: true, ListId: 4},
+			},
+		},
+		{
+			name: "OK Match Any",
+			args: args{userId: 1, names: []string{"work", "urgent"}},
+			mock: func(args args) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "list_id"}).
+					AddRow(3, "title3", "description3", false, 1)
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) HAVING count\\(DISTINCT t.id\\) >= 1").
+					WithArgs(args.userId, pq.Array(args.names)).WillReturnRows(rows)
+			},
+			want: []todo.TodoItem{
+				{Id: 3, Title: "title3", Description: "description3", ListId: 1},
+			},
+		},
+		{
+			name: "Error Select",
+			args: args{userId: 1, names: []string{"work"}, matchAll: true},
+			mock: func(args args) {
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
+					WithArgs(args.userId, pq.Array(args.names)).WillReturnError(errors.New("some error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock(testCase.args)
+
+			got, err := r.GetItems(testCase.args.userId, testCase.args.names, testCase.args.matchAll)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
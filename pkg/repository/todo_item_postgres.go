@@ -1,13 +1,26 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 	"todo-app"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// Поля задачи, выбираемые из таблицы todo_items (алиас ti)
+const todoItemFields = "ti.id, ti.title, ti.description, ti.done, ti.due_date, ti.due_all_day, ti.due_timezone, ti.priority, ti.parent_id, ti.recurrence, ti.occurrence, ti.position"
+
+// Условие просроченной задачи: не выполнена и срок прошел.
+// Срок "на весь день" истекает в конце суток, поэтому к нему прибавляется день
+const overdueCondition = `ti.done = false AND ti.due_date IS NOT NULL AND
+									(CASE WHEN ti.due_all_day THEN ti.due_date + interval '1 day' ELSE ti.due_date END) < now()`
+
 type TodoItemPostgres struct {
 	db *sqlx.DB
 }
@@ -25,9 +38,24 @@ func (r *TodoItemPostgres) Create(listId int, item todo.TodoItem) (int, error) {
 	}
 
 	var itemId int
-	createItemQuery := fmt.Sprintf("INSERT INTO %s (title, description) values ($1, $2) RETURNING id", todoItemsTable)
+	if item.Occurrence == 0 {
+		item.Occurrence = 1
+	}
 
-	row := tx.QueryRow(createItemQuery, item.Title, item.Description)
+	// Новая задача добавляется в конец списка
+	var last string
+	lastPositionQuery := fmt.Sprintf(`SELECT COALESCE(max(ti.position), '') FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									WHERE li.list_id = $1`, todoItemsTable, listsItemsTable)
+	if err := tx.QueryRow(lastPositionQuery, listId).Scan(&last); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	createItemQuery := fmt.Sprintf(`INSERT INTO %s (title, description, due_date, due_all_day, due_timezone, priority, parent_id, recurrence, occurrence, position)
+									values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`, todoItemsTable)
+
+	row := tx.QueryRow(createItemQuery, item.Title, item.Description, item.DueDate, item.DueAllDay, item.DueTimezone, item.Priority, item.ParentId,
+		item.Recurrence, item.Occurrence, nextPosition(last))
 	err = row.Scan(&itemId)
 	if err != nil {
 		tx.Rollback()
@@ -44,12 +72,108 @@ func (r *TodoItemPostgres) Create(listId int, item todo.TodoItem) (int, error) {
 	return itemId, tx.Commit()
 }
 
-func (r *TodoItemPostgres) GetAll(userId, listId int) ([]todo.TodoItem, error) {
+// Сортировки задач списка для постраничной выдачи
+var itemSorts = map[string]pageSort{
+	todo.ItemSortDefault: {columns: []pageColumn{{"ti.position", cursorText}, {"ti.id", cursorInt}}},
+	todo.ItemSortPriority: {columns: []pageColumn{{"ti.priority", cursorInt}, {"ti.created_at", cursorTime}, {"ti.id", cursorInt}},
+		defaultDesc: true},
+	todo.ItemSortTitle:   {columns: []pageColumn{{"ti.title", cursorText}, {"ti.id", cursorInt}}},
+	todo.ItemSortCreated: {columns: []pageColumn{{"ti.created_at", cursorTime}, {"ti.id", cursorInt}}},
+}
+
+// Задача вместе со временем создания, которое нужно для курсора
+type itemPageRow struct {
+	todo.TodoItem
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Значения полей сортировки задачи для курсора, в порядке itemSorts
+func itemCursorValues(sort string, row itemPageRow) []string {
+	id := strconv.Itoa(row.Id)
+	createdAt := row.CreatedAt.Format(time.RFC3339Nano)
+	switch sort {
+	case todo.ItemSortPriority:
+		return []string{strconv.Itoa(int(row.Priority)), createdAt, id}
+	case todo.ItemSortTitle:
+		return []string{row.Title, id}
+	case todo.ItemSortCreated:
+		return []string{createdAt, id}
+	default:
+		return []string{row.Position, id}
+	}
+}
+
+// Задачи списка по query, возвращает курсор следующей страницы ("" - страница последняя)
+func (r *TodoItemPostgres) GetAll(userId, listId int, query todo.ItemsQuery) ([]todo.TodoItem, string, error) {
+	var rows []itemPageRow
+
+	sort, ok := itemSorts[query.Sort]
+	if !ok {
+		return nil, "", query.Validate()
+	}
+
+	args := []interface{}{listId, userId}
+	conditions := ""
+	if query.Done != nil {
+		args = append(args, *query.Done)
+		conditions += fmt.Sprintf(" AND ti.done = $%d", len(args))
+	}
+	if query.Query != "" {
+		args = append(args, likePattern(query.Query))
+		conditions += fmt.Sprintf(" AND ti.title ILIKE $%d", len(args))
+	}
+	if query.Cursor != "" {
+		cursor, err := todo.DecodeCursor(query.Cursor, query.Sort, query.Order)
+		if err != nil {
+			return nil, "", err
+		}
+		after, err := sort.after(query.Order, cursor.Values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions += " AND " + after
+	}
+	limitQuery := ""
+	if query.Limit > 0 {
+		args = append(args, query.Limit+1)
+		limitQuery = fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	itemsQuery := fmt.Sprintf(`SELECT %s, ti.created_at FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id WHERE li.list_id = $1 AND ul.user_id = $2%s%s%s`,
+		todoItemFields, todoItemsTable, listsItemsTable, usersListsTable, conditions, sort.orderBy(query.Order), limitQuery)
+	if err := r.db.Select(&rows, itemsQuery, args...); err != nil {
+		return nil, "", err
+	}
+
+	n, next := nextPage(len(rows), query.Limit, query.Sort, query.Order, func(last int) []string {
+		return itemCursorValues(query.Sort, rows[last])
+	})
+
+	var items []todo.TodoItem
+	for _, row := range rows[:n] {
+		items = append(items, row.TodoItem)
+	}
+
+	return items, next, nil
+}
+
+// Просроченные задачи пользователя, отсортированные по сроку.
+// Если listId использовать не нужно (поиск по всем спискам), передать -1
+func (r *TodoItemPostgres) GetOverdue(userId, listId int) ([]todo.TodoItem, error) {
 	var items []todo.TodoItem
-	query := fmt.Sprintf(`SELECT ti.id, ti.title, ti.description, ti.done FROM %s ti INNER JOIN %s li on li.item_id = ti.id
-									INNER JOIN %s ul on ul.list_id = li.list_id WHERE li.list_id = $1 AND ul.user_id = $2`,
-		todoItemsTable, listsItemsTable, usersListsTable)
-	if err := r.db.Select(&items, query, listId, userId); err != nil {
+
+	args := []interface{}{userId}
+	listQuery := ""
+	if listId > 0 {
+		listQuery = " AND li.list_id = $2"
+		args = append(args, listId)
+	}
+
+	query := fmt.Sprintf(`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id WHERE ul.user_id = $1%s AND %s ORDER BY ti.due_date`,
+		todoItemFields, todoItemsTable, listsItemsTable, usersListsTable, listQuery, overdueCondition)
+	if err := r.db.Select(&items, query, args...); err != nil {
 		return nil, err
 	}
 
@@ -58,9 +182,9 @@ func (r *TodoItemPostgres) GetAll(userId, listId int) ([]todo.TodoItem, error) {
 
 func (r *TodoItemPostgres) GetById(userId, itemId int) (todo.TodoItem, error) {
 	var item todo.TodoItem
-	query := fmt.Sprintf(`SELECT ti.id, ti.title, ti.description, ti.done FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+	query := fmt.Sprintf(`SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
 									INNER JOIN %s ul on ul.list_id = li.list_id WHERE ti.id = $1 AND ul.user_id = $2`,
-		todoItemsTable, listsItemsTable, usersListsTable)
+		todoItemFields, todoItemsTable, listsItemsTable, usersListsTable)
 	if err := r.db.Get(&item, query, itemId, userId); err != nil {
 		return item, err
 	}
@@ -68,14 +192,32 @@ func (r *TodoItemPostgres) GetById(userId, itemId int) (todo.TodoItem, error) {
 	return item, nil
 }
 
+// Рекурсивная выборка id задачи пользователя и всех ее подзадач (алиас subtree).
+// UNION вместо UNION ALL гарантирует завершение даже при цикле в данных
+const subtreeQuery = `WITH RECURSIVE subtree AS (
+									SELECT ti.id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									INNER JOIN %s ul on ul.list_id = li.list_id WHERE ul.user_id = $1 AND ti.id = $2
+									UNION
+									SELECT c.id FROM %s c INNER JOIN subtree s on c.parent_id = s.id)`
+
+// Удаляется задача вместе со всем поддеревом подзадач
 func (r *TodoItemPostgres) Delete(userId, itemId int) error {
-	query := fmt.Sprintf(`DELETE FROM %s ti USING %s li, %s ul 
-									WHERE ti.id = li.item_id AND li.list_id = ul.list_id AND ul.user_id = $1 AND ti.id = $2`,
-		todoItemsTable, listsItemsTable, usersListsTable)
+	query := fmt.Sprintf(subtreeQuery+` DELETE FROM %s WHERE id IN (SELECT id FROM subtree)`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoItemsTable, todoItemsTable)
 	_, err := r.db.Exec(query, userId, itemId)
 	return err
 }
 
+// id всех подзадач (любой вложенности) задачи itemId, без нее самой
+func (r *TodoItemPostgres) GetSubtreeIds(userId, itemId int) ([]int, error) {
+	ids := make([]int, 0)
+	query := fmt.Sprintf(subtreeQuery+` SELECT id FROM subtree WHERE id <> $2`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoItemsTable)
+	err := r.db.Select(&ids, query, userId, itemId)
+
+	return ids, err
+}
+
 func (r *TodoItemPostgres) Update(userId, itemId int, input todo.UpdateItemInput) error {
 	setValues := make([]string, 0)
 	args := make([]interface{}, 0)
@@ -99,6 +241,50 @@ func (r *TodoItemPostgres) Update(userId, itemId int, input todo.UpdateItemInput
 		argId++
 	}
 
+	if input.RemoveDueDate {
+		setValues = append(setValues, "due_date=NULL", "due_all_day=false", "due_timezone=''")
+	}
+
+	if input.DueDate != nil {
+		setValues = append(setValues, fmt.Sprintf("due_date=$%d", argId))
+		args = append(args, *input.DueDate)
+		argId++
+	}
+
+	if input.DueAllDay != nil {
+		setValues = append(setValues, fmt.Sprintf("due_all_day=$%d", argId))
+		args = append(args, *input.DueAllDay)
+		argId++
+	}
+
+	if input.DueTimezone != nil {
+		setValues = append(setValues, fmt.Sprintf("due_timezone=$%d", argId))
+		args = append(args, *input.DueTimezone)
+		argId++
+	}
+
+	if input.Priority != nil {
+		setValues = append(setValues, fmt.Sprintf("priority=$%d", argId))
+		args = append(args, *input.Priority)
+		argId++
+	}
+
+	if input.Recurrence != nil {
+		setValues = append(setValues, fmt.Sprintf("recurrence=$%d", argId))
+		args = append(args, *input.Recurrence)
+		argId++
+	}
+
+	if input.ParentId != nil {
+		if *input.ParentId == 0 {
+			setValues = append(setValues, "parent_id=NULL")
+		} else {
+			setValues = append(setValues, fmt.Sprintf("parent_id=$%d", argId))
+			args = append(args, *input.ParentId)
+			argId++
+		}
+	}
+
 	setQuery := strings.Join(setValues, ", ")
 
 	query := fmt.Sprintf(`UPDATE %s ti SET %s FROM %s li, %s ul
@@ -106,6 +292,240 @@ func (r *TodoItemPostgres) Update(userId, itemId int, input todo.UpdateItemInput
 		todoItemsTable, setQuery, listsItemsTable, usersListsTable, argId, argId+1)
 	args = append(args, userId, itemId)
 
-	_, err := r.db.Exec(query, args...)
-	return err
+	if !input.CascadeDone || input.Done == nil {
+		_, err := r.db.Exec(query, args...)
+		return err
+	}
+
+	// Отметка вместе с подзадачами: обе команды выполняются в одной транзакции
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	cascadeQuery := fmt.Sprintf(subtreeQuery+` UPDATE %s SET done = $3 WHERE id IN (SELECT id FROM subtree)`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoItemsTable, todoItemsTable)
+	if _, err := tx.Exec(cascadeQuery, userId, itemId, *input.Done); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Перемещение задачи внутри списка при ручной сортировке. Строка списка блокируется,
+// поэтому одновременные перемещения в одном списке выполняются по очереди
+func (r *TodoItemPostgres) Move(userId, listId, itemId int, input todo.MoveInput) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err := lockList(tx, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var rows []rankedRow
+	rowsQuery := fmt.Sprintf(`SELECT ti.id, ti.position FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									WHERE li.list_id = $1 ORDER BY ti.position, ti.id`, todoItemsTable, listsItemsTable)
+	if err := tx.Select(&rows, rowsQuery, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	position, renumber, err := placeRanked(rows, itemId, input)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if renumber == nil {
+		renumber = []rankedRow{{Id: itemId, Position: position}}
+	}
+
+	filter := fmt.Sprintf("t.id IN (SELECT item_id FROM %s WHERE list_id = $3)", listsItemsTable)
+	if err := updateRanks(tx, todoItemsTable, "id", filter, listId, renumber); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Блокировка списка пользователя на время изменения его задач. Если список не принадлежит пользователю - sql.ErrNoRows
+func lockList(tx *sqlx.Tx, userId, listId int) error {
+	var id int
+	query := fmt.Sprintf(`SELECT tl.id FROM %s tl INNER JOIN %s ul on ul.list_id = tl.id
+									WHERE ul.user_id = $1 AND tl.id = $2 FOR UPDATE OF tl`, todoListsTable, usersListsTable)
+	return tx.Get(&id, query, userId, listId)
+}
+
+// Задача пользователя со всеми подзадачами в порядке ручной сортировки. Первой идет сама задача
+func selectSubtree(tx *sqlx.Tx, userId, itemId int) ([]todo.TodoItem, error) {
+	var items []todo.TodoItem
+	query := fmt.Sprintf(subtreeQuery+` SELECT %s, li.list_id FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									WHERE ti.id IN (SELECT id FROM subtree) ORDER BY ti.id <> $2, ti.position, ti.id`,
+		todoItemsTable, listsItemsTable, usersListsTable, todoItemsTable, todoItemFields, todoItemsTable, listsItemsTable)
+	if err := tx.Select(&items, query, userId, itemId); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return items, nil
+}
+
+// Последний ранг в списке (пустая строка - список пуст)
+func lastPosition(tx *sqlx.Tx, listId int) (string, error) {
+	var last string
+	query := fmt.Sprintf(`SELECT COALESCE(max(ti.position), '') FROM %s ti INNER JOIN %s li on li.item_id = ti.id
+									WHERE li.list_id = $1`, todoItemsTable, listsItemsTable)
+	err := tx.Get(&last, query, listId)
+
+	return last, err
+}
+
+// Перенос задачи вместе с подзадачами в конец другого списка. Оба списка должны принадлежать пользователю.
+// Задача становится задачей верхнего уровня, т.к. родитель остается в исходном списке
+func (r *TodoItemPostgres) MoveToList(userId, itemId, listId int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err := lockList(tx, userId, listId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	items, err := selectSubtree(tx, userId, itemId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if items[0].ListId == listId {
+		tx.Rollback()
+		return errors.New("item is already in this list")
+	}
+
+	last, err := lastPosition(tx, listId)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	ids := make([]int64, len(items))
+	ranks := make([]rankedRow, len(items))
+	for i, item := range items {
+		last = nextPosition(last)
+		ids[i] = int64(item.Id)
+		ranks[i] = rankedRow{Id: item.Id, Position: last}
+	}
+
+	moveQuery := fmt.Sprintf("UPDATE %s SET list_id = $1 WHERE item_id = ANY($2::int[])", listsItemsTable)
+	if _, err := tx.Exec(moveQuery, listId, pq.Array(ids)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	parentQuery := fmt.Sprintf("UPDATE %s SET parent_id = NULL WHERE id = $1", todoItemsTable)
+	if _, err := tx.Exec(parentQuery, itemId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	filter := fmt.Sprintf("t.id IN (SELECT item_id FROM %s WHERE list_id = $3)", listsItemsTable)
+	if err := updateRanks(tx, todoItemsTable, "id", filter, listId, ranks); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Копирование задачи вместе с подзадачами и метками в конец другого (или того же) списка.
+// Оба списка должны принадлежать пользователю. Возвращает id копии задачи
+func (r *TodoItemPostgres) CopyToList(userId, itemId, listId int) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := lockList(tx, userId, listId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	items, err := selectSubtree(tx, userId, itemId)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	last, err := lastPosition(tx, listId)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// Ранги копий назначаются в порядке исходных задач, а вставляются задачи от корня к листьям,
+	// чтобы id родителя копии был известен к моменту вставки подзадачи
+	positions := make(map[int]string, len(items))
+	children := make(map[int][]todo.TodoItem)
+	for _, item := range items {
+		last = nextPosition(last)
+		positions[item.Id] = last
+		if item.Id != itemId && item.ParentId != nil {
+			children[*item.ParentId] = append(children[*item.ParentId], item)
+		}
+	}
+
+	createItemQuery := fmt.Sprintf(`INSERT INTO %s (title, description, done, due_date, due_all_day, due_timezone, priority, parent_id, recurrence, occurrence, position)
+									values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`, todoItemsTable)
+	createListItemsQuery := fmt.Sprintf("INSERT INTO %s (list_id, item_id) values ($1, $2)", listsItemsTable)
+	copyTagsQuery := fmt.Sprintf("INSERT INTO %s (item_id, tag_id) SELECT $1, tag_id FROM %s WHERE item_id = $2", itemTagsTable, itemTagsTable)
+
+	newIds := make(map[int]int, len(items))
+	queue := []todo.TodoItem{items[0]}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		var parentId *int
+		if item.Id != itemId {
+			id := newIds[*item.ParentId]
+			parentId = &id
+		}
+
+		var newId int
+		row := tx.QueryRow(createItemQuery, item.Title, item.Description, item.Done, item.DueDate, item.DueAllDay, item.DueTimezone, item.Priority,
+			parentId, item.Recurrence, item.Occurrence, positions[item.Id])
+		if err := row.Scan(&newId); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		newIds[item.Id] = newId
+
+		if _, err := tx.Exec(createListItemsQuery, listId, newId); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		if _, err := tx.Exec(copyTagsQuery, newId, item.Id); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+
+		queue = append(queue, children[item.Id]...)
+	}
+
+	return newIds[itemId], tx.Commit()
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log"
 	"testing"
+	"time"
 	"todo-app"
 
 	"github.com/stretchr/testify/assert"
@@ -46,9 +47,12 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.Title, args.item.Description, args.item.DueDate, args.item.DueAllDay, args.item.DueTimezone, args.item.Priority, args.item.ParentId, args.item.Recurrence, 1, "k").WillReturnRows(rows)
 
 				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).
 					WillReturnResult(sqlmock.NewResult(1, 1))
@@ -68,9 +72,12 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id).RowError(1, errors.New("some error"))
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.Title, args.item.Description, args.item.DueDate, args.item.DueAllDay, args.item.DueTimezone, args.item.Priority, args.item.ParentId, args.item.Recurrence, 1, "k").WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
@@ -88,9 +95,12 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id).RowError(1, errors.New("some error"))
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.Title, args.item.Description, args.item.DueDate, args.item.DueAllDay, args.item.DueTimezone, args.item.Priority, args.item.ParentId, args.item.Recurrence, 1, "k").WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
@@ -109,9 +119,12 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.Title, args.item.Description, args.item.DueDate, args.item.DueAllDay, args.item.DueTimezone, args.item.Priority, args.item.ParentId, args.item.Recurrence, 1, "k").WillReturnRows(rows)
 
 				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).
 					WillReturnError(errors.New("some error"))
@@ -146,9 +159,12 @@ func TestTodoItemPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").WithArgs(args.listId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_items").
-					WithArgs(args.item.Title, args.item.Description).WillReturnRows(rows)
+					WithArgs(args.item.Title, args.item.Description, args.item.DueDate, args.item.DueAllDay, args.item.DueTimezone, args.item.Priority, args.item.ParentId, args.item.Recurrence, 1, "k").WillReturnRows(rows)
 
 				mock.ExpectExec("INSERT INTO lists_items").WithArgs(args.listId, id).
 					WillReturnResult(sqlmock.NewResult(1, 1))
@@ -187,12 +203,14 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 	type args struct {
 		userId int
 		listId int
+		query  todo.ItemsQuery
 	}
 	tests := []struct {
 		name    string
 		mock    func()
 		input   args
 		want    []todo.TodoItem
+		next    string
 		wantErr bool
 	}{
 		{
@@ -203,7 +221,7 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 					AddRow(2, "title2", "description2", false).
 					AddRow(3, "title3", "description3", false)
 
-				mock.ExpectQuery("SELECT ti.id, ti.title, ti.description, ti.done FROM todo_items ti").
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
 					WithArgs(1, 1).WillReturnRows(rows)
 			},
 			input: args{
@@ -221,7 +239,7 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "title", "description", "done"})
 
-				mock.ExpectQuery("SELECT ti.id, ti.title, ti.description, ti.done FROM todo_items ti").
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
 					WithArgs(1, 1).WillReturnRows(rows)
 			},
 			input: args{
@@ -229,10 +247,78 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 				userId: 1,
 			},
 		},
+		{
+			name: "Ok Sort Priority",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "priority"}).
+					AddRow(2, "title2", "description2", false, 4).
+					AddRow(1, "title1", "description1", true, 2)
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) ORDER BY ti.priority DESC, ti.created_at").
+					WithArgs(1, 1).WillReturnRows(rows)
+			},
+			input: args{
+				listId: 1,
+				userId: 1,
+				query:  todo.ItemsQuery{Sort: todo.ItemSortPriority},
+			},
+			want: []todo.TodoItem{
+				{Id: 2, Title: "title2", Description: "description2", Done: false, Priority: todo.PriorityUrgent},
+				{Id: 1, Title: "title1", Description: "description1", Done: true, Priority: todo.PriorityMedium},
+			},
+		},
+		{
+			name: "Invalid Sort",
+			mock: func() {},
+			input: args{
+				listId: 1,
+				userId: 1,
+				query:  todo.ItemsQuery{Sort: "due"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Ok Page",
+			mock: func() {
+				created := time.Date(2022, 6, 11, 9, 0, 0, 0, time.UTC)
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "priority", "created_at"}).
+					AddRow(7, "buy milk", "", false, 2, created).
+					AddRow(5, "buy bread", "", false, 2, created).
+					AddRow(4, "buy tea", "", false, 1, created)
+
+				// Следующая страница после задачи с приоритетом 3: по убыванию приоритета, затем по времени создания и id
+				mock.ExpectQuery(`SELECT (.+), ti.created_at FROM todo_items ti (.+) WHERE li.list_id = \$1 AND ul.user_id = \$2 `+
+					`AND ti.done = \$3 AND ti.title ILIKE \$4 `+
+					`AND \(ti.priority < \$5 OR ti.priority = \$5 AND ti.created_at > \$6 OR ti.priority = \$5 AND ti.created_at = \$6 AND ti.id > \$7\) `+
+					`ORDER BY ti.priority DESC, ti.created_at, ti.id LIMIT \$8`).
+					WithArgs(1, 1, false, "%buy%", 3, time.Date(2022, 6, 10, 9, 0, 0, 0, time.UTC), 9, 3).WillReturnRows(rows)
+			},
+			input: args{
+				listId: 1,
+				userId: 1,
+				query: todo.ItemsQuery{Sort: todo.ItemSortPriority, Done: boolPointer(false), Query: "buy", Limit: 2,
+					Cursor: todo.Cursor{Sort: todo.ItemSortPriority, Values: []string{"3", "2022-06-10T09:00:00Z", "9"}}.Encode()},
+			},
+			want: []todo.TodoItem{
+				{Id: 7, Title: "buy milk", Priority: todo.PriorityMedium},
+				{Id: 5, Title: "buy bread", Priority: todo.PriorityMedium},
+			},
+			next: todo.Cursor{Sort: todo.ItemSortPriority, Values: []string{"2", "2022-06-11T09:00:00Z", "5"}}.Encode(),
+		},
+		{
+			name: "Cursor Values Mismatch",
+			mock: func() {},
+			input: args{
+				listId: 1,
+				userId: 1,
+				query:  todo.ItemsQuery{Limit: 2, Cursor: todo.Cursor{Values: []string{"V"}}.Encode()},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Error Select",
 			mock: func() {
-				mock.ExpectQuery("SELECT ti.id, ti.title, ti.description, ti.done FROM todo_items ti").
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
 					WithArgs(1, 1).WillReturnError(errors.New("some error"))
 			},
 			input: args{
@@ -247,7 +333,94 @@ func TestTodoItemPostgres_GetAll(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mock()
 
-			got, err := r.GetAll(testCase.input.userId, testCase.input.listId)
+			got, next, err := r.GetAll(testCase.input.userId, testCase.input.listId, testCase.input.query)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+				assert.Equal(t, testCase.next, next)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_GetOverdue(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	due := time.Date(2022, 5, 30, 18, 0, 0, 0, time.UTC)
+
+	type args struct {
+		userId int
+		listId int
+	}
+	tests := []struct {
+		name    string
+		mock    func()
+		input   args
+		want    []todo.TodoItem
+		wantErr bool
+	}{
+		{
+			name: "Ok By List",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "due_date", "due_all_day", "due_timezone"}).
+					AddRow(1, "title1", "description1", false, due, false, "Europe/Moscow")
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE ul.user_id = \\$1 AND li.list_id = \\$2 AND ti.done = false").
+					WithArgs(1, 2).WillReturnRows(rows)
+			},
+			input: args{
+				userId: 1,
+				listId: 2,
+			},
+			want: []todo.TodoItem{
+				{Id: 1, Title: "title1", Description: "description1", DueDate: &due, DueTimezone: "Europe/Moscow"},
+			},
+		},
+		{
+			name: "Ok All Lists",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "title", "description", "done", "due_date", "due_all_day", "due_timezone"}).
+					AddRow(1, "title1", "description1", false, due, true, "")
+
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti (.+) WHERE ul.user_id = \\$1 AND ti.done = false").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: args{
+				userId: 1,
+				listId: -1,
+			},
+			want: []todo.TodoItem{
+				{Id: 1, Title: "title1", Description: "description1", DueDate: &due, DueAllDay: true},
+			},
+		},
+		{
+			name: "Error Select",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
+					WithArgs(1).WillReturnError(errors.New("some error"))
+			},
+			input: args{
+				userId: 1,
+				listId: -1,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetOverdue(testCase.input.userId, testCase.input.listId)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -285,7 +458,7 @@ func TestTodoItemPostgres_GetById(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id", "title", "description", "done"}).
 					AddRow(1, "title1", "description1", true)
 
-				mock.ExpectQuery("SELECT ti.id, ti.title, ti.description, ti.done FROM todo_items ti").
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
 					WithArgs(1, 1).WillReturnRows(rows)
 			},
 			input: args{
@@ -299,7 +472,7 @@ func TestTodoItemPostgres_GetById(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "title", "description", "done"})
 
-				mock.ExpectQuery("SELECT ti.id, ti.title, ti.description, ti.done FROM todo_items ti").
+				mock.ExpectQuery("SELECT (.+) FROM todo_items ti").
 					WithArgs(404, 1).WillReturnRows(rows)
 			},
 			input: args{
@@ -348,8 +521,8 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 		{
 			name: "Ok",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM todo_items ti USING lists_items li, user_lists").
-					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("WITH RECURSIVE subtree AS (.+) DELETE FROM todo_items WHERE id IN \\(SELECT id FROM subtree\\)").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 3))
 			},
 			input: args{
 				itemId: 1,
@@ -359,7 +532,7 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 		{
 			name: "Not Found",
 			mock: func() {
-				mock.ExpectExec("DELETE FROM todo_items ti").
+				mock.ExpectExec("DELETE FROM todo_items").
 					WithArgs(1, 404).WillReturnError(errors.New("not found table"))
 			},
 			input: args{
@@ -385,6 +558,32 @@ func TestTodoItemPostgres_Delete(t *testing.T) {
 	}
 }
 
+func TestTodoItemPostgres_GetSubtreeIds(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3).AddRow(5)
+	mock.ExpectQuery("WITH RECURSIVE subtree AS (.+) SELECT id FROM subtree WHERE id <> \\$2").
+		WithArgs(1, 1).WillReturnRows(rows)
+
+	got, err := r.GetSubtreeIds(1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 5}, got)
+
+	mock.ExpectQuery("WITH RECURSIVE subtree AS").
+		WithArgs(1, 404).WillReturnError(errors.New("some error"))
+
+	_, err = r.GetSubtreeIds(1, 404)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestTodoItemPostgres_Update(t *testing.T) {
 	db, mock, err := sqlmock.Newx()
 	if err != nil {
@@ -519,6 +718,131 @@ func TestTodoItemPostgres_Update(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "OK_ParentId",
+			mock: func() {
+				mock.ExpectExec("UPDATE todo_items ti SET parent_id=\\$1").
+					WithArgs(7, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					ParentId: intPointer(7),
+				},
+			},
+		},
+		{
+			name: "OK_RemoveParent",
+			mock: func() {
+				mock.ExpectExec("UPDATE todo_items ti SET parent_id=NULL").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					ParentId: intPointer(0),
+				},
+			},
+		},
+		{
+			name: "OK_CascadeDone",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE todo_items ti SET done=\\$1").
+					WithArgs(true, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("WITH RECURSIVE subtree AS (.+) UPDATE todo_items SET done = \\$3").
+					WithArgs(1, 1, true).WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectCommit()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					Done:        boolPointer(true),
+					CascadeDone: true,
+				},
+			},
+		},
+		{
+			name: "Error_CascadeDone",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE todo_items ti SET done=\\$1").
+					WithArgs(true, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("WITH RECURSIVE subtree AS (.+) UPDATE todo_items SET done = \\$3").
+					WithArgs(1, 1, true).WillReturnError(errors.New("error update"))
+				mock.ExpectRollback()
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					Done:        boolPointer(true),
+					CascadeDone: true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "OK_Recurrence",
+			mock: func() {
+				mock.ExpectExec("UPDATE todo_items ti SET recurrence=\\$1").
+					WithArgs("FREQ=WEEKLY;BYDAY=MO", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					Recurrence: stringPointer("FREQ=WEEKLY;BYDAY=MO"),
+				},
+			},
+		},
+		{
+			name: "OK_Priority",
+			mock: func() {
+				mock.ExpectExec("UPDATE todo_items ti SET priority=\\$1").
+					WithArgs(todo.PriorityHigh, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					Priority: priorityPointer(todo.PriorityHigh),
+				},
+			},
+		},
+		{
+			name: "OK_DueDate",
+			mock: func() {
+				mock.ExpectExec("UPDATE todo_items ti SET due_date=\\$1, due_all_day=\\$2, due_timezone=\\$3").
+					WithArgs(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), true, "UTC", 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					DueDate:     timePointer(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)),
+					DueAllDay:   boolPointer(true),
+					DueTimezone: stringPointer("UTC"),
+				},
+			},
+		},
+		{
+			name: "OK_RemoveDueDate",
+			mock: func() {
+				mock.ExpectExec("UPDATE todo_items ti SET due_date=NULL, due_all_day=false, due_timezone=''").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: args{
+				itemId: 1,
+				userId: 1,
+				item_input: todo.UpdateItemInput{
+					RemoveDueDate: true,
+				},
+			},
+		},
 		{
 			name: "OK_ErrorExec",
 			mock: func() {
@@ -560,3 +884,287 @@ func stringPointer(s string) *string {
 func boolPointer(b bool) *bool {
 	return &b
 }
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func priorityPointer(p todo.Priority) *todo.Priority {
+	return &p
+}
+
+func timePointer(t time.Time) *time.Time {
+	return &t
+}
+
+func TestTodoItemPostgres_Move(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	testTable := []struct {
+		name    string
+		input   todo.MoveInput
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name:  "OK",
+			input: todo.MoveInput{BeforeId: intPointer(1)},
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl (.+) FOR UPDATE OF tl").WithArgs(2, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				rows := sqlmock.NewRows([]string{"id", "position"}).AddRow(1, "F").AddRow(2, "V").AddRow(3, "k")
+				mock.ExpectQuery("SELECT ti.id, ti.position FROM todo_items ti").WithArgs(5).WillReturnRows(rows)
+
+				// Меняется только ранг перемещаемой задачи
+				mock.ExpectExec("UPDATE todo_items t SET position = v.position FROM unnest").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 5).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "List Not Found",
+			input: todo.MoveInput{BeforeId: intPointer(1)},
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(2, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name:  "Neighbour Not Found",
+			input: todo.MoveInput{AfterId: intPointer(9)},
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(2, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				rows := sqlmock.NewRows([]string{"id", "position"}).AddRow(1, "F").AddRow(3, "k")
+				mock.ExpectQuery("SELECT ti.id, ti.position FROM todo_items ti").WithArgs(5).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Move(2, 5, 3, testCase.input)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_MoveToList(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	subtreeColumns := []string{"id", "title", "description", "done", "parent_id", "position", "list_id"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				rows := sqlmock.NewRows(subtreeColumns).
+					AddRow(3, "title", "", false, 2, "V", 4).
+					AddRow(6, "child", "", false, 3, "k", 4)
+				mock.ExpectQuery("WITH RECURSIVE subtree AS (.+) SELECT (.+) FROM todo_items ti").WithArgs(1, 3).WillReturnRows(rows)
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM todo_items ti").WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
+				mock.ExpectExec("UPDATE lists_items SET list_id = (.+) WHERE item_id = ANY").
+					WithArgs(5, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectExec("UPDATE todo_items SET parent_id = NULL").
+					WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectExec("UPDATE todo_items t SET position = v.position FROM unnest").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 5).WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Target List Not Owned",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Item Not Owned",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				mock.ExpectQuery("WITH RECURSIVE subtree AS").WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows(subtreeColumns))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Same List",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				rows := sqlmock.NewRows(subtreeColumns).AddRow(3, "title", "", false, nil, "V", 5)
+				mock.ExpectQuery("WITH RECURSIVE subtree AS").WithArgs(1, 3).WillReturnRows(rows)
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.MoveToList(1, 3, 5)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoItemPostgres_CopyToList(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewTodoItemPostgres(db)
+
+	subtreeColumns := []string{"id", "title", "description", "done", "parent_id", "position", "list_id"}
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		id      int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			id:   10,
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				rows := sqlmock.NewRows(subtreeColumns).
+					AddRow(3, "title", "", false, nil, "V", 4).
+					AddRow(6, "child", "", true, 3, "k", 4)
+				mock.ExpectQuery("WITH RECURSIVE subtree AS").WithArgs(1, 3).WillReturnRows(rows)
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM todo_items ti").WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				// Корень копируется первым и без родителя
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs("title", "", false, nil, false, "", todo.PriorityNone, nil, "", 0, "V").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(5, 10).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO item_tags").WithArgs(10, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				// Подзадача ссылается на копию корня
+				mock.ExpectQuery("INSERT INTO todo_items").
+					WithArgs("child", "", true, nil, false, "", todo.PriorityNone, 10, "", 0, "k").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+				mock.ExpectExec("INSERT INTO lists_items").WithArgs(5, 11).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO item_tags").WithArgs(11, 6).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Insert Error",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT tl.id FROM todo_lists tl").WithArgs(1, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				rows := sqlmock.NewRows(subtreeColumns).AddRow(3, "title", "", false, nil, "V", 4)
+				mock.ExpectQuery("WITH RECURSIVE subtree AS").WithArgs(1, 3).WillReturnRows(rows)
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM todo_items ti").WithArgs(5).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				mock.ExpectQuery("INSERT INTO todo_items").WillReturnError(errors.New("insert error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.CopyToList(1, 3, 5)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.id, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -20,7 +20,8 @@ func NewTodoItemRedis(context *gin.Context, redisClient *redis.Client) *TodoItem
 	}
 }
 
-// Если listId или itemId использовать не нужно, передать -1 (что то одно)
+// Если listId или itemId использовать не нужно, передать -1 (что то одно).
+// В поле items:listId хранится только полная выборка в порядке по умолчанию, выборки с параметрами не кэшируются
 func (r *TodoItemRedis) HGet(userId, listId, itemId int) (string, error) {
 	var val string
 	var err error
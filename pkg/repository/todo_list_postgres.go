@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"todo-app"
 
@@ -18,46 +19,133 @@ func NewTodoListPostgres(db *sqlx.DB) *TodoListPostgres {
 	}
 }
 
+// Список создается в пространстве list.WorkspaceId (0 - личное пространство пользователя),
+// остальные участники пространства получают к нему унаследованный доступ
 func (r *TodoListPostgres) Create(userId int, list todo.TodoList) (int, error) {
-	tx, err := r.db.Begin() // запускаем транзакцию
+	tx, err := r.db.Beginx() // запускаем транзакцию
 	if err != nil {
 		return 0, err
 	}
 
+	workspaceId := list.WorkspaceId
+	if workspaceId == 0 {
+		personalQuery := fmt.Sprintf("SELECT id FROM %s WHERE personal_user_id = $1", workspacesTable)
+		if err := tx.Get(&workspaceId, personalQuery, userId); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
 	var id int
 	// Первой командой добавляем title и description, полученные от пользователя в таблицу с постами и получаем id нового поста
-	createListQuery := fmt.Sprintf("INSERT INTO %s (title, description) VALUES ($1, $2) RETURNING id", todoListsTable)
-	row := tx.QueryRow(createListQuery, list.Title, list.Description)
+	createListQuery := fmt.Sprintf("INSERT INTO %s (title, description, workspace_id) VALUES ($1, $2, $3) RETURNING id", todoListsTable)
+	row := tx.QueryRow(createListQuery, list.Title, list.Description, workspaceId)
 	if err := row.Scan(&id); err != nil {
 		tx.Rollback()
 		return 0, err
 	}
 
+	// Новый список добавляется в конец списков пользователя
+	var last string
+	lastPositionQuery := fmt.Sprintf("SELECT COALESCE(max(position), '') FROM %s WHERE user_id = $1", usersListsTable)
+	if err := tx.QueryRow(lastPositionQuery, userId).Scan(&last); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
 	// Второй командой создаем зависимости пользователя и созданного поста
-	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id) VALUES ($1, $2)", usersListsTable)
-	_, err = tx.Exec(createUsersListQuery, userId, id)
+	// Создатель списка становится его владельцем
+	createUsersListQuery := fmt.Sprintf("INSERT INTO %s (user_id, list_id, position, role) VALUES ($1, $2, $3, '%s')", usersListsTable, todo.ListRoleOwner)
+	_, err = tx.Exec(createUsersListQuery, userId, id, nextPosition(last))
 	if err != nil {
 		tx.Rollback()
 		return 0, err
 	}
 
+	memberIds := make([]int, 0)
+	membersQuery := fmt.Sprintf("SELECT user_id FROM %s WHERE workspace_id = $1 AND user_id <> $2 ORDER BY user_id", workspaceMembersTable)
+	if err := tx.Select(&memberIds, membersQuery, workspaceId, userId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := inheritLists(tx, workspaceId, memberIds, []int{id}); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
 	return id, tx.Commit() // Обязательно коммитим транзакцию
 }
 
-func (r *TodoListPostgres) GetAll(userId int) ([]todo.TodoList, error) { // Создаем слайс спизков определенного user`а
+// Сортировки списков пользователя для постраничной выдачи
+var listSorts = map[string]pageSort{
+	todo.ListSortDefault: {columns: []pageColumn{{"ul.position", cursorText}, {"tl.id", cursorInt}}},
+	todo.ListSortTitle:   {columns: []pageColumn{{"tl.title", cursorText}, {"tl.id", cursorInt}}},
+	todo.ListSortCreated: {columns: []pageColumn{{"tl.id", cursorInt}}},
+}
+
+// Значения полей сортировки списка для курсора, в порядке listSorts
+func listCursorValues(sort string, list todo.TodoList) []string {
+	id := strconv.Itoa(list.Id)
+	switch sort {
+	case todo.ListSortTitle:
+		return []string{list.Title, id}
+	case todo.ListSortCreated:
+		return []string{id}
+	default:
+		return []string{list.Position, id}
+	}
+}
+
+// Списки пользователя по query, возвращает курсор следующей страницы ("" - страница последняя)
+func (r *TodoListPostgres) GetAll(userId int, query todo.ListsQuery) ([]todo.TodoList, string, error) { // Создаем слайс спизков определенного user`а
 	var lists []todo.TodoList
 
-	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1",
-		todoListsTable, usersListsTable)
-	err := r.db.Select(&lists, query, userId)
+	sort, ok := listSorts[query.Sort]
+	if !ok {
+		return nil, "", query.Validate()
+	}
+
+	args := []interface{}{userId}
+	conditions := ""
+	if query.Query != "" {
+		args = append(args, likePattern(query.Query))
+		conditions += fmt.Sprintf(" AND tl.title ILIKE $%d", len(args))
+	}
+	if query.Cursor != "" {
+		cursor, err := todo.DecodeCursor(query.Cursor, query.Sort, query.Order)
+		if err != nil {
+			return nil, "", err
+		}
+		after, err := sort.after(query.Order, cursor.Values, &args)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions += " AND " + after
+	}
+	limitQuery := ""
+	if query.Limit > 0 {
+		args = append(args, query.Limit+1)
+		limitQuery = fmt.Sprintf(" LIMIT $%d", len(args))
+	}
 
-	return lists, err
+	listsQuery := fmt.Sprintf("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1%s%s%s",
+		todoListsTable, usersListsTable, conditions, sort.orderBy(query.Order), limitQuery)
+	if err := r.db.Select(&lists, listsQuery, args...); err != nil {
+		return nil, "", err
+	}
+
+	n, next := nextPage(len(lists), query.Limit, query.Sort, query.Order, func(last int) []string {
+		return listCursorValues(query.Sort, lists[last])
+	})
+
+	return lists[:n], next, nil
 }
 
 func (r *TodoListPostgres) GetById(userId, listId int) (todo.TodoList, error) {
 	var list todo.TodoList
 
-	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2",
+	query := fmt.Sprintf("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM %s tl INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND ul.list_id = $2",
 		todoListsTable, usersListsTable)
 	err := r.db.Get(&list, query, userId, listId)
 
@@ -101,3 +189,114 @@ func (r *TodoListPostgres) UpdateById(userId, listId int, list todo.UpdateListIn
 
 	return newList, err
 }
+
+// Перемещение списка среди списков пользователя. Порядок у каждого пользователя свой (ранг хранится в user_lists),
+// строки пользователя блокируются, поэтому одновременные перемещения выполняются по очереди
+func (r *TodoListPostgres) Move(userId, listId int, input todo.MoveInput) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	var rows []rankedRow
+	rowsQuery := fmt.Sprintf("SELECT list_id AS id, position FROM %s WHERE user_id = $1 ORDER BY position, list_id FOR UPDATE", usersListsTable)
+	if err := tx.Select(&rows, rowsQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	position, renumber, err := placeRanked(rows, listId, input)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if renumber == nil {
+		renumber = []rankedRow{{Id: listId, Position: position}}
+	}
+
+	if err := updateRanks(tx, usersListsTable, "list_id", "t.user_id = $3", userId, renumber); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Роль пользователя в списке. Если пользователь не участник списка - sql.ErrNoRows
+func (r *TodoListPostgres) GetRole(userId, listId int) (todo.ListRole, error) {
+	var role todo.ListRole
+	query := fmt.Sprintf("SELECT role FROM %s WHERE user_id = $1 AND list_id = $2", usersListsTable)
+	err := r.db.Get(&role, query, userId, listId)
+
+	return role, err
+}
+
+func (r *TodoListPostgres) GetMembers(listId int) ([]todo.ListMember, error) {
+	var members []todo.ListMember
+	query := fmt.Sprintf(`SELECT u.id AS user_id, u.name, u.username, ul.role FROM %s ul INNER JOIN %s u on u.id = ul.user_id
+									WHERE ul.list_id = $1 ORDER BY ul.id`, usersListsTable, usersTable)
+	err := r.db.Select(&members, query, listId)
+
+	return members, err
+}
+
+// id всех участников списка
+func (r *TodoListPostgres) GetMemberIds(listId int) ([]int, error) {
+	ids := make([]int, 0)
+	query := fmt.Sprintf("SELECT user_id FROM %s WHERE list_id = $1", usersListsTable)
+	err := r.db.Select(&ids, query, listId)
+
+	return ids, err
+}
+
+// id пользователя и всех, с кем у него есть хотя бы один общий список
+func (r *TodoListPostgres) GetCoMemberIds(userId int) ([]int, error) {
+	ids := make([]int, 0)
+	query := fmt.Sprintf(`SELECT $1::int UNION SELECT DISTINCT other.user_id FROM %s ul INNER JOIN %s other on other.list_id = ul.list_id
+									WHERE ul.user_id = $1`, usersListsTable, usersListsTable)
+	err := r.db.Select(&ids, query, userId)
+
+	return ids, err
+}
+
+// Добавление участника в список по username или изменение роли, если он уже участник.
+// Новый для пользователя список добавляется в конец его списков. Возвращает id участника
+func (r *TodoListPostgres) AddMember(listId int, username string, role todo.ListRole) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var memberId int
+	userQuery := fmt.Sprintf("SELECT id FROM %s WHERE username = $1", usersTable)
+	if err := tx.Get(&memberId, userQuery, username); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	var last string
+	lastPositionQuery := fmt.Sprintf("SELECT COALESCE(max(position), '') FROM %s WHERE user_id = $1", usersListsTable)
+	if err := tx.Get(&last, lastPositionQuery, memberId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	addQuery := fmt.Sprintf(`INSERT INTO %s (user_id, list_id, position, role) VALUES ($1, $2, $3, $4)
+									ON CONFLICT (user_id, list_id) DO UPDATE SET role = EXCLUDED.role, inherited = false`, usersListsTable)
+	if _, err := tx.Exec(addQuery, memberId, listId, nextPosition(last), role); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return memberId, tx.Commit()
+}
+
+// Исключение участника из списка по username. Возвращает id исключенного пользователя
+func (r *TodoListPostgres) RemoveMember(listId int, username string) (int, error) {
+	var memberId int
+	query := fmt.Sprintf(`DELETE FROM %s ul USING %s u WHERE u.id = ul.user_id AND ul.list_id = $1 AND u.username = $2
+									RETURNING ul.user_id`, usersListsTable, usersTable)
+	err := r.db.Get(&memberId, query, listId, username)
+
+	return memberId, err
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"todo-app"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
 )
@@ -45,13 +46,59 @@ func TestTodoListPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT id FROM workspaces WHERE personal_user_id").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
+				mock.ExpectQuery("INSERT INTO todo_lists").
+					WithArgs(args.list.Title, args.list.Description, 4).WillReturnRows(rows)
+
+				mock.ExpectQuery("SELECT (.+) FROM user_lists").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id, "V").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectQuery("SELECT user_id FROM workspace_members").WithArgs(4, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Shared Workspace",
+			args: args{
+				userId: 1,
+				list: todo.TodoList{
+					Title:       "test title",
+					Description: "test description",
+					WorkspaceId: 7,
+				},
+			},
+			id: 99,
+			mockBehavior: func(args args, id int) {
+				mock.ExpectBegin()
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_lists").
-					WithArgs(args.list.Title, args.list.Description).WillReturnRows(rows)
+					WithArgs(args.list.Title, args.list.Description, 7).WillReturnRows(rows)
 
-				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id).
+				mock.ExpectQuery("SELECT (.+) FROM user_lists").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id, "V").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				mock.ExpectQuery("SELECT user_id FROM workspace_members").WithArgs(7, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+
+				mock.ExpectQuery("SELECT u.id, COALESCE(.+) FROM unnest").WithArgs(pq.Array([]int{2, 3})).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "position"}).AddRow(2, "").AddRow(3, "V"))
+
+				mock.ExpectExec("INSERT INTO user_lists (.+) INNER JOIN workspace_members (.+) ON CONFLICT").
+					WithArgs(pq.Array([]int{2, 3}), pq.Array([]int{id, id}), pq.Array([]string{"V", "k"}), 7).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
 				mock.ExpectCommit()
 			},
 		},
@@ -67,9 +114,12 @@ func TestTodoListPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin() // Откроем транзакцию
 
+				mock.ExpectQuery("SELECT id FROM workspaces WHERE personal_user_id").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id).RowError(1, errors.New("some error"))
 				mock.ExpectQuery("INSERT INTO todo_lists").
-					WithArgs(args.list.Title, args.list.Description).WillReturnRows(rows)
+					WithArgs(args.list.Title, args.list.Description, 4).WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
@@ -87,9 +137,12 @@ func TestTodoListPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin()
 
+				mock.ExpectQuery("SELECT id FROM workspaces WHERE personal_user_id").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id).RowError(1, errors.New("some error"))
 				mock.ExpectQuery("INSERT INTO todo_lists").
-					WithArgs(args.list.Title, args.list.Description).WillReturnRows(rows)
+					WithArgs(args.list.Title, args.list.Description, 4).WillReturnRows(rows)
 
 				mock.ExpectRollback()
 			},
@@ -108,11 +161,17 @@ func TestTodoListPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin()
 
+				mock.ExpectQuery("SELECT id FROM workspaces WHERE personal_user_id").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_lists").
-					WithArgs(args.list.Title, args.list.Description).WillReturnRows(rows)
+					WithArgs(args.list.Title, args.list.Description, 4).WillReturnRows(rows)
+
+				mock.ExpectQuery("SELECT (.+) FROM user_lists").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
 
-				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id).
+				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id, "V").
 					WillReturnError(errors.New("some error"))
 
 				mock.ExpectRollback()
@@ -145,13 +204,22 @@ func TestTodoListPostgres_Create(t *testing.T) {
 			mockBehavior: func(args args, id int) {
 				mock.ExpectBegin()
 
+				mock.ExpectQuery("SELECT id FROM workspaces WHERE personal_user_id").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
 				rows := sqlmock.NewRows([]string{"id"}).AddRow(id)
 				mock.ExpectQuery("INSERT INTO todo_lists").
-					WithArgs(args.list.Title, args.list.Description).WillReturnRows(rows)
+					WithArgs(args.list.Title, args.list.Description, 4).WillReturnRows(rows)
 
-				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id).
+				mock.ExpectQuery("SELECT (.+) FROM user_lists").WithArgs(args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(""))
+
+				mock.ExpectExec("INSERT INTO user_lists").WithArgs(args.userId, id, "V").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
+				mock.ExpectQuery("SELECT user_id FROM workspace_members").WithArgs(4, args.userId).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
 				mock.ExpectCommit().WillReturnError(errors.New("Error Commit"))
 			},
 			wantErr: true,
@@ -189,7 +257,9 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 		name         string
 		mockBehavior mockBehavior
 		userId       int
+		query        todo.ListsQuery
 		lists        []todo.TodoList
+		next         string
 		wantErr      bool
 	}{
 		{
@@ -200,7 +270,7 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 					AddRow(2, "title2", "description2").
 					AddRow(3, "title3", "description3")
 
-				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description FROM ").
+				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM ").
 					WithArgs(userId).WillReturnRows(rows)
 			},
 			userId: 88,
@@ -215,15 +285,32 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 			mockBehavior: func(userId int) {
 				rows := sqlmock.NewRows([]string{"id", "title", "description"})
 
-				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description FROM ").
+				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM ").
 					WithArgs(userId).WillReturnRows(rows)
 			},
 			userId: 88,
 		},
+		{
+			name: "Ok Page",
+			mockBehavior: func(userId int) {
+				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
+					AddRow(5, "Work", "").
+					AddRow(2, "Shopping", "")
+
+				mock.ExpectQuery(`SELECT (.+) WHERE ul.user_id = \$1 AND tl.title ILIKE \$2 `+
+					`AND \(tl.title < \$3 OR tl.title = \$3 AND tl.id > \$4\) ORDER BY tl.title DESC, tl.id LIMIT \$5`).
+					WithArgs(userId, `%o%`, "Zoo", 9, 2).WillReturnRows(rows)
+			},
+			userId: 88,
+			query: todo.ListsQuery{Sort: todo.ListSortTitle, Order: todo.SortOrderDesc, Query: "o", Limit: 1,
+				Cursor: todo.Cursor{Sort: todo.ListSortTitle, Order: todo.SortOrderDesc, Values: []string{"Zoo", "9"}}.Encode()},
+			lists: []todo.TodoList{{Id: 5, Title: "Work"}},
+			next:  todo.Cursor{Sort: todo.ListSortTitle, Order: todo.SortOrderDesc, Values: []string{"Work", "5"}}.Encode(),
+		},
 		{
 			name: "Error Select",
 			mockBehavior: func(userId int) {
-				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description FROM ").
+				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM ").
 					WithArgs(userId).WillReturnError(errors.New("some error"))
 			},
 			userId:  88,
@@ -235,12 +322,13 @@ func TestTodoListPostgres_GetAll(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			testCase.mockBehavior(testCase.userId)
 
-			got, err := r.GetAll(testCase.userId)
+			got, next, err := r.GetAll(testCase.userId, testCase.query)
 			if testCase.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, testCase.lists, got)
+				assert.Equal(t, testCase.next, next)
 			}
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
@@ -276,7 +364,7 @@ func TestTodoLisrPostgres_GetById(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id", "title", "description"}).
 					AddRow(1, "title1", "description1")
 
-				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description FROM").
+				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM").
 					WithArgs(userId, listId).WillReturnRows(rows)
 			},
 			input: args{
@@ -290,7 +378,7 @@ func TestTodoLisrPostgres_GetById(t *testing.T) {
 			mockBehavior: func(userId, listId int) {
 				rows := sqlmock.NewRows([]string{"id", "title", "description"})
 
-				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description FROM").
+				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM").
 					WithArgs(userId, listId).WillReturnRows(rows)
 			},
 			input: args{
@@ -302,7 +390,7 @@ func TestTodoLisrPostgres_GetById(t *testing.T) {
 		{
 			name: "Error Select",
 			mockBehavior: func(userId, listId int) {
-				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description FROM").
+				mock.ExpectQuery("SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM").
 					WithArgs(userId, listId).WillReturnError(errors.New("Error SELECT"))
 			},
 			input: args{
@@ -526,3 +614,207 @@ func TestTodoListPostgres_Update(t *testing.T) {
 		})
 	}
 }
+
+func TestTodoListPostgres_Move(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTodoListPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "position"}).AddRow(1, "F").AddRow(2, "V").AddRow(3, "k")
+				mock.ExpectQuery("SELECT list_id AS id, position FROM user_lists (.+) FOR UPDATE").WithArgs(7).WillReturnRows(rows)
+
+				mock.ExpectExec("UPDATE user_lists t SET position = v.position FROM unnest").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Renumber Equal Positions",
+			mock: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "position"}).AddRow(1, "V").AddRow(2, "V").AddRow(3, "V")
+				mock.ExpectQuery("SELECT list_id AS id, position FROM user_lists").WithArgs(7).WillReturnRows(rows)
+
+				mock.ExpectExec("UPDATE user_lists t SET position = v.position FROM unnest").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7).WillReturnResult(sqlmock.NewResult(0, 3))
+
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Update Error",
+			mock: func() {
+				mock.ExpectBegin()
+
+				rows := sqlmock.NewRows([]string{"id", "position"}).AddRow(1, "F").AddRow(2, "V").AddRow(3, "k")
+				mock.ExpectQuery("SELECT list_id AS id, position FROM user_lists").WithArgs(7).WillReturnRows(rows)
+
+				mock.ExpectExec("UPDATE user_lists t SET position").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7).WillReturnError(errors.New("some error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			err := r.Move(7, 3, todo.MoveInput{AfterId: intPointer(1)})
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoListPostgres_GetRole(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTodoListPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    todo.ListRole
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("SELECT role FROM user_lists").WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("editor"))
+			},
+			want: todo.ListRoleEditor,
+		},
+		{
+			name: "Not Member",
+			mock: func() {
+				mock.ExpectQuery("SELECT role FROM user_lists").WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"role"}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.GetRole(1, 2)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoListPostgres_AddMember(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTodoListPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+				mock.ExpectQuery("SELECT COALESCE(.+) FROM user_lists").WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("V"))
+
+				mock.ExpectExec("INSERT INTO user_lists (.+) ON CONFLICT \\(user_id, list_id\\) DO UPDATE SET role").
+					WithArgs(7, 3, "k", todo.ListRoleViewer).WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectCommit()
+			},
+			want: 7,
+		},
+		{
+			name: "Unknown User",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.AddMember(3, "bob", todo.ListRoleViewer)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTodoListPostgres_GetCoMemberIds(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTodoListPostgres(db)
+
+	mock.ExpectQuery("SELECT (.+) UNION SELECT DISTINCT other.user_id FROM user_lists ul").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(4))
+
+	got, err := r.GetCoMemberIds(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 4}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
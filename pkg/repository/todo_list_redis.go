@@ -22,7 +22,8 @@ func NewTodoListRedis(context *gin.Context, redisClient *redis.Client) *TodoList
 	}
 }
 
-// Если listId использовать не нужно, передать -1
+// Если listId использовать не нужно, передать -1.
+// В поле lists хранится только полная выборка в порядке по умолчанию, выборки с параметрами не кэшируются
 func (r *TodoListRedis) HGet(userId, listId int) (string, error) {
 	if listId < 0 {
 		val, err := r.redisClient.HGet(r.context, fmt.Sprintf("user:%d", userId), "lists").Result() // Запрос значения из кэша по ключу userId и полю lists
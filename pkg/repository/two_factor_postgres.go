@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type TwoFactorPostgres struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactorPostgres(db *sqlx.DB) *TwoFactorPostgres {
+	return &TwoFactorPostgres{db: db}
+}
+
+func (r *TwoFactorPostgres) Get(userId int) (todo.TwoFactor, error) {
+	var tf todo.TwoFactor
+	query := fmt.Sprintf("SELECT username, totp_secret, totp_enabled, totp_last_step FROM %s WHERE id = $1", usersTable)
+	err := r.db.Get(&tf, query, userId)
+
+	return tf, err
+}
+
+// Новый секрет до подтверждения первым кодом. Если 2FA уже включена - sql.ErrNoRows
+func (r *TwoFactorPostgres) SetPendingSecret(userId int, secret string) error {
+	var id int
+	query := fmt.Sprintf("UPDATE %s SET totp_secret = $1 WHERE id = $2 AND NOT totp_enabled RETURNING id", usersTable)
+
+	return r.db.QueryRow(query, secret, userId).Scan(&id)
+}
+
+// Включение 2FA с новыми кодами восстановления (старые удаляются)
+func (r *TwoFactorPostgres) Enable(userId int, step int64, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	enableQuery := fmt.Sprintf("UPDATE %s SET totp_enabled = true, totp_last_step = $1 WHERE id = $2", usersTable)
+	if _, err := tx.Exec(enableQuery, step, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := replaceRecoveryCodes(tx, userId, codeHashes); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TwoFactorPostgres) Disable(userId int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	disableQuery := fmt.Sprintf("UPDATE %s SET totp_secret = NULL, totp_enabled = false, totp_last_step = 0 WHERE id = $1",
+		usersTable)
+	if _, err := tx.Exec(disableQuery, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := replaceRecoveryCodes(tx, userId, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Принятие кода шага step. false - код этого или более позднего шага уже использован (повтор)
+func (r *TwoFactorPostgres) UseStep(userId int, step int64) (bool, error) {
+	query := fmt.Sprintf("UPDATE %s SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1", usersTable)
+	res, err := r.db.Exec(query, step, userId)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Одноразовое использование кода восстановления. Если кода нет или он использован - sql.ErrNoRows
+func (r *TwoFactorPostgres) UseRecoveryCode(userId int, codeHash string) error {
+	var id int
+	query := fmt.Sprintf(`UPDATE %s SET used_at = now() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+							RETURNING id`, recoveryCodesTable)
+
+	return r.db.QueryRow(query, userId, codeHash).Scan(&id)
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, userId int, codeHashes []string) error {
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", recoveryCodesTable)
+	if _, err := tx.Exec(deleteQuery, userId); err != nil {
+		return err
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	insertQuery := fmt.Sprintf("INSERT INTO %s (user_id, code_hash) SELECT $1, unnest($2::text[])", recoveryCodesTable)
+	_, err := tx.Exec(insertQuery, userId, pq.Array(codeHashes))
+
+	return err
+}
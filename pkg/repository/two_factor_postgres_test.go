@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestTwoFactorPostgres_Enable(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTwoFactorPostgres(db)
+
+	hashes := []string{"hash1", "hash2"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE users SET totp_enabled = true").WithArgs(int64(55), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM recovery_codes WHERE user_id").WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO recovery_codes (.+) unnest").WithArgs(1, pq.Array(hashes)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	assert.NoError(t, r.Enable(1, 55, hashes))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTwoFactorPostgres_UseStep(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTwoFactorPostgres(db)
+
+	testTable := []struct {
+		name     string
+		affected int64
+		want     bool
+	}{
+		{name: "New Step", affected: 1, want: true},
+		{name: "Replayed Step", affected: 0, want: false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock.ExpectExec("UPDATE users SET totp_last_step (.+) AND totp_last_step <").WithArgs(int64(55), 1).
+				WillReturnResult(sqlmock.NewResult(0, testCase.affected))
+
+			got, err := r.UseStep(1, 55)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTwoFactorPostgres_UseRecoveryCode(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewTwoFactorPostgres(db)
+
+	mock.ExpectQuery("UPDATE recovery_codes SET used_at (.+) AND used_at IS NULL RETURNING id").WithArgs(1, "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery("UPDATE recovery_codes SET used_at (.+) AND used_at IS NULL RETURNING id").WithArgs(1, "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	assert.NoError(t, r.UseRecoveryCode(1, "hash"))
+	assert.Error(t, r.UseRecoveryCode(1, "hash")) // код уже использован
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"fmt"
+	"todo-app"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WorkspacesPostgres struct {
+	db *sqlx.DB
+}
+
+func NewWorkspacesPostgres(db *sqlx.DB) *WorkspacesPostgres {
+	return &WorkspacesPostgres{db: db}
+}
+
+// Создатель пространства становится его владельцем
+func (r *WorkspacesPostgres) Create(userId int, workspace todo.Workspace) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	createQuery := fmt.Sprintf("INSERT INTO %s (name) VALUES ($1) RETURNING id", workspacesTable)
+	if err := tx.QueryRow(createQuery, workspace.Name).Scan(&id); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	memberQuery := fmt.Sprintf("INSERT INTO %s (workspace_id, user_id, role) VALUES ($1, $2, '%s')", workspaceMembersTable, todo.ListRoleOwner)
+	if _, err := tx.Exec(memberQuery, id, userId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *WorkspacesPostgres) GetAll(userId int) ([]todo.Workspace, error) {
+	var workspaces []todo.Workspace
+	query := fmt.Sprintf(`SELECT w.id, w.name, w.personal_user_id IS NOT NULL AS personal, wm.role FROM %s w
+									INNER JOIN %s wm on wm.workspace_id = w.id WHERE wm.user_id = $1 ORDER BY personal DESC, w.id`,
+		workspacesTable, workspaceMembersTable)
+	err := r.db.Select(&workspaces, query, userId)
+
+	return workspaces, err
+}
+
+// Пространство вместе с ролью пользователя. Если пользователь не участник - sql.ErrNoRows
+func (r *WorkspacesPostgres) GetById(userId, workspaceId int) (todo.Workspace, error) {
+	var workspace todo.Workspace
+	query := fmt.Sprintf(`SELECT w.id, w.name, w.personal_user_id IS NOT NULL AS personal, wm.role FROM %s w
+									INNER JOIN %s wm on wm.workspace_id = w.id WHERE wm.user_id = $1 AND w.id = $2`,
+		workspacesTable, workspaceMembersTable)
+	err := r.db.Get(&workspace, query, userId, workspaceId)
+
+	return workspace, err
+}
+
+// Списки пространства, доступные пользователю, в его порядке сортировки
+func (r *WorkspacesPostgres) GetLists(userId, workspaceId int) ([]todo.TodoList, error) {
+	var lists []todo.TodoList
+	query := fmt.Sprintf(`SELECT tl.id, tl.title, tl.description, ul.position, ul.role, tl.workspace_id FROM %s tl
+									INNER JOIN %s ul on tl.id = ul.list_id WHERE ul.user_id = $1 AND tl.workspace_id = $2 ORDER BY ul.position, tl.id`,
+		todoListsTable, usersListsTable)
+	err := r.db.Select(&lists, query, userId, workspaceId)
+
+	return lists, err
+}
+
+func (r *WorkspacesPostgres) GetMembers(workspaceId int) ([]todo.ListMember, error) {
+	var members []todo.ListMember
+	query := fmt.Sprintf(`SELECT u.id AS user_id, u.name, u.username, wm.role FROM %s wm INNER JOIN %s u on u.id = wm.user_id
+									WHERE wm.workspace_id = $1 ORDER BY wm.id`, workspaceMembersTable, usersTable)
+	err := r.db.Select(&members, query, workspaceId)
+
+	return members, err
+}
+
+// Добавление участника по username или изменение его роли. Участник получает доступ ко всем спискам
+// пространства с ролью в пространстве, явно выданный доступ к спискам не меняется. Возвращает id участника
+func (r *WorkspacesPostgres) AddMember(workspaceId int, username string, role todo.ListRole) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var memberId int
+	userQuery := fmt.Sprintf("SELECT id FROM %s WHERE username = $1", usersTable)
+	if err := tx.Get(&memberId, userQuery, username); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	addQuery := fmt.Sprintf(`INSERT INTO %s (workspace_id, user_id, role) VALUES ($1, $2, $3)
+									ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role`, workspaceMembersTable)
+	if _, err := tx.Exec(addQuery, workspaceId, memberId, role); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	// При смене роли в пространстве меняется и роль в унаследованных списках
+	roleQuery := fmt.Sprintf(`UPDATE %s ul SET role = $1 FROM %s tl
+									WHERE tl.id = ul.list_id AND tl.workspace_id = $2 AND ul.user_id = $3 AND ul.inherited`,
+		usersListsTable, todoListsTable)
+	if _, err := tx.Exec(roleQuery, role, workspaceId, memberId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	listIds := make([]int, 0)
+	listsQuery := fmt.Sprintf("SELECT id FROM %s WHERE workspace_id = $1 ORDER BY id", todoListsTable)
+	if err := tx.Select(&listIds, listsQuery, workspaceId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := inheritLists(tx, workspaceId, []int{memberId}, listIds); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return memberId, tx.Commit()
+}
+
+// Исключение участника или выход из пространства: вместе с участием снимается унаследованный доступ к спискам.
+// Возвращает id исключенного пользователя
+func (r *WorkspacesPostgres) RemoveMember(workspaceId int, username string) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	var memberId int
+	removeQuery := fmt.Sprintf(`DELETE FROM %s wm USING %s u WHERE u.id = wm.user_id AND wm.workspace_id = $1 AND u.username = $2
+									RETURNING wm.user_id`, workspaceMembersTable, usersTable)
+	if err := tx.QueryRow(removeQuery, workspaceId, username).Scan(&memberId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	listsQuery := fmt.Sprintf(`DELETE FROM %s ul USING %s tl
+									WHERE tl.id = ul.list_id AND tl.workspace_id = $1 AND ul.user_id = $2 AND ul.inherited`,
+		usersListsTable, todoListsTable)
+	if _, err := tx.Exec(listsQuery, workspaceId, memberId); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return memberId, tx.Commit()
+}
+
+// Выдача участникам пространства userIds унаследованного доступа к спискам listIds с их ролью в пространстве.
+// Списки добавляются в конец списков каждого пользователя, уже имеющийся доступ не меняется
+func inheritLists(tx *sqlx.Tx, workspaceId int, userIds, listIds []int) error {
+	if len(userIds) == 0 || len(listIds) == 0 {
+		return nil
+	}
+
+	var last []rankedRow
+	lastQuery := fmt.Sprintf(`SELECT u.id, COALESCE(max(ul.position), '') AS position FROM unnest($1::int[]) AS u(id)
+									LEFT JOIN %s ul on ul.user_id = u.id GROUP BY u.id`, usersListsTable)
+	if err := tx.Select(&last, lastQuery, pq.Array(userIds)); err != nil {
+		return err
+	}
+
+	users := make([]int, 0, len(last)*len(listIds))
+	lists := make([]int, 0, len(last)*len(listIds))
+	positions := make([]string, 0, len(last)*len(listIds))
+	for _, row := range last {
+		position := row.Position
+		for _, listId := range listIds {
+			position = nextPosition(position)
+			users = append(users, row.Id)
+			lists = append(lists, listId)
+			positions = append(positions, position)
+		}
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (user_id, list_id, position, role, inherited)
+									SELECT p.user_id, p.list_id, p.position, wm.role, true
+									FROM unnest($1::int[], $2::int[], $3::text[]) AS p(user_id, list_id, position)
+									INNER JOIN %s wm on wm.user_id = p.user_id AND wm.workspace_id = $4
+									ON CONFLICT (user_id, list_id) DO NOTHING`, usersListsTable, workspaceMembersTable)
+	_, err := tx.Exec(query, pq.Array(users), pq.Array(lists), pq.Array(positions), workspaceId)
+
+	return err
+}
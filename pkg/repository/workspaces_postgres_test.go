@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"todo-app"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+)
+
+func TestWorkspacesPostgres_Create(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewWorkspacesPostgres(db)
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("INSERT INTO workspaces").WithArgs("Team").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	mock.ExpectExec("INSERT INTO workspace_members").WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	got, err := r.Create(1, todo.Workspace{Name: "Team"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspacesPostgres_AddMember(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewWorkspacesPostgres(db)
+
+	testTable := []struct {
+		name    string
+		mock    func()
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				mock.ExpectExec("INSERT INTO workspace_members (.+) ON CONFLICT").WithArgs(2, 5, todo.ListRoleEditor).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("UPDATE user_lists ul SET role (.+) AND ul.inherited").WithArgs(todo.ListRoleEditor, 2, 5).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectQuery("SELECT id FROM todo_lists WHERE workspace_id").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10).AddRow(11))
+
+				mock.ExpectQuery("SELECT u.id, COALESCE(.+) FROM unnest").WithArgs(pq.Array([]int{5})).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "position"}).AddRow(5, "V"))
+
+				mock.ExpectExec("INSERT INTO user_lists (.+) ON CONFLICT \\(user_id, list_id\\) DO NOTHING").
+					WithArgs(pq.Array([]int{5, 5}), pq.Array([]int{10, 11}), pq.Array([]string{"k", "s"}), 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+
+				mock.ExpectCommit()
+			},
+			want: 5,
+		},
+		{
+			name: "No Lists",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				mock.ExpectExec("INSERT INTO workspace_members (.+) ON CONFLICT").WithArgs(2, 5, todo.ListRoleEditor).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mock.ExpectExec("UPDATE user_lists ul SET role").WithArgs(todo.ListRoleEditor, 2, 5).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectQuery("SELECT id FROM todo_lists WHERE workspace_id").WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectCommit()
+			},
+			want: 5,
+		},
+		{
+			name: "Unknown User",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error Insert",
+			mock: func() {
+				mock.ExpectBegin()
+
+				mock.ExpectQuery("SELECT id FROM users WHERE username").WithArgs("bob").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+				mock.ExpectExec("INSERT INTO workspace_members (.+) ON CONFLICT").WithArgs(2, 5, todo.ListRoleEditor).
+					WillReturnError(errors.New("insert error"))
+
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.mock()
+
+			got, err := r.AddMember(2, "bob", todo.ListRoleEditor)
+			if testCase.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWorkspacesPostgres_RemoveMember(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewWorkspacesPostgres(db)
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("DELETE FROM workspace_members wm USING users u (.+) RETURNING wm.user_id").WithArgs(2, "bob").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(5))
+
+	mock.ExpectExec("DELETE FROM user_lists ul USING todo_lists tl (.+) AND ul.inherited").WithArgs(2, 5).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	mock.ExpectCommit()
+
+	got, err := r.RemoveMember(2, "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWorkspacesPostgres_GetLists(t *testing.T) {
+	db, mock, err := sqlmock.Newx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	r := NewWorkspacesPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "position", "role", "workspace_id"}).
+		AddRow(1, "title1", "description1", "V", "owner", 2).
+		AddRow(3, "title3", "description3", "k", "viewer", 2)
+	mock.ExpectQuery("SELECT (.+) FROM todo_lists tl INNER JOIN user_lists ul (.+) WHERE ul.user_id = (.+) AND tl.workspace_id").
+		WithArgs(1, 2).WillReturnRows(rows)
+
+	got, err := r.GetLists(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.TodoList{
+		{Id: 1, Title: "title1", Description: "description1", Position: "V", Role: todo.ListRoleOwner, WorkspaceId: 2},
+		{Id: 3, Title: "title3", Description: "description3", Position: "k", Role: todo.ListRoleViewer, WorkspaceId: 2},
+	}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+var ErrInvalidAccessToken = errors.New("invalid access token")
+
+type AccessTokensService struct {
+	repo repository.AccessTokens
+}
+
+func NewAccessTokensService(repo repository.AccessTokens) *AccessTokensService {
+	return &AccessTokensService{repo: repo}
+}
+
+// Возвращает id и сам токен. Токен показывается один раз: в БД хранится только его хэш
+func (s *AccessTokensService) Create(userId int, input todo.CreateAccessTokenInput) (int, string, error) {
+	secret, err := generateSecretToken()
+	if err != nil {
+		return 0, "", err
+	}
+	token := todo.AccessTokenPrefix + secret
+
+	var expiresAt *time.Time
+	if ttl := input.TTL(); ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+
+	id, err := s.repo.Create(userId, input.Name, input.Scopes, hashSecretToken(token), expiresAt)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return id, token, nil
+}
+
+func (s *AccessTokensService) GetAll(userId int) ([]todo.AccessToken, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *AccessTokensService) Revoke(userId, tokenId int) error {
+	err := s.repo.Delete(userId, tokenId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("access token not found")
+	}
+	return err
+}
+
+// Проверка персонального токена, возвращает id пользователя и области доступа токена
+func (s *AccessTokensService) Authenticate(token string) (int, []string, error) {
+	if !strings.HasPrefix(token, todo.AccessTokenPrefix) {
+		return 0, nil, ErrInvalidAccessToken
+	}
+
+	accessToken, err := s.repo.GetByHash(hashSecretToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return accessToken.UserId, accessToken.Scopes, nil
+}
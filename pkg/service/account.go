@@ -0,0 +1,164 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+var (
+	ErrUsernameTaken = errors.New("username is already taken")
+	ErrEmailTaken    = errors.New("email is already taken")
+)
+
+type AccountService struct {
+	auth         *AuthService
+	repo         repository.Authorization
+	listRepo     repository.TodoList
+	sessionsRepo repository.Sessions
+	revoked      repository.SessionsCach
+	tokensRepo   repository.AccessTokens
+	cfg          ConfigAuth
+}
+
+func NewAccountService(auth *AuthService, repo repository.Authorization, listRepo repository.TodoList,
+	sessionsRepo repository.Sessions, revoked repository.SessionsCach, tokensRepo repository.AccessTokens,
+	cfg ConfigAuth) *AccountService {
+	return &AccountService{
+		auth:         auth,
+		repo:         repo,
+		listRepo:     listRepo,
+		sessionsRepo: sessionsRepo,
+		revoked:      revoked,
+		tokensRepo:   tokensRepo,
+		cfg:          cfg,
+	}
+}
+
+func (s *AccountService) Get(userId int) (todo.Profile, error) {
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return todo.Profile{}, err
+	}
+
+	return todo.Profile{
+		Id:            user.Id,
+		Name:          user.Name,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+	}, nil
+}
+
+// Изменение имени, username или почты. Занятые username и адрес не принимаются
+func (s *AccountService) Update(userId int, input todo.UpdateUserInput) (todo.Profile, error) {
+	if err := input.Validate(); err != nil {
+		return todo.Profile{}, err
+	}
+
+	if input.Username != nil {
+		username := strings.TrimSpace(*input.Username)
+		input.Username = &username
+		user, err := s.repo.GetUser(username)
+		if err == nil && user.Id != userId {
+			return todo.Profile{}, ErrUsernameTaken
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return todo.Profile{}, err
+		}
+	}
+
+	if input.Email != nil {
+		email := normalizeEmail(*input.Email)
+		input.Email = &email
+		if email != "" {
+			user, err := s.repo.GetUserByEmail(email)
+			if err == nil && user.Id != userId {
+				return todo.Profile{}, ErrEmailTaken
+			}
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return todo.Profile{}, err
+			}
+		}
+	}
+
+	if err := s.repo.UpdateUser(userId, input); err != nil {
+		return todo.Profile{}, err
+	}
+
+	return s.Get(userId)
+}
+
+// Смена пароля с проверкой текущего. Все сессии, кроме sessionId, и персональные токены доступа отзываются
+func (s *AccountService) ChangePassword(userId, sessionId int, input todo.ChangePasswordInput) error {
+	if _, err := s.checkPassword(userId, input.CurrentPassword); err != nil {
+		return err
+	}
+
+	hash, err := generatePasswordHash(input.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePasswordHash(userId, hash); err != nil {
+		return err
+	}
+
+	if err := s.tokensRepo.DeleteAll(userId); err != nil {
+		return err
+	}
+
+	sessionIds, err := s.sessionsRepo.RevokeAll(userId, sessionId)
+	if err != nil {
+		return err
+	}
+	return s.revoked.Revoke(sessionIds, s.cfg.AccessTTL)
+}
+
+// Удаление пользователя. Пароль не проверяется только у пользователей без пароля (вход через OIDC).
+// Возвращает id пользователей, с которыми у удаленного были общие списки (и его самого),
+// их кэш нужно сбросить
+func (s *AccountService) Delete(userId int, password string) ([]int, error) {
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := s.repo.GetUser(user.Username)
+	if err != nil {
+		return nil, err
+	}
+	if credentials.Password != "" {
+		if _, err := s.auth.checkPassword(user.Username, password); err != nil {
+			return nil, err
+		}
+	}
+
+	// Участников общих списков запоминаем до удаления, после него связь уже не найти
+	ids, err := s.listRepo.GetCoMemberIds(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIds, err := s.sessionsRepo.RevokeAll(userId, -1)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revoked.Revoke(sessionIds, s.cfg.AccessTTL); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteUser(userId); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (s *AccountService) checkPassword(userId int, password string) (todo.User, error) {
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return todo.User{}, err
+	}
+	return s.auth.checkPassword(user.Username, password)
+}
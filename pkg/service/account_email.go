@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"todo-app"
+	"todo-app/pkg/mailer"
+	"todo-app/pkg/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	verifyTokenTTL = 24 * time.Hour
+	resetTokenTTL  = time.Hour
+	mailTimeout    = 10 * time.Second
+)
+
+var (
+	// Неизвестный, истекший или уже использованный токен из письма
+	ErrInvalidEmailToken = errors.New("invalid or expired token")
+	ErrEmailNotSet       = errors.New("email is not set")
+)
+
+type ConfigMail struct {
+	Mailer    mailer.Mailer
+	Templates *mailer.Templates
+	BaseURL   string // адрес API для ссылок в письмах, например https://todo.example.com
+	// Страница клиента с формой нового пароля, отправляющая POST /auth/reset-password.
+	// По умолчанию BaseURL + "/reset-password"
+	ResetURL string
+}
+
+// Данные шаблонов писем
+type emailData struct {
+	Name      string
+	Username  string
+	Link      string
+	Token     string
+	ExpiresIn string
+}
+
+type AccountEmailService struct {
+	repo         repository.Authorization
+	tokensRepo   repository.EmailTokens
+	sessionsRepo repository.Sessions
+	revoked      repository.SessionsCach
+	mail         ConfigMail
+	authCfg      ConfigAuth
+}
+
+func NewAccountEmailService(repo repository.Authorization, tokensRepo repository.EmailTokens, sessionsRepo repository.Sessions,
+	revoked repository.SessionsCach, mail ConfigMail, authCfg ConfigAuth) *AccountEmailService {
+	mail.BaseURL = strings.TrimRight(mail.BaseURL, "/")
+	if mail.ResetURL == "" {
+		mail.ResetURL = mail.BaseURL + "/reset-password"
+	}
+	return &AccountEmailService{
+		repo:         repo,
+		tokensRepo:   tokensRepo,
+		sessionsRepo: sessionsRepo,
+		revoked:      revoked,
+		mail:         mail,
+		authCfg:      authCfg.withDefaults(),
+	}
+}
+
+// Письмо со ссылкой подтверждения на текущий адрес пользователя
+func (s *AccountEmailService) SendVerification(userId int) error {
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		return ErrEmailNotSet
+	}
+
+	return s.sendToken(user, todo.EmailTokenVerify, "verify", s.mail.BaseURL+"/auth/verify", verifyTokenTTL, "24 часа")
+}
+
+// Подтверждение почты по токену из письма. Токен действует только для адреса, на который отправлен
+func (s *AccountEmailService) Verify(token string) error {
+	userId, email, err := s.tokensRepo.Use(todo.EmailTokenVerify, hashSecretToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidEmailToken
+		}
+		return err
+	}
+
+	if err := s.repo.SetEmailVerified(userId, email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) { // адрес изменился после отправки письма
+			return ErrInvalidEmailToken
+		}
+		return err
+	}
+	return nil
+}
+
+// Письмо со ссылкой сброса пароля, только на подтвержденный адрес: иначе ссылку получил бы владелец
+// ошибочно указанного адреса. Для неизвестного или неподтвержденного адреса ошибка не возвращается,
+// чтобы по ответу нельзя было узнать, зарегистрирован ли адрес
+func (s *AccountEmailService) ForgotPassword(email string) error {
+	user, err := s.repo.GetUserByEmail(normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if !user.EmailVerified {
+		return nil
+	}
+
+	if err := s.sendReset(user); err != nil {
+		logrus.Errorf("failed to send password reset email to user %d: %s", user.Id, err.Error())
+	}
+	return nil
+}
+
+// Новый пароль по токену из письма. Все сессии пользователя отзываются
+func (s *AccountEmailService) ResetPassword(token, password string) error {
+	userId, _, err := s.tokensRepo.Use(todo.EmailTokenReset, hashSecretToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidEmailToken
+		}
+		return err
+	}
+
+	hash, err := generatePasswordHash(password)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePasswordHash(userId, hash); err != nil {
+		return err
+	}
+
+	sessionIds, err := s.sessionsRepo.RevokeAll(userId, -1)
+	if err != nil {
+		return err
+	}
+	return s.revoked.Revoke(sessionIds, s.authCfg.AccessTTL)
+}
+
+func (s *AccountEmailService) sendReset(user todo.User) error {
+	return s.sendToken(user, todo.EmailTokenReset, "reset", s.mail.ResetURL, resetTokenTTL, "1 час")
+}
+
+// Создание токена и отправка письма template со ссылкой link?token=...
+func (s *AccountEmailService) sendToken(user todo.User, purpose, template, link string, ttl time.Duration, expiresIn string) error {
+	token, err := generateSecretToken()
+	if err != nil {
+		return err
+	}
+
+	email := normalizeEmail(user.Email)
+	if err := s.tokensRepo.Create(user.Id, purpose, email, hashSecretToken(token), time.Now().Add(ttl)); err != nil {
+		return err
+	}
+
+	msg, err := s.mail.Templates.Render(template, email, emailData{
+		Name:      user.Name,
+		Username:  user.Username,
+		Link:      link + "?token=" + url.QueryEscape(token),
+		Token:     token,
+		ExpiresIn: expiresIn,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+	defer cancel()
+	return s.mail.Mailer.Send(ctx, msg)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+	"todo-app"
+	"todo-app/pkg/mailer"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Токены из писем в памяти, ключ - хэш токена
+type emailTokensStub map[string]*emailTokenStub
+
+type emailTokenStub struct {
+	userId         int
+	purpose, email string
+	expiresAt      time.Time
+	used           bool
+}
+
+func (r emailTokensStub) Create(userId int, purpose, email, tokenHash string, expiresAt time.Time) error {
+	for _, token := range r {
+		if token.userId == userId && token.purpose == purpose {
+			token.used = true
+		}
+	}
+	r[tokenHash] = &emailTokenStub{userId: userId, purpose: purpose, email: email, expiresAt: expiresAt}
+	return nil
+}
+
+func (r emailTokensStub) Use(purpose, tokenHash string) (int, string, error) {
+	token, ok := r[tokenHash]
+	if !ok || token.used || token.purpose != purpose || time.Now().After(token.expiresAt) {
+		return 0, "", sql.ErrNoRows
+	}
+	token.used = true
+	return token.userId, token.email, nil
+}
+
+// Отправленные письма запоминаются
+type mailerStub []mailer.Message
+
+func (m *mailerStub) Send(ctx context.Context, msg mailer.Message) error {
+	*m = append(*m, msg)
+	return nil
+}
+
+func newTestAccountEmail(t *testing.T, repo *authRepoStub, sessions *sessionsRepoStub, revoked sessionsCachStub) (*AccountEmailService, *mailerStub) {
+	templates, err := mailer.NewTemplates(fstest.MapFS{
+		"verify.txt": {Data: []byte(`{{define "subject"}}verify{{end}}{{define "text"}}{{.Link}}{{end}}`)},
+		"reset.txt":  {Data: []byte(`{{define "subject"}}reset{{end}}{{define "text"}}{{.Link}}{{end}}`)},
+	})
+	if err != nil {
+		t.Fatalf("failed to parse templates: %s", err.Error())
+	}
+
+	sent := &mailerStub{}
+	s := NewAccountEmailService(repo, emailTokensStub{}, sessions, revoked, ConfigMail{
+		Mailer:    sent,
+		Templates: templates,
+		BaseURL:   "http://todo.test/",
+	}, ConfigAuth{})
+	return s, sent
+}
+
+// Токен из ссылки в письме
+func tokenFromMail(t *testing.T, msg mailer.Message) string {
+	link, err := url.Parse(strings.TrimSpace(msg.Text))
+	if err != nil {
+		t.Fatalf("invalid link in mail: %s", err.Error())
+	}
+	return link.Query().Get("token")
+}
+
+func TestAccountEmailService_Verify(t *testing.T) {
+	repo := &authRepoStub{
+		users:    map[string]todo.User{"alex": {Id: 7, Name: "Alex", Username: "alex", Email: "Alex@Example.com"}},
+		updated:  map[int]string{},
+		verified: map[int]string{},
+	}
+	s, sent := newTestAccountEmail(t, repo, nil, nil)
+
+	assert.NoError(t, s.SendVerification(7))
+	assert.NoError(t, s.SendVerification(7)) // повторное письмо аннулирует первую ссылку
+	assert.Len(t, *sent, 2)
+	assert.Equal(t, "alex@example.com", (*sent)[1].To)
+	assert.True(t, strings.HasPrefix((*sent)[1].Text, "http://todo.test/auth/verify?token="))
+
+	assert.ErrorIs(t, s.Verify(tokenFromMail(t, (*sent)[0])), ErrInvalidEmailToken)
+
+	token := tokenFromMail(t, (*sent)[1])
+	assert.NoError(t, s.Verify(token))
+	assert.Equal(t, "alex@example.com", repo.verified[7])
+
+	assert.ErrorIs(t, s.Verify(token), ErrInvalidEmailToken) // токен одноразовый
+}
+
+func TestAccountEmailService_SendVerification_NoEmail(t *testing.T) {
+	repo := &authRepoStub{users: map[string]todo.User{"alex": {Id: 7, Username: "alex"}}}
+	s, sent := newTestAccountEmail(t, repo, nil, nil)
+
+	assert.ErrorIs(t, s.SendVerification(7), ErrEmailNotSet)
+	assert.Empty(t, *sent)
+}
+
+func TestAccountEmailService_ResetPassword(t *testing.T) {
+	repo := &authRepoStub{
+		users: map[string]todo.User{
+			"alex": {Id: 7, Name: "Alex", Username: "alex", Email: "alex@example.com", EmailVerified: true},
+			"bob":  {Id: 9, Username: "bob", Email: "bob@example.com"},
+		},
+		updated: map[int]string{},
+	}
+	sessions := &sessionsRepoStub{userId: 7, sessionId: 3}
+	revoked := sessionsCachStub{}
+	s, sent := newTestAccountEmail(t, repo, sessions, revoked)
+
+	// Для неизвестного адреса ответ тот же, но письмо не отправляется
+	assert.NoError(t, s.ForgotPassword("nobody@example.com"))
+	assert.Empty(t, *sent)
+
+	// На неподтвержденный адрес ссылка не отправляется
+	assert.NoError(t, s.ForgotPassword("bob@example.com"))
+	assert.Empty(t, *sent)
+
+	assert.NoError(t, s.ForgotPassword(" ALEX@example.com "))
+	assert.Len(t, *sent, 1)
+	assert.True(t, strings.HasPrefix((*sent)[0].Text, "http://todo.test/reset-password?token="))
+
+	token := tokenFromMail(t, (*sent)[0])
+	assert.ErrorIs(t, s.ResetPassword("wrong", "new-password"), ErrInvalidEmailToken)
+	assert.NoError(t, s.ResetPassword(token, "new-password"))
+
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(repo.updated[7]), []byte("new-password")))
+	assert.Equal(t, []int{3, 4}, sessions.revoked) // все сессии
+	assert.True(t, revoked[3] && revoked[4])
+
+	assert.ErrorIs(t, s.ResetPassword(token, "other-password"), ErrInvalidEmailToken)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"testing"
+	"todo-app"
+	"todo-app/pkg/repository"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Участники общих списков удаляемого пользователя
+type coMembersStub struct {
+	repository.TodoList
+	ids []int
+}
+
+func (r coMembersStub) GetCoMemberIds(userId int) ([]int, error) {
+	return r.ids, nil
+}
+
+// Персональные токены доступа: запоминается, чьи токены отозваны
+type accessTokensStub struct {
+	repository.AccessTokens
+	deleted []int
+}
+
+func (r *accessTokensStub) DeleteAll(userId int) error {
+	r.deleted = append(r.deleted, userId)
+	return nil
+}
+
+func newTestAccount(repo *authRepoStub, sessions *sessionsRepoStub, revoked sessionsCachStub) *AccountService {
+	auth := NewAuthService(repo, sessions, nil, revoked, nil, nil, nil, ConfigAuth{})
+	return NewAccountService(auth, repo, coMembersStub{ids: []int{7, 9}}, sessions, revoked, &accessTokensStub{}, ConfigAuth{})
+}
+
+func TestAccountService_Update(t *testing.T) {
+	repo := &authRepoStub{users: map[string]todo.User{
+		"alex": {Id: 7, Name: "Alex", Username: "alex", Email: "alex@example.com", EmailVerified: true},
+		"bob":  {Id: 9, Name: "Bob", Username: "bob", Email: "bob@example.com"},
+	}}
+	s := newTestAccount(repo, nil, nil)
+
+	taken := "bob"
+	_, err := s.Update(7, todo.UpdateUserInput{Username: &taken})
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+
+	takenEmail := " BOB@example.com"
+	_, err = s.Update(7, todo.UpdateUserInput{Email: &takenEmail})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	// Свои username и адрес не считаются занятыми
+	same := "alex"
+	profile, err := s.Update(7, todo.UpdateUserInput{Username: &same})
+	assert.NoError(t, err)
+	assert.True(t, profile.EmailVerified)
+
+	email := " New@Example.com "
+	profile, err = s.Update(7, todo.UpdateUserInput{Email: &email})
+	assert.NoError(t, err)
+	assert.Equal(t, todo.Profile{Id: 7, Name: "Alex", Username: "alex", Email: "new@example.com"}, profile)
+}
+
+func TestAccountService_ChangePassword(t *testing.T) {
+	hash, err := generatePasswordHash("qwerty")
+	assert.NoError(t, err)
+
+	repo := &authRepoStub{
+		users:   map[string]todo.User{"alex": {Id: 7, Username: "alex", Password: hash}},
+		updated: map[int]string{},
+	}
+	sessions := &sessionsRepoStub{userId: 7, sessionId: 3}
+	revoked := sessionsCachStub{}
+	s := newTestAccount(repo, sessions, revoked)
+
+	err = s.ChangePassword(7, 3, todo.ChangePasswordInput{CurrentPassword: "wrong", NewPassword: "secret"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Empty(t, repo.updated)
+
+	assert.NoError(t, s.ChangePassword(7, 3, todo.ChangePasswordInput{CurrentPassword: "qwerty", NewPassword: "secret"}))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(repo.updated[7]), []byte("secret")))
+	assert.Equal(t, []int{4}, sessions.revoked) // текущая сессия остается
+	assert.Equal(t, sessionsCachStub{4: true}, revoked)
+	assert.Equal(t, []int{7}, s.tokensRepo.(*accessTokensStub).deleted)
+}
+
+func TestAccountService_Delete(t *testing.T) {
+	hash, err := generatePasswordHash("qwerty")
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name     string
+		hash     string
+		password string
+		wantErr  error
+	}{
+		{name: "OK", hash: hash, password: "qwerty"},
+		{name: "Wrong Password", hash: hash, password: "wrong", wantErr: ErrInvalidCredentials},
+		{name: "User Without Password", hash: ""},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &authRepoStub{
+				users:   map[string]todo.User{"alex": {Id: 7, Username: "alex", Password: testCase.hash}},
+				updated: map[int]string{},
+			}
+			sessions := &sessionsRepoStub{userId: 7, sessionId: 3}
+			revoked := sessionsCachStub{}
+			s := newTestAccount(repo, sessions, revoked)
+
+			ids, err := s.Delete(7, testCase.password)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				assert.Empty(t, repo.deleted)
+				assert.Empty(t, sessions.revoked)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []int{7, 9}, ids)
+			assert.Equal(t, []int{7}, repo.deleted)
+			assert.Equal(t, sessionsCachStub{3: true, 4: true}, revoked)
+		})
+	}
+}
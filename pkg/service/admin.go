@@ -0,0 +1,189 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"todo-app"
+	"todo-app/pkg/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Хэш, с которым вход по паролю невозможен: пароль сброшен администратором и задается заново по ссылке из письма
+const lockedPasswordHash = "!"
+
+var (
+	ErrAdminSelf        = errors.New("administrator cannot apply this action to own account")
+	ErrImpersonateAdmin = errors.New("cannot impersonate an administrator")
+	ErrNoVerifiedEmail  = errors.New("user has no verified email")
+)
+
+type AdminService struct {
+	auth         *AuthService
+	emails       *AccountEmailService
+	repo         repository.Admin
+	userRepo     repository.Authorization
+	sessionsRepo repository.Sessions
+	revoked      repository.SessionsCach
+	audit        repository.Audit
+	cfg          ConfigAuth
+}
+
+func NewAdminService(auth *AuthService, emails *AccountEmailService, repo repository.Admin, userRepo repository.Authorization,
+	sessionsRepo repository.Sessions, revoked repository.SessionsCach, audit repository.Audit, cfg ConfigAuth) *AdminService {
+	return &AdminService{
+		auth:         auth,
+		emails:       emails,
+		repo:         repo,
+		userRepo:     userRepo,
+		sessionsRepo: sessionsRepo,
+		revoked:      revoked,
+		audit:        audit,
+		cfg:          cfg,
+	}
+}
+
+func (s *AdminService) GetUsers(query todo.AdminUsersQuery) ([]todo.AdminUser, error) {
+	if query.Limit == 0 {
+		query.Limit = todo.AdminUsersDefaultLimit
+	}
+	if query.Limit > todo.AdminUsersMaxLimit {
+		query.Limit = todo.AdminUsersMaxLimit
+	}
+
+	return s.repo.GetUsers(query)
+}
+
+func (s *AdminService) GetUser(userId int) (todo.AdminUser, error) {
+	user, err := s.repo.GetUser(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, ErrUserNotFound
+	}
+
+	return user, err
+}
+
+// Отключение учетной записи: все сессии пользователя отзываются, новые запросы отклоняются
+func (s *AdminService) Disable(adminId, userId int, input todo.AdminActionInput, ip string) error {
+	if adminId == userId {
+		return ErrAdminSelf
+	}
+
+	if err := s.setDisabled(userId, true); err != nil {
+		return err
+	}
+	if err := s.revokeSessions(userId); err != nil {
+		return err
+	}
+
+	return s.writeAudit(adminId, userId, todo.AuditAdminDisable, input.Reason, ip)
+}
+
+func (s *AdminService) Enable(adminId, userId int, input todo.AdminActionInput, ip string) error {
+	if err := s.setDisabled(userId, false); err != nil {
+		return err
+	}
+
+	return s.writeAudit(adminId, userId, todo.AuditAdminEnable, input.Reason, ip)
+}
+
+// Принудительный сброс пароля: старый пароль перестает действовать, сессии отзываются,
+// на адрес пользователя отправляется ссылка для нового пароля.
+// Без подтвержденной почты пароль задать заново нельзя, поэтому сброс не выполняется (ErrNoVerifiedEmail).
+// Возвращает false, если письмо отправить не удалось
+func (s *AdminService) ResetPassword(adminId, userId int, input todo.AdminActionInput, ip string) (bool, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	if user.Email == "" || !user.EmailVerified { // ссылка только на подтвержденный адрес
+		return false, ErrNoVerifiedEmail
+	}
+
+	if err := s.userRepo.UpdatePasswordHash(userId, lockedPasswordHash); err != nil {
+		return false, err
+	}
+	if err := s.revokeSessions(userId); err != nil {
+		return false, err
+	}
+	if err := s.writeAudit(adminId, userId, todo.AuditAdminPasswordReset, input.Reason, ip); err != nil {
+		return false, err
+	}
+
+	if err := s.emails.sendReset(user); err != nil {
+		logrus.Errorf("failed to send password reset email to user %d: %s", userId, err.Error())
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Вход от имени пользователя для поддержки: создается отдельная сессия пользователя без проверки 2FA.
+// Действие записывается в журнал аудита до выдачи токенов
+func (s *AdminService) Impersonate(adminId, userId int, input todo.ImpersonateInput, client todo.SessionClient) (todo.Tokens, error) {
+	if adminId == userId {
+		return todo.Tokens{}, ErrAdminSelf
+	}
+
+	user, err := s.userRepo.GetUserById(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return todo.Tokens{}, ErrUserNotFound
+	}
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if user.IsAdmin {
+		return todo.Tokens{}, ErrImpersonateAdmin
+	}
+	if user.Disabled {
+		return todo.Tokens{}, ErrAccountDisabled
+	}
+
+	if err := s.writeAudit(adminId, userId, todo.AuditAdminImpersonate, input.Reason, client.Ip); err != nil {
+		return todo.Tokens{}, err
+	}
+
+	// Сессия видна пользователю в списке устройств и может быть им завершена
+	client.DeviceName = fmt.Sprintf("support (admin %d)", adminId)
+	return s.auth.createSession(userId, client)
+}
+
+func (s *AdminService) setDisabled(userId int, disabled bool) error {
+	err := s.repo.SetDisabled(userId, disabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+
+	return err
+}
+
+func (s *AdminService) revokeSessions(userId int) error {
+	sessionIds, err := s.sessionsRepo.RevokeAll(userId, -1)
+	if err != nil {
+		return err
+	}
+
+	return s.revoked.Revoke(sessionIds, s.cfg.AccessTTL)
+}
+
+func (s *AdminService) writeAudit(adminId, userId int, action, reason, ip string) error {
+	details, err := json.Marshal(map[string]interface{}{
+		"admin_id": adminId,
+		"reason":   reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Create(todo.AuditRecord{
+		UserId:  &userId,
+		Action:  action,
+		Ip:      ip,
+		Details: details,
+	})
+}
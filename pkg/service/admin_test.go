@@ -0,0 +1,167 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Признак отключения пользователей запоминается
+type adminRepoStub map[int]bool
+
+func (r adminRepoStub) GetUsers(query todo.AdminUsersQuery) ([]todo.AdminUser, error) {
+	return []todo.AdminUser{{Id: query.Limit}}, nil
+}
+
+func (r adminRepoStub) GetUser(userId int) (todo.AdminUser, error) {
+	if _, ok := r[userId]; !ok {
+		return todo.AdminUser{}, sql.ErrNoRows
+	}
+	return todo.AdminUser{Id: userId}, nil
+}
+
+func (r adminRepoStub) SetDisabled(userId int, disabled bool) error {
+	if _, ok := r[userId]; !ok {
+		return sql.ErrNoRows
+	}
+	r[userId] = disabled
+	return nil
+}
+
+func newTestAdmin(t *testing.T) (*AdminService, *authRepoStub, *sessionsRepoStub, sessionsCachStub, *auditStub, *mailerStub) {
+	repo := &authRepoStub{
+		users: map[string]todo.User{
+			"root":  {Id: 1, Username: "root", IsAdmin: true},
+			"alex":  {Id: 7, Username: "alex", Email: "alex@example.com", EmailVerified: true, Password: "hash"},
+			"dave":  {Id: 13, Username: "dave", Email: "dave@example.com", Password: "hash"},
+			"bob":   {Id: 9, Username: "bob", Password: "hash"},
+			"carol": {Id: 11, Username: "carol", Disabled: true},
+		},
+		updated: map[int]string{},
+	}
+	sessions := &sessionsRepoStub{sessionId: 3}
+	revoked := sessionsCachStub{}
+	audit := &auditStub{}
+
+	auth := NewAuthService(repo, sessions, nil, revoked, nil, audit, newTestKeys(t), ConfigAuth{})
+	emails, sent := newTestAccountEmail(t, repo, sessions, revoked)
+	s := NewAdminService(auth, emails, adminRepoStub{1: false, 7: false, 9: false, 11: true, 13: false}, repo, sessions, revoked, audit,
+		ConfigAuth{})
+	return s, repo, sessions, revoked, audit, sent
+}
+
+func TestAdminService_GetUsers(t *testing.T) {
+	s, _, _, _, _, _ := newTestAdmin(t)
+
+	testTable := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "Default Limit", limit: 0, want: todo.AdminUsersDefaultLimit},
+		{name: "Max Limit", limit: 1000, want: todo.AdminUsersMaxLimit},
+		{name: "Limit", limit: 10, want: 10},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			users, err := s.GetUsers(todo.AdminUsersQuery{Limit: testCase.limit})
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, users[0].Id)
+		})
+	}
+
+	_, err := s.GetUser(42)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestAdminService_Disable(t *testing.T) {
+	s, _, sessions, revoked, audit, _ := newTestAdmin(t)
+
+	assert.ErrorIs(t, s.Disable(1, 1, todo.AdminActionInput{}, "192.0.2.1"), ErrAdminSelf)
+	assert.ErrorIs(t, s.Disable(1, 42, todo.AdminActionInput{}, "192.0.2.1"), ErrUserNotFound)
+	assert.Empty(t, *audit)
+
+	assert.NoError(t, s.Disable(1, 7, todo.AdminActionInput{Reason: "spam"}, "192.0.2.1"))
+	assert.Equal(t, []int{3, 4}, sessions.revoked)
+	assert.True(t, revoked[3] && revoked[4])
+	if assert.Len(t, *audit, 1) {
+		record := (*audit)[0]
+		assert.Equal(t, todo.AuditAdminDisable, record.Action)
+		assert.Equal(t, 7, *record.UserId)
+		assert.Equal(t, "192.0.2.1", record.Ip)
+
+		var details map[string]interface{}
+		assert.NoError(t, json.Unmarshal(record.Details, &details))
+		assert.Equal(t, map[string]interface{}{"admin_id": float64(1), "reason": "spam"}, details)
+	}
+
+	assert.NoError(t, s.Enable(1, 7, todo.AdminActionInput{}, "192.0.2.1"))
+	assert.Equal(t, todo.AuditAdminEnable, (*audit)[1].Action)
+}
+
+func TestAdminService_ResetPassword(t *testing.T) {
+	s, repo, sessions, _, audit, sent := newTestAdmin(t)
+
+	emailSent, err := s.ResetPassword(1, 7, todo.AdminActionInput{}, "192.0.2.1")
+	assert.NoError(t, err)
+	assert.True(t, emailSent)
+	assert.Equal(t, lockedPasswordHash, repo.updated[7])
+	assert.Equal(t, []int{3, 4}, sessions.revoked)
+	assert.Equal(t, todo.AuditAdminPasswordReset, (*audit)[0].Action)
+	if assert.Len(t, *sent, 1) {
+		assert.Equal(t, "alex@example.com", (*sent)[0].To)
+	}
+
+	// Без почты или с неподтвержденным адресом пароль и сессии не меняются
+	for _, userId := range []int{9, 13} {
+		_, err = s.ResetPassword(1, userId, todo.AdminActionInput{}, "192.0.2.1")
+		assert.ErrorIs(t, err, ErrNoVerifiedEmail)
+		assert.NotContains(t, repo.updated, userId)
+	}
+	assert.Equal(t, []int{3, 4}, sessions.revoked)
+	assert.Len(t, *audit, 1)
+	assert.Len(t, *sent, 1)
+
+	_, err = s.ResetPassword(1, 42, todo.AdminActionInput{}, "192.0.2.1")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestAdminService_Impersonate(t *testing.T) {
+	testTable := []struct {
+		name    string
+		userId  int
+		wantErr error
+	}{
+		{name: "OK", userId: 7},
+		{name: "Self", userId: 1, wantErr: ErrAdminSelf},
+		{name: "Not Found", userId: 42, wantErr: ErrUserNotFound},
+		{name: "Disabled", userId: 11, wantErr: ErrAccountDisabled},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, _, _, _, audit, _ := newTestAdmin(t)
+
+			tokens, err := s.Impersonate(1, testCase.userId, todo.ImpersonateInput{Reason: "ticket 42"}, todo.SessionClient{Ip: "192.0.2.1"})
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				assert.Empty(t, *audit)
+				return
+			}
+
+			assert.NoError(t, err)
+			userId, sessionId, err := s.auth.ParseToken(tokens.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, []int{7, 3}, []int{userId, sessionId})
+			assert.Equal(t, todo.AuditAdminImpersonate, (*audit)[0].Action)
+		})
+	}
+
+	s, _, _, _, _, _ := newTestAdmin(t)
+	_, err := s.Impersonate(9, 1, todo.ImpersonateInput{Reason: "ticket 42"}, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrImpersonateAdmin)
+}
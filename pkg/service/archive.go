@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+// Архив не прошел проверку: неизвестная версия или нарушены связи внутри архива
+var ErrInvalidArchive = errors.New("invalid archive")
+
+type ArchiveService struct {
+	repo     repository.Archive
+	userRepo repository.Authorization
+}
+
+func NewArchiveService(repo repository.Archive, userRepo repository.Authorization) *ArchiveService {
+	return &ArchiveService{repo: repo, userRepo: userRepo}
+}
+
+func (s *ArchiveService) Export(userId int) (todo.Archive, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return todo.Archive{}, err
+	}
+
+	archive, err := s.repo.Export(userId)
+	if err != nil {
+		return todo.Archive{}, err
+	}
+
+	archive.Version = todo.ArchiveVersion
+	archive.ExportedAt = time.Now().UTC()
+	archive.Profile = todo.ArchiveProfile{
+		Name:     user.Name,
+		Username: user.Username,
+		Email:    user.Email,
+	}
+
+	return archive, nil
+}
+
+// Импорт архива в учетную запись пользователя. Архив проверяется целиком до записи в БД
+func (s *ArchiveService) Import(userId int, archive todo.Archive) (todo.ImportResult, error) {
+	if err := archive.Validate(); err != nil {
+		return todo.ImportResult{}, fmt.Errorf("%w: %s", ErrInvalidArchive, err.Error())
+	}
+
+	return s.repo.Import(userId, archive)
+}
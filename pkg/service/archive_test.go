@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Запоминает импортированный архив
+type archiveRepoStub struct {
+	archive  todo.Archive
+	imported *todo.Archive
+}
+
+func (r *archiveRepoStub) Export(userId int) (todo.Archive, error) {
+	return r.archive, nil
+}
+
+func (r *archiveRepoStub) Import(userId int, archive todo.Archive) (todo.ImportResult, error) {
+	r.imported = &archive
+	return todo.ImportResult{Lists: len(archive.Lists)}, nil
+}
+
+func TestArchiveService_Export(t *testing.T) {
+	users := &authRepoStub{users: map[string]todo.User{"alex": {Id: 7, Name: "Alex", Username: "alex", Email: "alex@example.com"}}}
+	repo := &archiveRepoStub{archive: todo.Archive{Tags: []todo.ArchiveTag{}, Lists: []todo.ArchiveList{{Id: 1, Title: "Home"}}}}
+	s := NewArchiveService(repo, users)
+
+	archive, err := s.Export(7)
+	assert.NoError(t, err)
+	assert.Equal(t, todo.ArchiveVersion, archive.Version)
+	assert.WithinDuration(t, time.Now(), archive.ExportedAt, time.Minute)
+	assert.Equal(t, todo.ArchiveProfile{Name: "Alex", Username: "alex", Email: "alex@example.com"}, archive.Profile)
+	assert.Equal(t, repo.archive.Lists, archive.Lists)
+}
+
+func TestArchiveService_Import(t *testing.T) {
+	due := time.Date(2022, 6, 13, 9, 0, 0, 0, time.UTC)
+	one, two, unknown := 1, 2, 99
+	offset := -5
+
+	item := func(id int, modify func(item *todo.ArchiveItem)) todo.ArchiveItem {
+		item := todo.ArchiveItem{Id: id, Title: "Item"}
+		if modify != nil {
+			modify(&item)
+		}
+		return item
+	}
+	archive := func(items ...todo.ArchiveItem) todo.Archive {
+		return todo.Archive{
+			Version: todo.ArchiveVersion,
+			Tags:    []todo.ArchiveTag{{Id: 5, Name: "work"}},
+			Lists:   []todo.ArchiveList{{Id: 10, Title: "Home", Items: items}},
+		}
+	}
+
+	testTable := []struct {
+		name    string
+		archive todo.Archive
+		wantErr bool
+	}{
+		{
+			name: "OK",
+			archive: archive(
+				item(1, func(i *todo.ArchiveItem) { i.TagIds = []int{5} }),
+				item(2, func(i *todo.ArchiveItem) { i.ParentId = &one }),
+				item(3, func(i *todo.ArchiveItem) { i.DueDate, i.Recurrence = &due, "freq=weekly;byday=mo" }),
+				item(4, func(i *todo.ArchiveItem) { i.DueDate, i.DueAllDay, i.DueTimezone = &due, true, "Asia/Tokyo" }),
+				item(5, func(i *todo.ArchiveItem) { i.DueAllDay, i.DueTimezone = true, "Asia/Tokyo" }),
+			),
+		},
+		{name: "Unsupported Version", archive: todo.Archive{Version: todo.ArchiveVersion + 1}, wantErr: true},
+		{name: "Duplicate Item Id", archive: archive(item(1, nil), item(1, nil)), wantErr: true},
+		{name: "Empty Title", archive: archive(item(1, func(i *todo.ArchiveItem) { i.Title = " " })), wantErr: true},
+		{name: "Unknown Parent", archive: archive(item(1, func(i *todo.ArchiveItem) { i.ParentId = &unknown })), wantErr: true},
+		{
+			name: "Parent Cycle",
+			archive: archive(
+				item(1, func(i *todo.ArchiveItem) { i.ParentId = &two }),
+				item(2, func(i *todo.ArchiveItem) { i.ParentId = &one }),
+			),
+			wantErr: true,
+		},
+		{name: "Unknown Tag", archive: archive(item(1, func(i *todo.ArchiveItem) { i.TagIds = []int{6} })), wantErr: true},
+		{name: "Invalid Recurrence", archive: archive(item(1, func(i *todo.ArchiveItem) { i.DueDate, i.Recurrence = &due, "FREQ=SOMETIMES" })), wantErr: true},
+		{
+			name: "Invalid Reminder",
+			archive: archive(item(1, func(i *todo.ArchiveItem) {
+				i.Reminders = []todo.ArchiveReminder{{OffsetMinutes: &offset, Channel: todo.ReminderChannelEmail, Target: "alex@example.com"}}
+			})),
+			wantErr: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &archiveRepoStub{}
+			s := NewArchiveService(repo, nil)
+
+			result, err := s.Import(7, testCase.archive)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArchive)
+				assert.Nil(t, repo.imported)
+				assert.Error(t, testCase.archive.Validate()) // архив отклоняется целиком еще при проверке
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, todo.ImportResult{Lists: 1}, result)
+			items := repo.imported.Lists[0].Items
+			assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", items[2].Recurrence)
+			// 9:00 UTC - 18:00 в Токио, срок на весь день - полночь этих суток по Токио
+			tokyo, _ := time.LoadLocation("Asia/Tokyo")
+			assert.True(t, items[3].DueDate.Equal(time.Date(2022, 6, 13, 0, 0, 0, 0, tokyo)), "due date: %s", items[3].DueDate)
+			// без срока признаки срока сбрасываются
+			assert.False(t, items[4].DueAllDay)
+			assert.Empty(t, items[4].DueTimezone)
+		})
+	}
+}
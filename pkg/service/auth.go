@@ -2,75 +2,461 @@ package service
 
 import (
 	"crypto/sha1"
+	"crypto/subtle"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 	"todo-app"
+	"todo-app/pkg/jwtkeys"
 	"todo-app/pkg/repository"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
 )
 
-const tokenTTL = 30 * time.Hour
-
-const JWT_SECRET = "rkjk#4#%35FSFJlja#4353KSFjH"
+// Соль старых хэшей SHA1, используется только для их проверки при входе
 const SOLT = "hjqrhjqw124617ajfhajs"
 
+// Стоимость bcrypt. Хэши с меньшей стоимостью пересчитываются при входе
+const passwordHashCost = bcrypt.DefaultCost
+
+// Хэш для проверки пароля неизвестного пользователя: время ответа не выдает, существует ли username
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+func dummyPasswordHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), passwordHashCost)
+	})
+	return dummyHash
+}
+
+var (
+	// Одна ошибка для неизвестного пользователя и неверного пароля, чтобы нельзя было подбирать username
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// Неизвестный, истекший, уже использованный refresh-токен или отозванная сессия
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrTokenRevoked        = errors.New("token revoked")
+	ErrInvalidChallenge    = errors.New("invalid or expired two-factor challenge")
+	// Учетная запись отключена администратором
+	ErrAccountDisabled = errors.New("account is disabled")
+	ErrUserNotFound    = errors.New("user not found")
+)
+
+// Время на ввод кода 2FA после проверки пароля
+const challengeTTL = 5 * time.Minute
+
+const challengePurpose = "2fa"
+
+// Попыток ввода кода на один токен второго шага, после них нужно снова войти по паролю
+const challengeMaxAttempts = 5
+
+// Пароль верный, но включена 2FA: вместо токенов выдается токен второго шага входа
+type TwoFactorRequiredError struct {
+	ChallengeToken string
+	ExpiresIn      int // секунд
+}
+
+func (e *TwoFactorRequiredError) Error() string {
+	return "two-factor authentication required"
+}
+
+type ConfigAuth struct {
+	AccessTTL  time.Duration // время жизни access-токена
+	RefreshTTL time.Duration // время жизни refresh-токена, продлевается при каждом обновлении
+	TOTPIssuer string        // название сервиса в приложении-аутентификаторе
+	Login      ConfigLogin   // защита входа от подбора пароля
+}
+
 type AuthService struct {
-	repo repository.Authorization
+	repo          repository.Authorization
+	sessionsRepo  repository.Sessions
+	twoFactorRepo repository.TwoFactor
+	revoked       repository.SessionsCach
+	attempts      repository.LoginAttempts
+	audit         repository.Audit
+	keys          *jwtkeys.KeySet
+	cfg           ConfigAuth
 }
 
 type tokenClaims struct {
 	jwt.StandardClaims
-	UserId int `json:"user_id"`
+	UserId    int `json:"user_id"`
+	SessionId int `json:"sid"`
+}
+
+// Токен второго шага входа. Не содержит sid, поэтому не принимается как access-токен
+type challengeClaims struct {
+	jwt.StandardClaims
+	UserId     int    `json:"user_id"`
+	Purpose    string `json:"purpose"`
+	DeviceName string `json:"device_name,omitempty"`
+}
+
+func (c ConfigAuth) withDefaults() ConfigAuth {
+	if c.AccessTTL <= 0 {
+		c.AccessTTL = 15 * time.Minute
+	}
+	if c.RefreshTTL <= 0 {
+		c.RefreshTTL = 30 * 24 * time.Hour
+	}
+	if c.TOTPIssuer == "" {
+		c.TOTPIssuer = "Todo App"
+	}
+	c.Login = c.Login.withDefaults()
+	return c
 }
 
-func NewAuthService(repo repository.Authorization) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo repository.Authorization, sessionsRepo repository.Sessions, twoFactorRepo repository.TwoFactor,
+	revoked repository.SessionsCach, attempts repository.LoginAttempts, audit repository.Audit, keys *jwtkeys.KeySet,
+	cfg ConfigAuth) *AuthService {
+	return &AuthService{
+		repo:          repo,
+		sessionsRepo:  sessionsRepo,
+		twoFactorRepo: twoFactorRepo,
+		revoked:       revoked,
+		attempts:      attempts,
+		audit:         audit,
+		keys:          keys,
+		cfg:           cfg.withDefaults(),
+	}
 }
 
+// Регистрация пользователя. Адрес почты приводится к нижнему регистру, занятый адрес не принимается
 func (s *AuthService) CreateUser(user todo.User) (int, error) {
-	user.Password = generatePasswordHash(user.Password) // Перезаписываем пароль на хэшированный
+	user.Email = normalizeEmail(user.Email)
+	if user.Email != "" {
+		_, err := s.repo.GetUserByEmail(user.Email)
+		if err == nil {
+			return 0, ErrEmailTaken
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	hash, err := generatePasswordHash(user.Password)
+	if err != nil {
+		return 0, err
+	}
+	user.Password = hash // Перезаписываем пароль на хэшированный
 	return s.repo.CreateUser(user)
 }
 
-func (s *AuthService) GenerateToken(username, password string) (string, error) { // Генерация токена по имени и паролю
-	user, err := s.repo.GetUser(username, generatePasswordHash(password)) // поиск пользователя в БД
+// Вход по имени и паролю: создается новая сессия устройства client и выдается пара токенов.
+// Если у пользователя включена 2FA, возвращается *TwoFactorRequiredError с токеном второго шага.
+// После серии неудач по username или ip вход временно запрещается (*LoginBlockedError)
+func (s *AuthService) GenerateToken(username, password string, client todo.SessionClient) (todo.Tokens, error) {
+	if err := s.checkLoginBlocked(username, client.Ip); err != nil {
+		return todo.Tokens{}, err
+	}
+
+	user, err := s.checkPassword(username, password) // поиск пользователя в БД и проверка пароля
+	if errors.Is(err, ErrInvalidCredentials) {
+		if err := s.loginFailed(username, client.Ip); err != nil {
+			return todo.Tokens{}, err
+		}
+		return todo.Tokens{}, ErrInvalidCredentials
+	}
 	if err != nil {
-		return "", err
+		return todo.Tokens{}, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{ // генерация токена
-		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(tokenTTL).Unix(), // время действия токена
-			IssuedAt:  time.Now().Unix(),               //время создания
-		},
-		user.Id,
-	})
+	tokens, err := s.issueTokens(user.Id, client)
+	if err != nil { // в т.ч. требуется 2FA: счетчик неудач сбрасывается только после второго шага
+		return todo.Tokens{}, err
+	}
+	if err := s.attempts.Reset(username); err != nil {
+		return todo.Tokens{}, err
+	}
 
-	return token.SignedString([]byte(JWT_SECRET))
+	return tokens, nil
 }
 
-func (s *AuthService) ParseToken(accesstoken string) (int, error) { //Парс токена (получаем из токена id)
-	token, err := jwt.ParseWithClaims(accesstoken, &tokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
+// Выдача токенов после проверки первого фактора (пароль или вход через провайдера OIDC).
+// При включенной 2FA вместо токенов возвращается *TwoFactorRequiredError
+func (s *AuthService) issueTokens(userId int, client todo.SessionClient) (todo.Tokens, error) {
+	user, err := s.repo.GetUserById(userId)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if user.Disabled {
+		return todo.Tokens{}, ErrAccountDisabled
+	}
+
+	tf, err := s.twoFactorRepo.Get(userId)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if tf.Enabled {
+		challengeId, err := generateSecretToken() // по id считаются попытки ввода кода
+		if err != nil {
+			return todo.Tokens{}, err
 		}
-		return []byte(JWT_SECRET), nil
-	})
+		challenge, err := s.keys.Sign(&challengeClaims{
+			jwt.StandardClaims{
+				Id:        challengeId,
+				ExpiresAt: time.Now().Add(challengeTTL).Unix(),
+				IssuedAt:  time.Now().Unix(),
+			},
+			userId,
+			challengePurpose,
+			client.DeviceName,
+		})
+		if err != nil {
+			return todo.Tokens{}, err
+		}
+		return todo.Tokens{}, &TwoFactorRequiredError{ChallengeToken: challenge, ExpiresIn: int(challengeTTL.Seconds())}
+	}
+
+	return s.createSession(userId, client)
+}
+
+// Второй шаг входа: обмен токена второго шага и кода 2FA (или кода восстановления) на пару токенов.
+// Токен одноразовый и принимает не больше challengeMaxAttempts кодов, затем - ErrInvalidChallenge.
+// Неверные коды учитываются вместе с неудачами ввода пароля (задержка и блокировка по username и ip)
+func (s *AuthService) VerifyTwoFactor(challengeToken, code string, client todo.SessionClient) (todo.Tokens, error) {
+	token, err := s.keys.Parse(challengeToken, &challengeClaims{})
 	if err != nil {
-		return 0, err
+		return todo.Tokens{}, ErrInvalidChallenge
+	}
+
+	claims, ok := token.Claims.(*challengeClaims)
+	if !ok || claims.Purpose != challengePurpose || claims.UserId == 0 || claims.Id == "" {
+		return todo.Tokens{}, ErrInvalidChallenge
+	}
+
+	// Попытка учитывается до проверки кода, так что параллельные запросы не обходят ограничение
+	attempts, err := s.attempts.ChallengeAttempt(claims.Id, challengeTTL)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if attempts > challengeMaxAttempts {
+		return todo.Tokens{}, ErrInvalidChallenge
+	}
+
+	tf, err := s.twoFactorRepo.Get(claims.UserId)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if !tf.Enabled { // 2FA отключена после проверки пароля - нужно войти заново
+		return todo.Tokens{}, ErrInvalidChallenge
+	}
+	if err := s.checkLoginBlocked(tf.Username, client.Ip); err != nil {
+		return todo.Tokens{}, err
+	}
+
+	err = verifySecondFactor(s.twoFactorRepo, tf, claims.UserId, code)
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		if err := s.loginFailed(tf.Username, client.Ip); err != nil {
+			return todo.Tokens{}, err
+		}
+		return todo.Tokens{}, ErrInvalidTwoFactorCode
+	}
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+	if err := s.attempts.ChallengeDone(claims.Id, challengeTTL); err != nil {
+		return todo.Tokens{}, err
+	}
+	if err := s.attempts.Reset(tf.Username); err != nil {
+		return todo.Tokens{}, err
+	}
+
+	client.DeviceName = claims.DeviceName
+	return s.createSession(claims.UserId, client)
+}
+
+func (s *AuthService) createSession(userId int, client todo.SessionClient) (todo.Tokens, error) {
+	refreshToken, err := generateSecretToken()
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	sessionId, err := s.sessionsRepo.Create(userId, truncateClient(client), hashSecretToken(refreshToken),
+		time.Now().Add(s.cfg.RefreshTTL))
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	return s.newTokens(userId, sessionId, refreshToken)
+}
+
+// Обмен refresh-токена на новую пару. Каждый refresh-токен одноразовый: повторное
+// предъявление означает, что токен украден, и вся сессия отзывается. ip - адрес клиента для списка сессий
+func (s *AuthService) RefreshToken(refreshToken, ip string) (todo.Tokens, error) {
+	newRefreshToken, err := generateSecretToken()
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	userId, sessionId, err := s.sessionsRepo.Rotate(hashSecretToken(refreshToken), hashSecretToken(newRefreshToken), ip,
+		time.Now().Add(s.cfg.RefreshTTL))
+	if errors.Is(err, repository.ErrRefreshTokenReused) {
+		logrus.Warnf("refresh token reuse detected, session %d of user %d revoked", sessionId, userId)
+		if err := s.revoked.Revoke([]int{sessionId}, s.cfg.AccessTTL); err != nil {
+			return todo.Tokens{}, err
+		}
+		return todo.Tokens{}, ErrInvalidRefreshToken
+	}
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, repository.ErrSessionRevoked) {
+		return todo.Tokens{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	return s.newTokens(userId, sessionId, newRefreshToken)
+}
+
+// Выход из текущей сессии: ее refresh-токены больше не обмениваются, а access-токены
+// отклоняются до истечения по отметке в Redis
+func (s *AuthService) Logout(userId, sessionId int) error {
+	// Сессия уже отозвана в БД (например, при обнаружении повторного refresh-токена) - отметка в Redis все равно ставится
+	if err := s.sessionsRepo.Revoke(userId, sessionId); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return s.revoked.Revoke([]int{sessionId}, s.cfg.AccessTTL)
+}
+
+// Выход на всех устройствах
+func (s *AuthService) LogoutAll(userId int) error {
+	sessionIds, err := s.sessionsRepo.RevokeAll(userId, -1)
+	if err != nil {
+		return err
+	}
+	return s.revoked.Revoke(sessionIds, s.cfg.AccessTTL)
+}
+
+// Проверка пользователя при каждом запросе, возвращает признак администратора.
+// Запросы отключенного или удаленного пользователя отклоняются даже с действующим токеном
+func (s *AuthService) CheckUser(userId int) (bool, error) {
+	user, err := s.repo.GetUserById(userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	if user.Disabled {
+		return false, ErrAccountDisabled
+	}
+
+	return user.IsAdmin, nil
+}
+
+// Парс токена (получаем из токена id пользователя и сессии)
+func (s *AuthService) ParseToken(accesstoken string) (int, int, error) {
+	token, err := s.keys.Parse(accesstoken, &tokenClaims{}) // проверка подписи ключом из заголовка kid
+	if err != nil {
+		return 0, 0, err
 	}
 
 	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return 0, errors.New("token claims are not of type *tokenClaims")
+		return 0, 0, errors.New("token claims are not of type *tokenClaims")
+	}
+	if claims.SessionId == 0 { // токены, выданные до появления сессий, не отзываются и не принимаются
+		return 0, 0, errors.New("token has no session")
+	}
+
+	revoked, err := s.revoked.IsRevoked(claims.SessionId)
+	if err != nil {
+		return 0, 0, err
 	}
+	if revoked {
+		return 0, 0, ErrTokenRevoked
+	}
+
+	return claims.UserId, claims.SessionId, nil
+}
+
+func (s *AuthService) newTokens(userId, sessionId int, refreshToken string) (todo.Tokens, error) {
+	accessToken, err := s.keys.Sign(&tokenClaims{ // генерация токена, подписывается активным ключом
+		jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(s.cfg.AccessTTL).Unix(), // время действия токена
+			IssuedAt:  time.Now().Unix(),                      //время создания
+		},
+		userId,
+		sessionId,
+	})
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	return todo.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.cfg.AccessTTL.Seconds()),
+	}, nil
+}
+
+// Открытые ключи для проверки токенов другими сервисами
+func (s *AuthService) JWKS() jwtkeys.JWKS {
+	return s.keys.JWKS()
+}
+
+// Проверка пароля пользователя. Старый хэш SHA1 или bcrypt с устаревшей стоимостью
+// после успешной проверки заменяется новым, так что пользователям не нужно сбрасывать пароль
+func (s *AuthService) checkPassword(username, password string) (todo.User, error) {
+	user, err := s.repo.GetUser(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		// user does not exists
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return todo.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return todo.User{}, err
+	}
+
+	rehash := false
+	if isLegacyPasswordHash(user.Password) {
+		if subtle.ConstantTimeCompare([]byte(legacyPasswordHash(password)), []byte(user.Password)) != 1 {
+			return todo.User{}, ErrInvalidCredentials
+		}
+		rehash = true
+	} else {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return todo.User{}, ErrInvalidCredentials
+		}
+		cost, err := bcrypt.Cost([]byte(user.Password))
+		rehash = err == nil && cost < passwordHashCost
+	}
+
+	if rehash {
+		// Ошибка обновления хэша не мешает входу: хэш будет обновлен при следующем входе
+		hash, err := generatePasswordHash(password)
+		if err == nil {
+			err = s.repo.UpdatePasswordHash(user.Id, hash)
+		}
+		if err != nil {
+			logrus.Errorf("failed to upgrade password hash of user %d: %s", user.Id, err.Error())
+		}
+	}
+
+	return user, nil
+}
+
+func generatePasswordHash(password string) (string, error) { // Хэширование пароля (bcrypt, соль своя для каждого хэша)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
 
-	return claims.UserId, nil
+// Хэши bcrypt начинаются с "$2", все остальные - старые SHA1
+func isLegacyPasswordHash(hash string) bool {
+	return !strings.HasPrefix(hash, "$2")
 }
 
-func generatePasswordHash(password string) string { // Хэширование пароля
+func legacyPasswordHash(password string) string { // Старое хэширование пароля (SHA1 со статической солью)
 	hash := sha1.New()
 	hash.Write([]byte(password))
 
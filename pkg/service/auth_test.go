@@ -0,0 +1,343 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/jwtkeys"
+	"todo-app/pkg/repository"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Хранилище пользователей в памяти для проверки паролей без БД
+type authRepoStub struct {
+	users    map[string]todo.User
+	updated  map[int]string
+	verified map[int]string
+	deleted  []int
+	created  []todo.User
+	err      error // ошибка БД при поиске пользователя
+}
+
+func (r *authRepoStub) CreateUser(user todo.User) (int, error) {
+	r.created = append(r.created, user)
+	return len(r.created), nil
+}
+
+func (r *authRepoStub) GetUser(username string) (todo.User, error) {
+	if r.err != nil {
+		return todo.User{}, r.err
+	}
+	user, ok := r.users[username]
+	if !ok {
+		return todo.User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (r *authRepoStub) UpdatePasswordHash(userId int, hash string) error {
+	r.updated[userId] = hash
+	return nil
+}
+
+func (r *authRepoStub) GetUserById(userId int) (todo.User, error) {
+	for _, user := range r.users {
+		if user.Id == userId {
+			return user, nil
+		}
+	}
+	return todo.User{}, sql.ErrNoRows
+}
+
+func (r *authRepoStub) GetUserByEmail(email string) (todo.User, error) {
+	for _, user := range r.users {
+		if user.Email != "" && strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return todo.User{}, sql.ErrNoRows
+}
+
+func (r *authRepoStub) SetEmailVerified(userId int, email string) error {
+	user, err := r.GetUserById(userId)
+	if err != nil || !strings.EqualFold(user.Email, email) {
+		return sql.ErrNoRows
+	}
+	r.verified[userId] = email
+	return nil
+}
+
+func (r *authRepoStub) UpdateUser(userId int, input todo.UpdateUserInput) error {
+	for username, user := range r.users {
+		if user.Id != userId {
+			continue
+		}
+		if input.Name != nil {
+			user.Name = *input.Name
+		}
+		if input.Email != nil && !strings.EqualFold(user.Email, *input.Email) {
+			user.Email, user.EmailVerified = *input.Email, false
+		}
+		delete(r.users, username)
+		if input.Username != nil {
+			user.Username = *input.Username
+		}
+		r.users[user.Username] = user
+		return nil
+	}
+	return nil
+}
+
+func (r *authRepoStub) DeleteUser(userId int) error {
+	r.deleted = append(r.deleted, userId)
+	return nil
+}
+
+// Результат обмена refresh-токена задается в тесте, отзыв сессий запоминается
+type sessionsRepoStub struct {
+	userId, sessionId int
+	rotateErr         error
+	revoked           []int
+}
+
+func (r *sessionsRepoStub) Create(userId int, client todo.SessionClient, tokenHash string, expiresAt time.Time) (int, error) {
+	return r.sessionId, nil
+}
+
+func (r *sessionsRepoStub) Rotate(tokenHash, newTokenHash, ip string, expiresAt time.Time) (int, int, error) {
+	return r.userId, r.sessionId, r.rotateErr
+}
+
+func (r *sessionsRepoStub) GetAll(userId int) ([]todo.Session, error) {
+	return []todo.Session{{Id: r.sessionId}, {Id: r.sessionId + 1}}, nil
+}
+
+func (r *sessionsRepoStub) Revoke(userId, sessionId int) error {
+	r.revoked = append(r.revoked, sessionId)
+	return nil
+}
+
+// У пользователя две сессии, как в GetAll
+func (r *sessionsRepoStub) RevokeAll(userId, exceptSessionId int) ([]int, error) {
+	ids := make([]int, 0, 2)
+	for _, id := range []int{r.sessionId, r.sessionId + 1} {
+		if id != exceptSessionId {
+			ids = append(ids, id)
+		}
+	}
+	r.revoked = append(r.revoked, ids...)
+	return ids, nil
+}
+
+type sessionsCachStub map[int]bool
+
+func (c sessionsCachStub) Revoke(sessionIds []int, ttl time.Duration) error {
+	for _, id := range sessionIds {
+		c[id] = true
+	}
+	return nil
+}
+
+func (c sessionsCachStub) IsRevoked(sessionId int) (bool, error) {
+	return c[sessionId], nil
+}
+
+func newTestKeys(t *testing.T) *jwtkeys.KeySet {
+	t.Setenv("TEST_JWT_SECRET", "0123456789abcdef0123456789abcdef")
+	keys, err := jwtkeys.New(jwtkeys.Config{ActiveKey: "hs", Keys: []jwtkeys.KeyConfig{
+		{Id: "hs", Alg: jwtkeys.AlgHS256, SecretEnv: "TEST_JWT_SECRET"},
+	}})
+	if err != nil {
+		t.Fatalf("failed to create keys: %s", err.Error())
+	}
+	return keys
+}
+
+func TestAuthService_checkPassword(t *testing.T) {
+	current, err := generatePasswordHash("qwerty")
+	assert.NoError(t, err)
+
+	cheap, err := bcrypt.GenerateFromPassword([]byte("qwerty"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name       string
+		hash       string
+		password   string
+		wantErr    bool
+		wantRehash bool
+	}{
+		{name: "Bcrypt", hash: current, password: "qwerty"},
+		{name: "Bcrypt Wrong Password", hash: current, password: "qwerty1", wantErr: true},
+		{name: "Legacy SHA1", hash: legacyPasswordHash("qwerty"), password: "qwerty", wantRehash: true},
+		{name: "Legacy SHA1 Wrong Password", hash: legacyPasswordHash("qwerty"), password: "qwerty1", wantErr: true},
+		{name: "Low Cost Bcrypt", hash: string(cheap), password: "qwerty", wantRehash: true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &authRepoStub{
+				users:   map[string]todo.User{"alex": {Id: 7, Password: testCase.hash}},
+				updated: map[int]string{},
+			}
+			s := NewAuthService(repo, nil, nil, nil, nil, nil, nil, ConfigAuth{})
+
+			user, err := s.checkPassword("alex", testCase.password)
+			if testCase.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+				assert.Empty(t, repo.updated)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 7, user.Id)
+
+			hash, rehashed := repo.updated[7]
+			assert.Equal(t, testCase.wantRehash, rehashed)
+			if rehashed {
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(testCase.password)))
+				cost, err := bcrypt.Cost([]byte(hash))
+				assert.NoError(t, err)
+				assert.Equal(t, passwordHashCost, cost)
+			}
+		})
+	}
+}
+
+func TestAuthService_checkPassword_UnknownUser(t *testing.T) {
+	s := NewAuthService(&authRepoStub{users: map[string]todo.User{}, updated: map[int]string{}},
+		nil, nil, nil, nil, nil, nil, ConfigAuth{})
+
+	_, err := s.checkPassword("nobody", "qwerty")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	cost, err := bcrypt.Cost(dummyPasswordHash())
+	assert.NoError(t, err)
+	assert.Equal(t, passwordHashCost, cost)
+}
+
+func TestAuthService_checkPassword_RepositoryFailure(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	s := NewAuthService(&authRepoStub{err: dbErr}, nil, nil, nil, nil, nil, nil, ConfigAuth{})
+
+	_, err := s.checkPassword("alex", "qwerty")
+	assert.ErrorIs(t, err, dbErr)
+	assert.NotErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthService_CreateUser(t *testing.T) {
+	testTable := []struct {
+		name      string
+		email     string
+		wantEmail string
+		wantErr   error
+	}{
+		{name: "OK", email: "  New@Example.com ", wantEmail: "new@example.com"},
+		{name: "No Email", email: "", wantEmail: ""},
+		{name: "Email Taken", email: "ALEX@example.com ", wantErr: ErrEmailTaken},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			repo := &authRepoStub{users: map[string]todo.User{
+				"alex": {Id: 1, Username: "alex", Email: "alex@example.com"},
+			}}
+			s := NewAuthService(repo, nil, nil, nil, nil, nil, nil, ConfigAuth{})
+
+			_, err := s.CreateUser(todo.User{Name: "New", Username: "new", Password: "qwerty", Email: testCase.email})
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				assert.Empty(t, repo.created)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, repo.created, 1) {
+				assert.Equal(t, testCase.wantEmail, repo.created[0].Email)
+				assert.NotEqual(t, "qwerty", repo.created[0].Password)
+			}
+		})
+	}
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	keys := newTestKeys(t)
+
+	testTable := []struct {
+		name        string
+		rotateErr   error
+		wantErr     error
+		wantRevoked bool
+	}{
+		{name: "OK"},
+		{name: "Reuse Revokes Session", rotateErr: repository.ErrRefreshTokenReused, wantErr: ErrInvalidRefreshToken, wantRevoked: true},
+		{name: "Session Revoked", rotateErr: repository.ErrSessionRevoked, wantErr: ErrInvalidRefreshToken},
+		{name: "Unknown Or Expired", rotateErr: sql.ErrNoRows, wantErr: ErrInvalidRefreshToken},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			revoked := sessionsCachStub{}
+			s := NewAuthService(nil, &sessionsRepoStub{userId: 7, sessionId: 3, rotateErr: testCase.rotateErr}, nil, revoked,
+				nil, nil, keys, ConfigAuth{AccessTTL: time.Minute})
+
+			tokens, err := s.RefreshToken("refresh", "192.0.2.1")
+			assert.Equal(t, testCase.wantRevoked, revoked[3])
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, tokens.RefreshToken)
+			assert.NotEqual(t, "refresh", tokens.RefreshToken)
+			assert.Equal(t, 60, tokens.ExpiresIn)
+
+			userId, sessionId, err := s.ParseToken(tokens.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, 7, userId)
+			assert.Equal(t, 3, sessionId)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	sessions := &sessionsRepoStub{userId: 7, sessionId: 3}
+	s := NewAuthService(nil, sessions, nil, sessionsCachStub{}, nil, nil, newTestKeys(t), ConfigAuth{})
+
+	tokens, err := s.RefreshToken("refresh", "192.0.2.1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Logout(7, 3))
+	assert.Equal(t, []int{3}, sessions.revoked)
+
+	_, _, err = s.ParseToken(tokens.AccessToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+}
+
+func TestAuthService_CheckUser(t *testing.T) {
+	repo := &authRepoStub{users: map[string]todo.User{
+		"root":  {Id: 1, Username: "root", IsAdmin: true},
+		"alex":  {Id: 7, Username: "alex"},
+		"carol": {Id: 11, Username: "carol", Disabled: true},
+	}}
+	s := NewAuthService(repo, nil, nil, nil, nil, nil, nil, ConfigAuth{})
+
+	isAdmin, err := s.CheckUser(1)
+	assert.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	isAdmin, err = s.CheckUser(7)
+	assert.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	_, err = s.CheckUser(11)
+	assert.ErrorIs(t, err, ErrAccountDisabled)
+
+	_, err = s.CheckUser(42)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+type InvitationsService struct {
+	repo     repository.Invitations
+	listRepo repository.TodoList
+}
+
+func NewInvitationsService(repo repository.Invitations, listRepo repository.TodoList) *InvitationsService {
+	return &InvitationsService{repo: repo, listRepo: listRepo}
+}
+
+// Приглашать может только владелец списка. Для приглашения по ссылке возвращается токен,
+// он показывается один раз: в БД хранится только его хэш
+func (s *InvitationsService) Create(userId, listId int, input todo.CreateInvitationInput) (int, string, error) {
+	if err := input.Validate(); err != nil {
+		return 0, "", err
+	}
+
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleOwner); err != nil {
+		return 0, "", err
+	}
+
+	expiresAt := time.Now().Add(input.TTL())
+
+	if input.Username != "" {
+		members, err := s.listRepo.GetMembers(listId)
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, member := range members {
+			if member.Username == input.Username {
+				return 0, "", errors.New("user is already a member of this list")
+			}
+		}
+
+		id, err := s.repo.Create(userId, listId, input.Username, input.Role, expiresAt, nil)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", errors.New("user not found")
+		}
+		return id, "", err
+	}
+
+	token, err := generateSecretToken()
+	if err != nil {
+		return 0, "", err
+	}
+
+	tokenHash := hashSecretToken(token)
+	id, err := s.repo.Create(userId, listId, "", input.Role, expiresAt, &tokenHash)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return id, token, nil
+}
+
+func (s *InvitationsService) GetByList(userId, listId int) ([]todo.Invitation, error) {
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleOwner); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByList(listId)
+}
+
+func (s *InvitationsService) GetPending(userId int) ([]todo.Invitation, error) {
+	return s.repo.GetPending(userId)
+}
+
+func (s *InvitationsService) Revoke(userId, listId, invitationId int) error {
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleOwner); err != nil {
+		return err
+	}
+	return invitationError(s.repo.Revoke(listId, invitationId))
+}
+
+func (s *InvitationsService) Accept(userId, invitationId int) (int, error) {
+	listId, err := s.repo.Accept(userId, invitationId)
+	return listId, invitationError(err)
+}
+
+func (s *InvitationsService) AcceptToken(userId int, token string) (int, error) {
+	listId, err := s.repo.AcceptToken(userId, hashSecretToken(token))
+	return listId, invitationError(err)
+}
+
+func (s *InvitationsService) Decline(userId, invitationId int) error {
+	return invitationError(s.repo.Decline(userId, invitationId))
+}
+
+// Отозванное, истекшее, уже принятое и чужое приглашение для пользователя неотличимы
+func invitationError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("invitation not found or expired")
+	}
+	return err
+}
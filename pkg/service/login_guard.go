@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+	"todo-app"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Пороги защиты входа от подбора пароля
+type ConfigLogin struct {
+	Window          time.Duration // время жизни счетчиков неудач, продлевается с каждой неудачей
+	BackoffAfter    int64         // неудач по username, после которых между попытками появляется задержка
+	IpBackoffAfter  int64         // то же для ip (с одного адреса могут входить разные пользователи)
+	BackoffBase     time.Duration // первая задержка, каждая следующая неудача удваивает ее
+	BackoffMax      time.Duration
+	LockoutAfter    int64 // неудач по username до временной блокировки учетной записи
+	LockoutDuration time.Duration
+}
+
+func (c ConfigLogin) withDefaults() ConfigLogin {
+	if c.Window <= 0 {
+		c.Window = 15 * time.Minute
+	}
+	if c.BackoffAfter <= 0 {
+		c.BackoffAfter = 3
+	}
+	if c.IpBackoffAfter <= 0 {
+		c.IpBackoffAfter = 20
+	}
+	if c.BackoffBase <= 0 {
+		c.BackoffBase = time.Second
+	}
+	if c.BackoffMax <= 0 {
+		c.BackoffMax = 5 * time.Minute
+	}
+	if c.LockoutAfter <= 0 {
+		c.LockoutAfter = 10
+	}
+	if c.LockoutDuration <= 0 {
+		c.LockoutDuration = 15 * time.Minute
+	}
+	return c
+}
+
+// Вход временно запрещен: Locked - учетная запись заблокирована после серии неудач,
+// иначе нужно подождать RetryAfter перед следующей попыткой
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginBlockedError) Error() string {
+	if e.Locked {
+		return "account temporarily locked due to too many failed login attempts"
+	}
+	return "too many login attempts, try again later"
+}
+
+// Задержка после fails неудач при пороге after: base, 2*base, 4*base ... но не больше max
+func (c ConfigLogin) backoff(fails, after int64) time.Duration {
+	if fails < after {
+		return 0
+	}
+	shift := fails - after
+	if shift > 30 {
+		return c.BackoffMax
+	}
+	delay := c.BackoffBase << shift
+	if delay <= 0 || delay > c.BackoffMax {
+		return c.BackoffMax
+	}
+	return delay
+}
+
+// Проверка блокировок до проверки пароля, чтобы заблокированный вход не нагружал bcrypt
+func (s *AuthService) checkLoginBlocked(username, ip string) error {
+	lock, userDelay, ipDelay, err := s.attempts.Blocked(username, ip)
+	if err != nil {
+		return err
+	}
+
+	if lock > 0 {
+		return &LoginBlockedError{RetryAfter: lock, Locked: true}
+	}
+	if delay := maxDuration(userDelay, ipDelay); delay > 0 {
+		return &LoginBlockedError{RetryAfter: delay}
+	}
+	return nil
+}
+
+// Учет неудачного входа: задержка следующей попытки или блокировка учетной записи с записью в журнал аудита
+func (s *AuthService) loginFailed(username, ip string) error {
+	cfg := s.cfg.Login
+
+	userFails, ipFails, err := s.attempts.Fail(username, ip, cfg.Window)
+	if err != nil {
+		return err
+	}
+
+	if userFails >= cfg.LockoutAfter {
+		if err := s.attempts.Lock(username, cfg.LockoutDuration); err != nil {
+			return err
+		}
+		s.auditLockout(username, ip, userFails)
+		return s.attempts.Delay(username, ip, 0, cfg.backoff(ipFails, cfg.IpBackoffAfter))
+	}
+
+	return s.attempts.Delay(username, ip, cfg.backoff(userFails, cfg.BackoffAfter), cfg.backoff(ipFails, cfg.IpBackoffAfter))
+}
+
+// Ошибка записи в журнал не мешает блокировке
+func (s *AuthService) auditLockout(username, ip string, fails int64) {
+	record := todo.AuditRecord{Action: todo.AuditLoginLockout, Ip: ip}
+	if user, err := s.repo.GetUser(username); err == nil {
+		record.UserId = &user.Id
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"username":     username,
+		"failures":     fails,
+		"locked_until": time.Now().Add(s.cfg.Login.LockoutDuration).UTC().Format(time.RFC3339),
+	})
+	if err == nil {
+		record.Details = details
+		err = s.audit.Create(record)
+	}
+	if err != nil {
+		logrus.Errorf("failed to write audit record: %s", err.Error())
+	}
+
+	logrus.Warnf("login locked for username %q after %d failed attempts from %s", username, fails, ip)
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Счетчики попыток входа в памяти. Сроки не истекают сами, тест "ждет", очищая delays и locks
+type loginAttemptsStub struct {
+	userFails, ipFails map[string]int64
+	userDelays         map[string]time.Duration
+	ipDelays           map[string]time.Duration
+	locks              map[string]time.Duration
+	challenges         map[string]int64
+}
+
+func newLoginAttemptsStub() *loginAttemptsStub {
+	return &loginAttemptsStub{
+		userFails:  map[string]int64{},
+		ipFails:    map[string]int64{},
+		userDelays: map[string]time.Duration{},
+		ipDelays:   map[string]time.Duration{},
+		locks:      map[string]time.Duration{},
+		challenges: map[string]int64{},
+	}
+}
+
+func (r *loginAttemptsStub) Blocked(username, ip string) (time.Duration, time.Duration, time.Duration, error) {
+	return r.locks[username], r.userDelays[username], r.ipDelays[ip], nil
+}
+
+func (r *loginAttemptsStub) Fail(username, ip string, window time.Duration) (int64, int64, error) {
+	r.userFails[username]++
+	r.ipFails[ip]++
+	return r.userFails[username], r.ipFails[ip], nil
+}
+
+func (r *loginAttemptsStub) Delay(username, ip string, userTTL, ipTTL time.Duration) error {
+	if userTTL > 0 {
+		r.userDelays[username] = userTTL
+	}
+	if ipTTL > 0 {
+		r.ipDelays[ip] = ipTTL
+	}
+	return nil
+}
+
+func (r *loginAttemptsStub) Lock(username string, ttl time.Duration) error {
+	r.locks[username] = ttl
+	return r.Reset(username)
+}
+
+func (r *loginAttemptsStub) Reset(username string) error {
+	delete(r.userFails, username)
+	delete(r.userDelays, username)
+	return nil
+}
+
+func (r *loginAttemptsStub) ChallengeAttempt(challengeId string, ttl time.Duration) (int64, error) {
+	r.challenges[challengeId]++
+	return r.challenges[challengeId], nil
+}
+
+func (r *loginAttemptsStub) ChallengeDone(challengeId string, ttl time.Duration) error {
+	r.challenges[challengeId] = math.MaxInt32
+	return nil
+}
+
+type auditStub []todo.AuditRecord
+
+func (a *auditStub) Create(record todo.AuditRecord) error {
+	*a = append(*a, record)
+	return nil
+}
+
+func TestConfigLogin_backoff(t *testing.T) {
+	cfg := ConfigLogin{BackoffBase: time.Second, BackoffMax: 10 * time.Second}
+
+	testTable := []struct {
+		fails int64
+		want  time.Duration
+	}{
+		{fails: 2, want: 0},
+		{fails: 3, want: time.Second},
+		{fails: 4, want: 2 * time.Second},
+		{fails: 6, want: 8 * time.Second},
+		{fails: 7, want: 10 * time.Second},
+		{fails: 100, want: 10 * time.Second},
+	}
+
+	for _, testCase := range testTable {
+		assert.Equal(t, testCase.want, cfg.backoff(testCase.fails, 3), "fails: %d", testCase.fails)
+	}
+}
+
+func TestAuthService_GenerateToken_Lockout(t *testing.T) {
+	hash, err := generatePasswordHash("qwerty")
+	assert.NoError(t, err)
+
+	users := &authRepoStub{users: map[string]todo.User{"alex": {Id: 7, Password: hash}}, updated: map[int]string{}}
+	attempts := newLoginAttemptsStub()
+	audit := &auditStub{}
+	s := NewAuthService(users, &sessionsRepoStub{sessionId: 3}, &twoFactorRepoStub{}, sessionsCachStub{}, attempts, audit,
+		newTestKeys(t), ConfigAuth{Login: ConfigLogin{BackoffAfter: 2, LockoutAfter: 4, LockoutDuration: time.Minute}})
+	client := todo.SessionClient{Ip: "192.0.2.1"}
+
+	wait := func() { attempts.userDelays = map[string]time.Duration{} }
+
+	// Первая неудача без задержки, после второй следующая попытка откладывается
+	_, err = s.GenerateToken("alex", "wrong", client)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = s.GenerateToken("alex", "wrong", client)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = s.GenerateToken("alex", "qwerty", client)
+	var blocked *LoginBlockedError
+	if assert.ErrorAs(t, err, &blocked) {
+		assert.False(t, blocked.Locked)
+		assert.Equal(t, time.Second, blocked.RetryAfter)
+	}
+
+	wait()
+	_, err = s.GenerateToken("alex", "wrong", client)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, 2*time.Second, attempts.userDelays["alex"]) // задержка удваивается
+
+	// Четвертая неудача блокирует учетную запись, даже верный пароль не принимается
+	wait()
+	_, err = s.GenerateToken("alex", "wrong", client)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = s.GenerateToken("alex", "qwerty", client)
+	if assert.ErrorAs(t, err, &blocked) {
+		assert.True(t, blocked.Locked)
+		assert.Equal(t, time.Minute, blocked.RetryAfter)
+	}
+
+	if assert.Len(t, *audit, 1) {
+		record := (*audit)[0]
+		assert.Equal(t, todo.AuditLoginLockout, record.Action)
+		assert.Equal(t, 7, *record.UserId)
+		assert.Equal(t, "192.0.2.1", record.Ip)
+		assert.Contains(t, string(record.Details), `"failures":4`)
+	}
+
+	// После снятия блокировки вход успешен и сбрасывает счетчик
+	delete(attempts.locks, "alex")
+	_, err = s.GenerateToken("alex", "qwerty", client)
+	assert.NoError(t, err)
+	assert.Zero(t, attempts.userFails["alex"])
+	assert.Equal(t, int64(4), attempts.ipFails["192.0.2.1"])
+}
+
+func TestAuthService_VerifyTwoFactor_Lockout(t *testing.T) {
+	hash, err := generatePasswordHash("qwerty")
+	assert.NoError(t, err)
+
+	secret := "JBSWY3DPEHPK3PXP"
+	users := &authRepoStub{users: map[string]todo.User{"alex": {Id: 7, Password: hash}}, updated: map[int]string{}}
+	tfRepo := &twoFactorRepoStub{tf: todo.TwoFactor{Username: "alex", Enabled: true, Secret: &secret}}
+	attempts := newLoginAttemptsStub()
+	audit := &auditStub{}
+	s := NewAuthService(users, &sessionsRepoStub{sessionId: 3}, tfRepo, sessionsCachStub{}, attempts, audit,
+		newTestKeys(t), ConfigAuth{Login: ConfigLogin{BackoffAfter: 10, LockoutAfter: 3, LockoutDuration: time.Minute}})
+	client := todo.SessionClient{Ip: "192.0.2.1"}
+
+	signIn := func() string {
+		_, err := s.GenerateToken("alex", "qwerty", client)
+		var challenge *TwoFactorRequiredError
+		assert.ErrorAs(t, err, &challenge)
+		return challenge.ChallengeToken
+	}
+
+	// Верный пароль без второго шага не сбрасывает счетчик неудач
+	_, err = s.GenerateToken("alex", "wrong", client)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	challenge := signIn()
+	assert.Equal(t, int64(1), attempts.userFails["alex"])
+
+	// Неверные коды 2FA учитываются вместе с неудачами пароля и блокируют учетную запись
+	_, err = s.VerifyTwoFactor(challenge, "wrong-code", client)
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	_, err = s.VerifyTwoFactor(challenge, "wrong-code", client)
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	assert.Equal(t, time.Minute, attempts.locks["alex"])
+	assert.Len(t, *audit, 1)
+
+	code, err := totp.Code(secret, time.Now())
+	assert.NoError(t, err)
+	_, err = s.VerifyTwoFactor(challenge, code, client)
+	var blocked *LoginBlockedError
+	if assert.ErrorAs(t, err, &blocked) {
+		assert.True(t, blocked.Locked)
+	}
+
+	// После снятия блокировки полный вход сбрасывает счетчик
+	delete(attempts.locks, "alex")
+	_, err = s.GenerateToken("alex", "wrong", client)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = s.VerifyTwoFactor(signIn(), code, client)
+	assert.NoError(t, err)
+	assert.Zero(t, attempts.userFails["alex"])
+}
+
+func TestAuthService_GenerateToken_IpBackoff(t *testing.T) {
+	users := &authRepoStub{users: map[string]todo.User{}, updated: map[int]string{}}
+	attempts := newLoginAttemptsStub()
+	s := NewAuthService(users, nil, nil, nil, attempts, nil, nil, ConfigAuth{Login: ConfigLogin{IpBackoffAfter: 3}})
+	client := todo.SessionClient{Ip: "192.0.2.1"}
+
+	// Подбор разных username с одного адреса
+	for _, username := range []string{"a", "b", "c"} {
+		_, err := s.GenerateToken(username, "wrong", client)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	}
+
+	_, err := s.GenerateToken("d", "wrong", client)
+	var blocked *LoginBlockedError
+	if assert.ErrorAs(t, err, &blocked) {
+		assert.False(t, blocked.Locked)
+	}
+
+	_, err = s.GenerateToken("e", "wrong", todo.SessionClient{Ip: "192.0.2.2"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"todo-app"
+	"todo-app/pkg/oidc"
+	"todo-app/pkg/repository"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	oidcStateTTL     = 10 * time.Minute // время на вход на странице провайдера
+	oidcTimeout      = 15 * time.Second
+	usernameAttempts = 5
+)
+
+var (
+	ErrUnknownOIDCProvider  = errors.New("unknown oidc provider")
+	ErrInvalidOIDCState     = errors.New("invalid or expired oidc state")
+	ErrOIDCLoginFailed      = errors.New("oidc login failed")
+	ErrOIDCAccountNotLinked = errors.New("no account is linked to this identity")
+)
+
+type ConfigOIDC struct {
+	Providers []*oidc.Provider
+}
+
+type OIDCService struct {
+	auth       *AuthService
+	authRepo   repository.Authorization
+	identities repository.Identities
+	states     repository.OIDCStates
+	providers  map[string]*oidc.Provider
+}
+
+func NewOIDCService(auth *AuthService, authRepo repository.Authorization, identities repository.Identities,
+	states repository.OIDCStates, cfg ConfigOIDC) *OIDCService {
+	providers := make(map[string]*oidc.Provider, len(cfg.Providers))
+	for _, provider := range cfg.Providers {
+		providers[provider.Name()] = provider
+	}
+
+	return &OIDCService{
+		auth:       auth,
+		authRepo:   authRepo,
+		identities: identities,
+		states:     states,
+		providers:  providers,
+	}
+}
+
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Начало входа: state, nonce и code_verifier сохраняются до возврата пользователя на callback
+func (s *OIDCService) LoginURL(providerName, deviceName string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", ErrUnknownOIDCProvider
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		value, err := oidc.RandomString()
+		if err != nil {
+			return "", err
+		}
+		secrets[i] = value
+	}
+	state, nonce, codeVerifier := secrets[0], secrets[1], secrets[2]
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.states.Save(state, todo.OIDCLogin{
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		DeviceName:   deviceName,
+	}, oidcStateTTL)
+	if err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// Завершение входа: код обменивается на ID-токен, учетная запись провайдера связывается с пользователем
+// (или создается новый пользователь) и выдаются обычные токены приложения.
+// При включенной 2FA возвращается *TwoFactorRequiredError
+func (s *OIDCService) Callback(input todo.OIDCCallbackInput, client todo.SessionClient) (todo.Tokens, error) {
+	login, err := s.states.Take(input.State)
+	if errors.Is(err, redis.Nil) {
+		return todo.Tokens{}, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	if input.Error != "" {
+		return todo.Tokens{}, fmt.Errorf("%w: %s %s", ErrOIDCLoginFailed, input.Error, input.ErrorDescription)
+	}
+
+	provider, ok := s.providers[login.Provider]
+	if !ok { // провайдер убран из конфигурации, пока пользователь входил
+		return todo.Tokens{}, ErrUnknownOIDCProvider
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+
+	idToken, err := provider.Exchange(ctx, input.Code, login.CodeVerifier)
+	if err != nil {
+		logrus.Warnf("oidc code exchange with %s failed: %s", login.Provider, err.Error())
+		return todo.Tokens{}, ErrOIDCLoginFailed
+	}
+
+	claims, err := provider.Verify(ctx, idToken, login.Nonce)
+	if err != nil {
+		logrus.Warnf("oidc id token from %s rejected: %s", login.Provider, err.Error())
+		return todo.Tokens{}, ErrOIDCLoginFailed
+	}
+
+	userId, err := s.resolveUser(provider, claims)
+	if err != nil {
+		return todo.Tokens{}, err
+	}
+
+	client.DeviceName = login.DeviceName
+	return s.auth.issueTokens(userId, client)
+}
+
+// Пользователь для учетной записи провайдера: ранее связанный, существующий с той же подтвержденной почтой
+// (связывается) или новый, если провайдеру разрешено создавать пользователей
+func (s *OIDCService) resolveUser(provider *oidc.Provider, claims oidc.Claims) (int, error) {
+	userId, err := s.identities.GetUserId(provider.Name(), claims.Subject)
+	if err == nil {
+		return userId, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	// Почта связывает учетные записи, только если ее подтвердили и провайдер, и приложение.
+	// Иначе можно было бы заранее зарегистрироваться с чужой почтой и получить доступ после входа владельца
+	email := ""
+	if claims.EmailVerified {
+		email = normalizeEmail(claims.Email)
+	}
+	if email != "" {
+		user, err := s.authRepo.GetUserByEmail(email)
+		switch {
+		case err == nil && user.EmailVerified:
+			if err := s.identities.Link(user.Id, provider.Name(), claims.Subject, email); err != nil {
+				return 0, err
+			}
+			return user.Id, nil
+		case err == nil:
+			email = "" // почта занята пользователем, который ее не подтвердил
+		case !errors.Is(err, sql.ErrNoRows):
+			return 0, err
+		}
+	}
+
+	if !provider.AutoProvision() {
+		return 0, ErrOIDCAccountNotLinked
+	}
+
+	username, err := s.freeUsername(provider.Name(), claims)
+	if err != nil {
+		return 0, err
+	}
+
+	name := firstNonEmpty(claims.Name, claims.PreferredUsername, username)
+	return s.identities.CreateUser(todo.User{Name: name, Username: username, Email: email}, provider.Name(), claims.Subject)
+}
+
+// Имя пользователя из preferred_username или почты. Если оно занято, добавляется случайный суффикс
+func (s *OIDCService) freeUsername(providerName string, claims oidc.Claims) (string, error) {
+	base := firstNonEmpty(claims.PreferredUsername, strings.SplitN(claims.Email, "@", 2)[0], providerName+"-"+claims.Subject)
+	if runes := []rune(base); len(runes) > 200 { // по символам, чтобы не разрезать многобайтовый символ
+		base = string(runes[:200])
+	}
+
+	candidate := base
+	for i := 0; i < usernameAttempts; i++ {
+		_, err := s.authRepo.GetUser(candidate)
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+
+	return "", errors.New("failed to pick a free username")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
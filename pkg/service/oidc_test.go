@@ -0,0 +1,225 @@
+package service
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/oidc"
+	"todo-app/pkg/oidc/oidctest"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// Связи с учетными записями провайдеров в памяти, новые пользователи добавляются в authRepoStub
+type identitiesStub struct {
+	users *authRepoStub
+	links map[string]int // provider/subject -> id пользователя
+}
+
+func (r *identitiesStub) GetUserId(provider, subject string) (int, error) {
+	userId, ok := r.links[provider+"/"+subject]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+	return userId, nil
+}
+
+func (r *identitiesStub) Link(userId int, provider, subject, email string) error {
+	r.links[provider+"/"+subject] = userId
+	return nil
+}
+
+func (r *identitiesStub) CreateUser(user todo.User, provider, subject string) (int, error) {
+	user.Id = 100 + len(r.users.users)
+	user.EmailVerified = user.Email != ""
+	r.users.users[user.Username] = user
+	return user.Id, r.Link(user.Id, provider, subject, user.Email)
+}
+
+type oidcStatesStub map[string]todo.OIDCLogin
+
+func (s oidcStatesStub) Save(state string, login todo.OIDCLogin, ttl time.Duration) error {
+	s[state] = login
+	return nil
+}
+
+func (s oidcStatesStub) Take(state string) (todo.OIDCLogin, error) {
+	login, ok := s[state]
+	if !ok {
+		return todo.OIDCLogin{}, redis.Nil
+	}
+	delete(s, state)
+	return login, nil
+}
+
+type oidcTestEnv struct {
+	service    *OIDCService
+	issuer     *oidctest.Issuer
+	users      *authRepoStub
+	identities *identitiesStub
+}
+
+func newOIDCTestEnv(t *testing.T, autoProvision bool) *oidcTestEnv {
+	issuer, server, err := oidctest.NewServer("todo-app", "")
+	if err != nil {
+		t.Fatalf("failed to start issuer: %s", err.Error())
+	}
+	t.Cleanup(server.Close)
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Name:          "corp",
+		Issuer:        issuer.URL,
+		ClientId:      "todo-app",
+		RedirectURL:   "http://todo.test/auth/oidc/callback",
+		AutoProvision: autoProvision,
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create provider: %s", err.Error())
+	}
+
+	users := &authRepoStub{users: map[string]todo.User{}, updated: map[int]string{}}
+	identities := &identitiesStub{users: users, links: map[string]int{}}
+	auth := NewAuthService(users, &sessionsRepoStub{sessionId: 3}, &twoFactorRepoStub{}, sessionsCachStub{}, nil, nil,
+		newTestKeys(t), ConfigAuth{})
+
+	return &oidcTestEnv{
+		service:    NewOIDCService(auth, users, identities, oidcStatesStub{}, ConfigOIDC{Providers: []*oidc.Provider{provider}}),
+		issuer:     issuer,
+		users:      users,
+		identities: identities,
+	}
+}
+
+// Полный вход: страница провайдера и возврат на callback, возвращает id вошедшего пользователя
+func (e *oidcTestEnv) login(t *testing.T) (int, error) {
+	authURL, err := e.service.LoginURL("corp", "laptop")
+	if err != nil {
+		t.Fatalf("failed to start login: %s", err.Error())
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorize request failed: %s", err.Error())
+	}
+	resp.Body.Close()
+	callback, _ := url.Parse(resp.Header.Get("Location"))
+
+	tokens, err := e.service.Callback(todo.OIDCCallbackInput{
+		State: callback.Query().Get("state"),
+		Code:  callback.Query().Get("code"),
+	}, todo.SessionClient{Ip: "192.0.2.1"})
+	if err != nil {
+		return 0, err
+	}
+
+	userId, _, err := e.service.auth.ParseToken(tokens.AccessToken)
+	assert.NoError(t, err)
+	return userId, nil
+}
+
+func TestOIDCService_AutoProvision(t *testing.T) {
+	env := newOIDCTestEnv(t, true)
+	env.users.users["user"] = todo.User{Id: 1, Username: "user"} // имя из preferred_username занято
+
+	userId, err := env.login(t)
+	assert.NoError(t, err)
+
+	var created todo.User
+	for _, user := range env.users.users {
+		if user.Id == userId {
+			created = user
+		}
+	}
+	assert.Equal(t, "Test User", created.Name)
+	assert.Regexp(t, `^user-[0-9a-f]{6}$`, created.Username)
+	assert.Equal(t, "user@example.com", created.Email)
+
+	// Повторный вход - тот же пользователь
+	again, err := env.login(t)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, again)
+	assert.Len(t, env.users.users, 2)
+}
+
+func TestOIDCService_LinkByVerifiedEmail(t *testing.T) {
+	testTable := []struct {
+		name          string
+		user          todo.User
+		autoProvision bool
+		wantLinked    bool
+		wantErr       error
+	}{
+		{
+			name:       "Verified Email",
+			user:       todo.User{Id: 7, Username: "alex", Email: "user@example.com", EmailVerified: true},
+			wantLinked: true,
+		},
+		{
+			name:    "Unverified Email",
+			user:    todo.User{Id: 7, Username: "alex", Email: "user@example.com"},
+			wantErr: ErrOIDCAccountNotLinked,
+		},
+		{
+			name:          "Unverified Email Auto Provision",
+			user:          todo.User{Id: 7, Username: "alex", Email: "user@example.com"},
+			autoProvision: true,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t, testCase.autoProvision)
+			env.users.users[testCase.user.Username] = testCase.user
+
+			userId, err := env.login(t)
+			if testCase.wantErr != nil {
+				assert.ErrorIs(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantLinked, userId == 7)
+			if !testCase.wantLinked { // новый пользователь создан без чужой почты
+				assert.Empty(t, env.users.users["user"].Email)
+			}
+		})
+	}
+}
+
+func TestOIDCService_Callback_Errors(t *testing.T) {
+	env := newOIDCTestEnv(t, true)
+
+	_, err := env.service.Callback(todo.OIDCCallbackInput{State: "unknown", Code: "code"}, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+
+	_, err = env.service.LoginURL("other", "")
+	assert.ErrorIs(t, err, ErrUnknownOIDCProvider)
+
+	// Пользователь отказался от входа на странице провайдера
+	authURL, err := env.service.LoginURL("corp", "")
+	assert.NoError(t, err)
+	u, _ := url.Parse(authURL)
+	state := u.Query().Get("state")
+
+	_, err = env.service.Callback(todo.OIDCCallbackInput{State: state, Error: "access_denied"}, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrOIDCLoginFailed)
+
+	// state одноразовый
+	_, err = env.service.Callback(todo.OIDCCallbackInput{State: state, Code: "code"}, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+}
+
+func TestOIDCService_freeUsername_Long(t *testing.T) {
+	s := &OIDCService{authRepo: &authRepoStub{users: map[string]todo.User{}}}
+
+	username, err := s.freeUsername("test", oidc.Claims{Subject: "1", PreferredUsername: strings.Repeat("я", 300)})
+	assert.NoError(t, err)
+	assert.True(t, utf8.ValidString(username))
+	assert.Equal(t, strings.Repeat("я", 200), username)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+	"todo-app"
+	"todo-app/pkg/notifier"
+	"todo-app/pkg/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ConfigScheduler struct {
+	Interval    time.Duration // период опроса БД
+	BatchSize   int           // сколько напоминаний захватывать за один проход
+	MaxAttempts int           // после стольких неудачных попыток напоминание больше не отправляется
+}
+
+// Фоновая отправка напоминаний. Каналы доставки передаются в notifiers по имени канала (todo.ReminderChannel...)
+type ReminderScheduler struct {
+	repo      repository.Reminders
+	notifiers map[string]notifier.Notifier
+	cfg       ConfigScheduler
+}
+
+func NewReminderScheduler(repo repository.Reminders, notifiers map[string]notifier.Notifier, cfg ConfigScheduler) *ReminderScheduler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+
+	return &ReminderScheduler{repo: repo, notifiers: notifiers, cfg: cfg}
+}
+
+// Запуск цикла отправки, завершается при отмене ctx
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Один проход: захват подошедших напоминаний и их отправка.
+// Неотправленные напоминания возвращаются в очередь и повторяются на следующем проходе
+func (s *ReminderScheduler) Tick(ctx context.Context) {
+	reminders, err := s.repo.ClaimDue(s.cfg.BatchSize)
+	if err != nil {
+		logrus.Errorf("failed to claim reminders: %s", err.Error())
+		return
+	}
+
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			// Оставшиеся уже захвачены, возвращаем их в очередь
+			s.fail(reminder, ctx.Err().Error(), true)
+			continue
+		}
+		s.send(ctx, reminder)
+	}
+}
+
+func (s *ReminderScheduler) send(ctx context.Context, reminder todo.DueReminder) {
+	n, ok := s.notifiers[reminder.Channel]
+	if !ok {
+		s.fail(reminder, "notifier for channel "+reminder.Channel+" is not configured", false)
+		return
+	}
+
+	err := n.Notify(ctx, notifier.Notification{
+		ReminderId:  reminder.Id,
+		UserId:      reminder.UserId,
+		ItemId:      reminder.ItemId,
+		Title:       reminder.ItemTitle,
+		Description: reminder.ItemDescription,
+		DueDate:     reminder.DueDate,
+		Target:      reminder.Target,
+	})
+	if err != nil {
+		s.fail(reminder, err.Error(), reminder.Attempts < s.cfg.MaxAttempts)
+	}
+}
+
+func (s *ReminderScheduler) fail(reminder todo.DueReminder, message string, retry bool) {
+	logrus.Errorf("failed to send reminder %d: %s", reminder.Id, message)
+
+	if err := s.repo.Fail(reminder.Id, message, retry); err != nil {
+		logrus.Errorf("failed to save reminder %d error: %s", reminder.Id, err.Error())
+	}
+}
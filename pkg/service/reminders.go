@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+type RemindersService struct {
+	repo     repository.Reminders
+	itemRepo repository.TodoItem
+}
+
+func NewRemindersService(repo repository.Reminders, itemRepo repository.TodoItem) *RemindersService {
+	return &RemindersService{repo: repo, itemRepo: itemRepo}
+}
+
+func (s *RemindersService) Create(userId, itemId int, reminder todo.Reminder) (int, error) {
+	reminder.Target = strings.TrimSpace(reminder.Target)
+	if err := reminder.Validate(); err != nil {
+		return 0, err
+	}
+
+	item, err := s.itemRepo.GetById(userId, itemId)
+	if err != nil {
+		// item does not exists or does not belongs to user
+		return 0, err
+	}
+
+	// Смещение отсчитывается от срока выполнения, поэтому срок должен быть задан
+	if reminder.OffsetMinutes != nil && item.DueDate == nil {
+		return 0, errors.New("offset reminder requires item with due date")
+	}
+
+	return s.repo.Create(userId, itemId, reminder)
+}
+
+func (s *RemindersService) GetByItem(userId, itemId int) ([]todo.Reminder, error) {
+	return s.repo.GetByItem(userId, itemId)
+}
+
+func (s *RemindersService) Delete(userId, reminderId int) error {
+	return s.repo.Delete(userId, reminderId)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"strings"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+type SearchService struct {
+	repo repository.Search
+}
+
+func NewSearchService(repo repository.Search) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	query.Query = strings.TrimSpace(query.Query)
+	if query.Query == "" {
+		return []todo.SearchResult{}, nil
+	}
+
+	if query.Limit == 0 {
+		query.Limit = todo.SearchDefaultLimit
+	}
+	if query.Limit > todo.SearchMaxLimit {
+		query.Limit = todo.SearchMaxLimit
+	}
+
+	return s.repo.Search(userId, query)
+}
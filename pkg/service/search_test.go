@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Возвращает запрос, с которым был вызван
+type searchRepoStub struct{}
+
+func (r searchRepoStub) Search(userId int, query todo.SearchQuery) ([]todo.SearchResult, error) {
+	return []todo.SearchResult{{Id: userId, Title: query.Query, ListId: query.Limit}}, nil
+}
+
+func TestSearchService_Search(t *testing.T) {
+	s := NewSearchService(searchRepoStub{})
+
+	testTable := []struct {
+		name  string
+		query todo.SearchQuery
+		want  []todo.SearchResult
+	}{
+		{name: "Default Limit", query: todo.SearchQuery{Query: " milk "},
+			want: []todo.SearchResult{{Id: 1, Title: "milk", ListId: todo.SearchDefaultLimit}}},
+		{name: "Max Limit", query: todo.SearchQuery{Query: "milk", Limit: 1000},
+			want: []todo.SearchResult{{Id: 1, Title: "milk", ListId: todo.SearchMaxLimit}}},
+		{name: "Blank Query", query: todo.SearchQuery{Query: "  "}, want: []todo.SearchResult{}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := s.Search(1, testCase.query)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"todo-app"
+	"todo-app/pkg/jwtkeys"
 	"todo-app/pkg/repository"
 )
 
@@ -10,24 +11,175 @@ import (
 
 type Authorization interface {
 	CreateUser(user todo.User) (int, error)
-	GenerateToken(username, password string) (string, error)
-	ParseToken(token string) (int, error)
+	// Вход по паролю, создает новую сессию устройства client
+	// При включенной 2FA возвращает *TwoFactorRequiredError
+	GenerateToken(username, password string, client todo.SessionClient) (todo.Tokens, error)
+	// Второй шаг входа с кодом 2FA
+	VerifyTwoFactor(challengeToken, code string, client todo.SessionClient) (todo.Tokens, error)
+	// Обмен refresh-токена на новую пару токенов, ip - адрес клиента
+	RefreshToken(refreshToken, ip string) (todo.Tokens, error)
+	Logout(userId, sessionId int) error
+	// Отзыв всех сессий пользователя
+	LogoutAll(userId int) error
+	// Возвращает id пользователя и id сессии
+	ParseToken(token string) (int, int, error)
+	// Возвращает признак администратора, для отключенного пользователя - ErrAccountDisabled
+	CheckUser(userId int) (bool, error)
+	// Открытые ключи подписи токенов (JWKS)
+	JWKS() jwtkeys.JWKS
 }
 
 type TodoList interface {
 	Create(userId int, list todo.TodoList) (int, error)
-	GetAll(userId int) ([]todo.TodoList, error)
+	// Возвращает курсор следующей страницы ("" - страница последняя)
+	GetAll(userId int, query todo.ListsQuery) ([]todo.TodoList, string, error)
 	GetById(userId, listId int) (todo.TodoList, error)
 	DeleteById(userId, listId int) error
 	UpdateById(userId, listId int, list todo.UpdateListInput) (todo.TodoList, error)
+	// Ручная сортировка: список ставится перед или после другого списка пользователя
+	Move(userId, listId int, input todo.MoveInput) error
+	GetMembers(userId, listId int) ([]todo.ListMember, error)
+	// Добавление участника или изменение его роли (только владелец), возвращает id участника
+	AddMember(userId, listId int, input todo.AddMemberInput) (int, error)
+	// Исключение участника или выход из списка, возвращает id исключенного пользователя
+	RemoveMember(userId, listId int, username string) (int, error)
 }
 
 type TodoItem interface {
 	Create(userId, listId int, item todo.TodoItem) (int, error)
-	GetAll(userId, listId int) ([]todo.TodoItem, error)
+	// sort - порядок выдачи (todo.ItemSortDefault или todo.ItemSortPriority)
+	// Возвращает курсор следующей страницы ("" - страница последняя)
+	GetAll(userId, listId int, query todo.ItemsQuery) ([]todo.TodoItem, string, error)
+	// Если listId использовать не нужно (поиск по всем спискам), передать -1
+	GetOverdue(userId, listId int) ([]todo.TodoItem, error)
 	GetById(userId, itemId int) (todo.TodoItem, error)
 	Delete(userId, itemId int) error
 	Update(userId, itemId int, input todo.UpdateItemInput) error
+	// Ручная сортировка: задача ставится перед или после другой задачи того же списка
+	Move(userId, listId, itemId int, input todo.MoveInput) error
+	// Перенос задачи с подзадачами в другой список пользователя
+	MoveToList(userId, itemId, listId int) error
+	// Копирование задачи с подзадачами и метками в список пользователя, возвращает id копии
+	CopyToList(userId, itemId, listId int) (int, error)
+}
+
+type Tags interface {
+	Create(userId int, tag todo.Tag) (int, error)
+	GetAll(userId int) ([]todo.Tag, error)
+	GetById(userId, tagId int) (todo.Tag, error)
+	Update(userId, tagId int, input todo.UpdateTagInput) error
+	Delete(userId, tagId int) error
+	Attach(userId, itemId, tagId int) error
+	Detach(userId, itemId, tagId int) error
+	GetByItem(userId, itemId int) ([]todo.Tag, error)
+	// names - имена меток через запятую; matchAll - задача должна иметь все метки, иначе хотя бы одну
+	GetItems(userId int, names string, matchAll bool) ([]todo.TodoItem, error)
+}
+
+type Reminders interface {
+	Create(userId, itemId int, reminder todo.Reminder) (int, error)
+	GetByItem(userId, itemId int) ([]todo.Reminder, error)
+	Delete(userId, reminderId int) error
+}
+
+type Invitations interface {
+	// Для приглашения по ссылке (пустой username) возвращает токен ссылки
+	Create(userId, listId int, input todo.CreateInvitationInput) (int, string, error)
+	GetByList(userId, listId int) ([]todo.Invitation, error)
+	// Действующие приглашения, адресованные пользователю
+	GetPending(userId int) ([]todo.Invitation, error)
+	Revoke(userId, listId, invitationId int) error
+	// Принятие приглашения возвращает id списка, в который добавлен пользователь
+	Accept(userId, invitationId int) (int, error)
+	AcceptToken(userId int, token string) (int, error)
+	Decline(userId, invitationId int) error
+}
+
+type Workspaces interface {
+	Create(userId int, workspace todo.Workspace) (int, error)
+	GetAll(userId int) ([]todo.Workspace, error)
+	GetLists(userId, workspaceId int) ([]todo.TodoList, error)
+	GetMembers(userId, workspaceId int) ([]todo.ListMember, error)
+	// Добавление участника или изменение его роли (только владелец), возвращает id участника
+	AddMember(userId, workspaceId int, input todo.AddMemberInput) (int, error)
+	// Исключение участника или выход из пространства, возвращает id исключенного пользователя
+	RemoveMember(userId, workspaceId int, username string) (int, error)
+}
+
+type Sessions interface {
+	// Действующие сессии пользователя, currentSessionId - сессия запроса
+	GetAll(userId, currentSessionId int) ([]todo.Session, error)
+	Revoke(userId, sessionId int) error
+}
+
+type AccessTokens interface {
+	// Возвращает id и сам токен (показывается один раз)
+	Create(userId int, input todo.CreateAccessTokenInput) (int, string, error)
+	GetAll(userId int) ([]todo.AccessToken, error)
+	Revoke(userId, tokenId int) error
+	// Возвращает id пользователя и области доступа токена
+	Authenticate(token string) (int, []string, error)
+}
+
+type TwoFactor interface {
+	Enroll(userId int) (todo.TwoFactorEnrollment, error)
+	// Подтверждение первым кодом, возвращает коды восстановления
+	Confirm(userId int, code string) ([]string, error)
+	Disable(userId int, code string) error
+}
+
+type Account interface {
+	Get(userId int) (todo.Profile, error)
+	Update(userId int, input todo.UpdateUserInput) (todo.Profile, error)
+	// Смена пароля, все сессии пользователя кроме sessionId отзываются.
+	// Если sessionId использовать не нужно, передать -1
+	ChangePassword(userId, sessionId int, input todo.ChangePasswordInput) error
+	// Удаление пользователя, возвращает id пользователей, чей кэш нужно сбросить
+	Delete(userId int, password string) ([]int, error)
+}
+
+type Archive interface {
+	// Архив профиля, меток и списков пользователя с задачами и напоминаниями
+	Export(userId int) (todo.Archive, error)
+	// Импорт архива одной транзакцией, при неверном архиве возвращает ErrInvalidArchive
+	Import(userId int, archive todo.Archive) (todo.ImportResult, error)
+}
+
+type Admin interface {
+	GetUsers(query todo.AdminUsersQuery) ([]todo.AdminUser, error)
+	GetUser(userId int) (todo.AdminUser, error)
+	// Действия администратора adminId над пользователем userId записываются в журнал аудита, ip - адрес администратора
+	Disable(adminId, userId int, input todo.AdminActionInput, ip string) error
+	Enable(adminId, userId int, input todo.AdminActionInput, ip string) error
+	// Возвращает признак отправки письма со ссылкой для нового пароля
+	ResetPassword(adminId, userId int, input todo.AdminActionInput, ip string) (bool, error)
+	// Токены сессии пользователя для поддержки
+	Impersonate(adminId, userId int, input todo.ImpersonateInput, client todo.SessionClient) (todo.Tokens, error)
+}
+
+type Search interface {
+	// Пустой после обрезки пробелов запрос - пустой результат
+	Search(userId int, query todo.SearchQuery) ([]todo.SearchResult, error)
+}
+
+type AccountEmail interface {
+	// Письмо со ссылкой подтверждения адреса
+	SendVerification(userId int) error
+	Verify(token string) error
+	// Письмо со ссылкой сброса пароля, для неизвестного адреса тоже возвращает nil
+	ForgotPassword(email string) error
+	// Новый пароль по токену из письма, все сессии пользователя отзываются
+	ResetPassword(token, password string) error
+}
+
+type OIDC interface {
+	// Имена настроенных провайдеров
+	Providers() []string
+	// Адрес страницы входа провайдера
+	LoginURL(provider, deviceName string) (string, error)
+	// Возврат со страницы провайдера: проверка входа, связывание или создание пользователя и выдача токенов.
+	// При включенной 2FA возвращает *TwoFactorRequiredError
+	Callback(input todo.OIDCCallbackInput, client todo.SessionClient) (todo.Tokens, error)
 }
 
 type TodoListCach interface {
@@ -35,7 +187,9 @@ type TodoListCach interface {
 	HGet(userId, listId int) (string, error)
 	// Если listId использовать не нужно, передать -1
 	HSet(userId, listId int, data string) error
+	// Удаляет lists пользователя и всех участников его общих списков
 	HDelete(userId int) error
+	// Удаляет данные пользователя и всех участников его общих списков
 	Delete(userId int) error
 }
 
@@ -44,7 +198,9 @@ type TodoItemCach interface {
 	HGet(userId, listId, itemId int) (string, error)
 	// Если listId или itemId использовать не нужно, передать -1 (что то одно)
 	HSet(userId, listId, itemId int, data string) error
+	// Удаляет items:listId у всех участников списка
 	HDelete(userId, listId int) error
+	// Удаляет данные пользователя и всех участников его общих списков
 	Delete(userId int) error
 }
 
@@ -52,16 +208,50 @@ type Service struct {
 	Authorization
 	TodoList
 	TodoItem
+	Tags
+	Reminders
+	Invitations
+	Workspaces
+	Sessions
+	AccessTokens
+	TwoFactor
+	Account
+	Archive
+	Admin
+	Search
+	AccountEmail
+	OIDC
 	TodoListCach
 	TodoItemCach
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, keys *jwtkeys.KeySet, authCfg ConfigAuth, mailCfg ConfigMail,
+	oidcCfg ConfigOIDC) *Service {
+	auth := NewAuthService(repos.Authorization, repos.Sessions, repos.TwoFactor, repos.SessionsCach,
+		repos.LoginAttempts, repos.Audit, keys, authCfg)
+	accountEmail := NewAccountEmailService(repos.Authorization, repos.EmailTokens, repos.Sessions, repos.SessionsCach,
+		mailCfg, authCfg)
+
 	return &Service{
-		Authorization: NewAuthService(repos.Authorization),
-		TodoList:      NewTodoListService(repos.TodoList),
+		Authorization: auth,
+		TodoList:      NewTodoListService(repos.TodoList, repos.Workspaces),
 		TodoItem:      NewTodoItemService(repos.TodoItem, repos.TodoList),
-		TodoListCach:  NewTodoListServiceCach(repos.TodoListCach),
-		TodoItemCach:  NewTodoItemServiceCach(repos.TodoItemCach),
+		Tags:          NewTagsService(repos.Tags, repos.TodoItem),
+		Reminders:     NewRemindersService(repos.Reminders, repos.TodoItem),
+		Invitations:   NewInvitationsService(repos.Invitations, repos.TodoList),
+		Workspaces:    NewWorkspacesService(repos.Workspaces),
+		Sessions:      NewSessionsService(repos.Sessions, repos.SessionsCach, authCfg),
+		AccessTokens:  NewAccessTokensService(repos.AccessTokens),
+		TwoFactor:     NewTwoFactorService(repos.TwoFactor, authCfg),
+		Account: NewAccountService(auth, repos.Authorization, repos.TodoList, repos.Sessions, repos.SessionsCach,
+			repos.AccessTokens, authCfg),
+		Archive: NewArchiveService(repos.Archive, repos.Authorization),
+		Admin: NewAdminService(auth, accountEmail, repos.Admin, repos.Authorization, repos.Sessions, repos.SessionsCach,
+			repos.Audit, authCfg),
+		Search:       NewSearchService(repos.Search),
+		AccountEmail: accountEmail,
+		OIDC:         NewOIDCService(auth, repos.Authorization, repos.Identities, repos.OIDCStates, oidcCfg),
+		TodoListCach: NewTodoListServiceCach(repos.TodoListCach, repos.TodoList),
+		TodoItemCach: NewTodoItemServiceCach(repos.TodoItemCach, repos.TodoList),
 	}
 }
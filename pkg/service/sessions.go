@@ -0,0 +1,68 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+// Ограничения длины полей сессии (как в таблице sessions)
+const (
+	maxDeviceNameLength = 100
+	maxUserAgentLength  = 512
+)
+
+// Сессия не найдена, уже завершена или принадлежит другому пользователю
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionsService struct {
+	repo    repository.Sessions
+	revoked repository.SessionsCach
+	cfg     ConfigAuth
+}
+
+func NewSessionsService(repo repository.Sessions, revoked repository.SessionsCach, cfg ConfigAuth) *SessionsService {
+	return &SessionsService{repo: repo, revoked: revoked, cfg: cfg.withDefaults()}
+}
+
+func (s *SessionsService) GetAll(userId, currentSessionId int) ([]todo.Session, error) {
+	sessions, err := s.repo.GetAll(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == currentSessionId
+	}
+
+	return sessions, nil
+}
+
+// Завершение сессии на устройстве: access-токены сессии отклоняются middleware userIdentity
+func (s *SessionsService) Revoke(userId, sessionId int) error {
+	if err := s.repo.Revoke(userId, sessionId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	return s.revoked.Revoke([]int{sessionId}, s.cfg.AccessTTL)
+}
+
+// Обрезка сведений об устройстве до размеров полей таблицы
+func truncateClient(client todo.SessionClient) todo.SessionClient {
+	client.DeviceName = truncateString(client.DeviceName, maxDeviceNameLength)
+	client.UserAgent = truncateString(client.UserAgent, maxUserAgentLength)
+	return client
+}
+
+// Обрезка строки до max символов (не байт)
+func truncateString(str string, max int) string {
+	runes := []rune(str)
+	if len(runes) <= max {
+		return str
+	}
+	return string(runes[:max])
+}
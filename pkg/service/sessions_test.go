@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"todo-app"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionsService_GetAll(t *testing.T) {
+	s := NewSessionsService(&sessionsRepoStub{sessionId: 3}, sessionsCachStub{}, ConfigAuth{})
+
+	sessions, err := s.GetAll(1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []todo.Session{{Id: 3, Current: true}, {Id: 4}}, sessions)
+}
+
+func TestSessionsService_Revoke(t *testing.T) {
+	sessions := &sessionsRepoStub{}
+	revoked := sessionsCachStub{}
+	s := NewSessionsService(sessions, revoked, ConfigAuth{})
+
+	assert.NoError(t, s.Revoke(1, 4))
+	assert.Equal(t, []int{4}, sessions.revoked)
+	assert.True(t, revoked[4])
+}
+
+func TestTruncateClient(t *testing.T) {
+	client := truncateClient(todo.SessionClient{
+		DeviceName: string(make([]rune, 150)),
+		UserAgent:  "okhttp/4.9",
+	})
+
+	assert.Equal(t, maxDeviceNameLength, len([]rune(client.DeviceName)))
+	assert.Equal(t, "okhttp/4.9", client.UserAgent)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+type TagsService struct {
+	repo     repository.Tags
+	itemRepo repository.TodoItem
+}
+
+func NewTagsService(repo repository.Tags, itemRepo repository.TodoItem) *TagsService {
+	return &TagsService{repo: repo, itemRepo: itemRepo}
+}
+
+func (s *TagsService) Create(userId int, tag todo.Tag) (int, error) {
+	tag.Name = strings.TrimSpace(tag.Name)
+	if err := tag.Validate(); err != nil {
+		return 0, err
+	}
+	return s.repo.Create(userId, tag)
+}
+
+func (s *TagsService) GetAll(userId int) ([]todo.Tag, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *TagsService) GetById(userId, tagId int) (todo.Tag, error) {
+	return s.repo.GetById(userId, tagId)
+}
+
+func (s *TagsService) Update(userId, tagId int, input todo.UpdateTagInput) error {
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		input.Name = &name
+	}
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(userId, tagId, input)
+}
+
+func (s *TagsService) Delete(userId, tagId int) error {
+	return s.repo.Delete(userId, tagId)
+}
+
+func (s *TagsService) Attach(userId, itemId, tagId int) error {
+	if _, err := s.itemRepo.GetById(userId, itemId); err != nil {
+		// item does not exists or does not belongs to user
+		return err
+	}
+
+	if _, err := s.repo.GetById(userId, tagId); err != nil {
+		// tag does not exists or does not belongs to user
+		return err
+	}
+
+	return s.repo.Attach(itemId, tagId)
+}
+
+func (s *TagsService) Detach(userId, itemId, tagId int) error {
+	return s.repo.Detach(userId, itemId, tagId)
+}
+
+func (s *TagsService) GetByItem(userId, itemId int) ([]todo.Tag, error) {
+	return s.repo.GetByItem(userId, itemId)
+}
+
+// names - имена меток через запятую, как они пришли в параметре запроса
+func (s *TagsService) GetItems(userId int, names string, matchAll bool) ([]todo.TodoItem, error) {
+	list := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		list = append(list, name)
+	}
+
+	if len(list) == 0 {
+		return nil, errors.New("no tags to search")
+	}
+
+	return s.repo.GetItems(userId, list, matchAll)
+}
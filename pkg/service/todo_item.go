@@ -1,7 +1,11 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"time"
 	"todo-app"
+	"todo-app/pkg/recurrence"
 	"todo-app/pkg/repository"
 )
 
@@ -15,17 +19,58 @@ func NewTodoItemService(repo repository.TodoItem, listRepo repository.TodoList)
 }
 
 func (s *TodoItemService) Create(userId, listId int, item todo.TodoItem) (int, error) {
-	_, err := s.listRepo.GetById(userId, listId)
-	if err != nil {
-		// list does not exists or does not belongs to user
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleEditor); err != nil {
 		return 0, err
 	}
 
+	if !item.Priority.IsValid() {
+		return 0, fmt.Errorf("invalid priority: %d", int(item.Priority))
+	}
+
+	if item.ParentId != nil {
+		if err := s.checkParent(userId, listId, *item.ParentId); err != nil {
+			return 0, err
+		}
+	}
+
+	if item.DueDate != nil {
+		due, err := todo.NormalizeDueDate(*item.DueDate, item.DueAllDay, item.DueTimezone)
+		if err != nil {
+			return 0, err
+		}
+		item.DueDate = &due
+	} else {
+		item.DueAllDay, item.DueTimezone = false, "" // без срока эти поля не имеют смысла
+	}
+
+	if item.Recurrence != "" {
+		if item.DueDate == nil {
+			return 0, errors.New("recurring item requires due date")
+		}
+
+		rule, err := recurrence.Parse(item.Recurrence)
+		if err != nil {
+			return 0, err
+		}
+		item.Recurrence = rule.String()
+	}
+	item.Occurrence = 1
+
 	return s.repo.Create(listId, item)
 }
 
-func (s *TodoItemService) GetAll(userId, listId int) ([]todo.TodoItem, error) {
-	return s.repo.GetAll(userId, listId)
+func (s *TodoItemService) GetAll(userId, listId int, query todo.ItemsQuery) ([]todo.TodoItem, string, error) {
+	if err := query.Validate(); err != nil {
+		return nil, "", err
+	}
+	if query.Limit > todo.PageMaxLimit {
+		query.Limit = todo.PageMaxLimit
+	}
+	return s.repo.GetAll(userId, listId, query)
+}
+
+func (s *TodoItemService) GetOverdue(userId, listId int) ([]todo.TodoItem, error) {
+	return s.repo.GetOverdue(userId, listId)
 }
 
 func (s *TodoItemService) GetById(userId, itemId int) (todo.TodoItem, error) {
@@ -33,9 +78,235 @@ func (s *TodoItemService) GetById(userId, itemId int) (todo.TodoItem, error) {
 }
 
 func (s *TodoItemService) Delete(userId, itemId int) error {
+	if err := s.checkItemRole(userId, itemId, todo.ListRoleEditor); err != nil {
+		return err
+	}
 	return s.repo.Delete(userId, itemId)
 }
 
 func (s *TodoItemService) Update(userId, itemId int, input todo.UpdateItemInput) error {
-	return s.repo.Update(userId, itemId, input)
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.checkItemRole(userId, itemId, todo.ListRoleEditor); err != nil {
+		return err
+	}
+
+	if input.DueDate != nil || input.DueAllDay != nil || input.DueTimezone != nil {
+		if err := s.normalizeDueInput(userId, itemId, &input); err != nil {
+			return err
+		}
+	}
+
+	if input.ParentId != nil && *input.ParentId != 0 {
+		if err := s.checkMove(userId, itemId, *input.ParentId); err != nil {
+			return err
+		}
+	}
+
+	if input.Recurrence != nil && *input.Recurrence != "" {
+		if err := s.checkRecurrence(userId, itemId, &input); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Update(userId, itemId, input); err != nil {
+		return err
+	}
+
+	if input.Done != nil && *input.Done {
+		return s.createNextOccurrence(userId, itemId)
+	}
+
+	return nil
+}
+
+func (s *TodoItemService) Move(userId, listId, itemId int, input todo.MoveInput) error {
+	if err := input.Validate(itemId); err != nil {
+		return err
+	}
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleEditor); err != nil {
+		return err
+	}
+	return s.repo.Move(userId, listId, itemId, input)
+}
+
+// Задача удаляется из исходного списка и добавляется в целевой, поэтому нужны права редактора в обоих
+func (s *TodoItemService) MoveToList(userId, itemId, listId int) error {
+	if err := s.checkItemRole(userId, itemId, todo.ListRoleEditor); err != nil {
+		return err
+	}
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleEditor); err != nil {
+		return err
+	}
+	return s.repo.MoveToList(userId, itemId, listId)
+}
+
+// Копировать можно из любого доступного списка, а добавлять - только в список, где есть права редактора
+func (s *TodoItemService) CopyToList(userId, itemId, listId int) (int, error) {
+	if err := checkListRole(s.listRepo, userId, listId, todo.ListRoleEditor); err != nil {
+		return 0, err
+	}
+	return s.repo.CopyToList(userId, itemId, listId)
+}
+
+// Проверка роли пользователя в списке, которому принадлежит задача
+func (s *TodoItemService) checkItemRole(userId, itemId int, required todo.ListRole) error {
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		// item does not exists or does not belongs to user
+		return err
+	}
+	return checkListRole(s.listRepo, userId, item.ListId, required)
+}
+
+func (s *TodoItemService) checkRecurrence(userId, itemId int, input *todo.UpdateItemInput) error {
+	rule, err := recurrence.Parse(*input.Recurrence)
+	if err != nil {
+		return err
+	}
+	normalized := rule.String()
+	input.Recurrence = &normalized
+
+	if input.DueDate != nil {
+		return nil
+	}
+
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		return err
+	}
+	if item.DueDate == nil || input.RemoveDueDate {
+		return errors.New("recurring item requires due date")
+	}
+
+	return nil
+}
+
+// При выполнении повторяющейся задачи в том же списке создается ее следующее повторение.
+// Правило повторения переходит к новой задаче, поэтому повторная отметка выполненной
+// задачи (или снятие и установка отметки) не порождает дубликатов
+func (s *TodoItemService) createNextOccurrence(userId, itemId int) error {
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if item.Recurrence == "" || item.DueDate == nil {
+		return nil
+	}
+
+	rule, err := recurrence.Parse(item.Recurrence)
+	if err != nil {
+		return err
+	}
+
+	// Повторения считаются по "настенному" времени в поясе задачи, чтобы переживать переходы на летнее время
+	current := *item.DueDate
+	if item.DueTimezone != "" {
+		loc, err := time.LoadLocation(item.DueTimezone)
+		if err != nil {
+			return err
+		}
+		current = current.In(loc)
+	}
+
+	noRecurrence := ""
+	next, ok := rule.Next(current, item.Occurrence)
+	if !ok { // серия закончилась
+		return s.repo.Update(userId, itemId, todo.UpdateItemInput{Recurrence: &noRecurrence})
+	}
+
+	_, err = s.repo.Create(item.ListId, todo.TodoItem{
+		Title:       item.Title,
+		Description: item.Description,
+		DueDate:     &next,
+		DueAllDay:   item.DueAllDay,
+		DueTimezone: item.DueTimezone,
+		Priority:    item.Priority,
+		ParentId:    item.ParentId,
+		Recurrence:  item.Recurrence,
+		Occurrence:  item.Occurrence + 1,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Update(userId, itemId, todo.UpdateItemInput{Recurrence: &noRecurrence})
+}
+
+// Родительская задача должна принадлежать пользователю и находиться в том же списке
+func (s *TodoItemService) checkParent(userId, listId, parentId int) error {
+	parent, err := s.repo.GetById(userId, parentId)
+	if err != nil {
+		// parent does not exists or does not belongs to user
+		return err
+	}
+
+	if parent.ListId != listId {
+		return errors.New("parent item belongs to another list")
+	}
+
+	return nil
+}
+
+// Перенос задачи под нового родителя не должен образовывать цикл:
+// родителем не может быть сама задача или любая из ее подзадач
+func (s *TodoItemService) checkMove(userId, itemId, parentId int) error {
+	if parentId == itemId {
+		return errors.New("item cannot be its own parent")
+	}
+
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkParent(userId, item.ListId, parentId); err != nil {
+		return err
+	}
+
+	subtree, err := s.repo.GetSubtreeIds(userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range subtree {
+		if id == parentId {
+			return errors.New("parent item is a subtask of the item")
+		}
+	}
+
+	return nil
+}
+
+// Срок выполнения хранится согласованно (дата, признак "весь день" и пояс),
+// поэтому при частичном обновлении недостающие поля берутся из текущей задачи
+func (s *TodoItemService) normalizeDueInput(userId, itemId int, input *todo.UpdateItemInput) error {
+	item, err := s.repo.GetById(userId, itemId)
+	if err != nil {
+		return err
+	}
+
+	if input.DueDate == nil {
+		if item.DueDate == nil {
+			return errors.New("item has no due date")
+		}
+		input.DueDate = item.DueDate
+	}
+	if input.DueAllDay == nil {
+		input.DueAllDay = &item.DueAllDay
+	}
+	if input.DueTimezone == nil {
+		input.DueTimezone = &item.DueTimezone
+	}
+
+	due, err := todo.NormalizeDueDate(*input.DueDate, *input.DueAllDay, *input.DueTimezone)
+	if err != nil {
+		return err
+	}
+	input.DueDate = &due
+
+	return nil
 }
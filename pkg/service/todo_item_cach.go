@@ -5,11 +5,13 @@ import (
 )
 
 type TodoItemServiceCach struct {
-	repo repository.TodoItemCach
+	repo     repository.TodoItemCach
+	listRepo repository.TodoList
 }
 
-func NewTodoItemServiceCach(repo repository.TodoItemCach) *TodoItemServiceCach {
-	return &TodoItemServiceCach{repo: repo}
+// listRepo нужен, чтобы сбрасывать кэш всех участников общих списков, а не только текущего пользователя
+func NewTodoItemServiceCach(repo repository.TodoItemCach, listRepo repository.TodoList) *TodoItemServiceCach {
+	return &TodoItemServiceCach{repo: repo, listRepo: listRepo}
 }
 
 // Если listId или itemId использовать не нужно, передать -1
@@ -22,10 +24,37 @@ func (s *TodoItemServiceCach) HSet(userId, listId, itemId int, data string) erro
 	return s.repo.HSet(userId, listId, itemId, data)
 }
 
+// Список items:listId удаляется у всех участников списка
 func (s *TodoItemServiceCach) HDelete(userId, listId int) error {
-	return s.repo.HDelete(userId, listId)
+	ids, err := s.listRepo.GetMemberIds(listId)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 { // список уже удален
+		ids = append(ids, userId)
+	}
+
+	for _, id := range ids {
+		if err := s.repo.HDelete(id, listId); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// Удаляются данные пользователя и всех, с кем у него есть общие списки
 func (s *TodoItemServiceCach) Delete(userId int) error {
-	return s.repo.Delete(userId)
+	ids, err := s.listRepo.GetCoMemberIds(userId)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.repo.Delete(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"todo-app"
+	mock_repository "todo-app/pkg/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodoItemService_Update_Parent(t *testing.T) {
+	type mockBehavior func(items *mock_repository.MockTodoItem, lists *mock_repository.MockTodoList)
+
+	item := todo.TodoItem{Id: 5, ListId: 10}
+
+	testTable := []struct {
+		name         string
+		parentId     int
+		mockBehavior mockBehavior
+		wantErr      string
+	}{
+		{
+			name:     "OK",
+			parentId: 8,
+			mockBehavior: func(items *mock_repository.MockTodoItem, lists *mock_repository.MockTodoList) {
+				items.EXPECT().GetById(1, 5).Return(item, nil).Times(2)
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+				items.EXPECT().GetById(1, 8).Return(todo.TodoItem{Id: 8, ListId: 10}, nil)
+				items.EXPECT().GetSubtreeIds(1, 5).Return([]int{5, 6, 7}, nil)
+				items.EXPECT().Update(1, 5, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "Own Parent",
+			parentId: 5,
+			mockBehavior: func(items *mock_repository.MockTodoItem, lists *mock_repository.MockTodoList) {
+				items.EXPECT().GetById(1, 5).Return(item, nil)
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+			},
+			wantErr: "item cannot be its own parent",
+		},
+		{
+			name:     "Parent In Another List",
+			parentId: 8,
+			mockBehavior: func(items *mock_repository.MockTodoItem, lists *mock_repository.MockTodoList) {
+				items.EXPECT().GetById(1, 5).Return(item, nil).Times(2)
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+				items.EXPECT().GetById(1, 8).Return(todo.TodoItem{Id: 8, ListId: 11}, nil)
+			},
+			wantErr: "parent item belongs to another list",
+		},
+		{
+			name:     "Descendant As Parent",
+			parentId: 7,
+			mockBehavior: func(items *mock_repository.MockTodoItem, lists *mock_repository.MockTodoList) {
+				items.EXPECT().GetById(1, 5).Return(item, nil).Times(2)
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+				items.EXPECT().GetById(1, 7).Return(todo.TodoItem{Id: 7, ListId: 10}, nil)
+				items.EXPECT().GetSubtreeIds(1, 5).Return([]int{5, 6, 7}, nil)
+			},
+			wantErr: "parent item is a subtask of the item",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			items := mock_repository.NewMockTodoItem(c)
+			lists := mock_repository.NewMockTodoList(c)
+			testCase.mockBehavior(items, lists)
+
+			s := NewTodoItemService(items, lists)
+			parentId := testCase.parentId
+			err := s.Update(1, 5, todo.UpdateItemInput{ParentId: &parentId})
+
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoItemService_Create_ParentInAnotherList(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	items := mock_repository.NewMockTodoItem(c)
+	lists := mock_repository.NewMockTodoList(c)
+	lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleOwner, nil)
+	items.EXPECT().GetById(1, 8).Return(todo.TodoItem{Id: 8, ListId: 11}, nil)
+
+	parentId := 8
+	_, err := NewTodoItemService(items, lists).Create(1, 10, todo.TodoItem{Title: "child", ParentId: &parentId})
+	assert.EqualError(t, err, "parent item belongs to another list")
+}
+
+func TestTodoItemService_Update_NextOccurrence(t *testing.T) {
+	type mockBehavior func(items *mock_repository.MockTodoItem, item todo.TodoItem)
+
+	due := time.Date(2022, 6, 6, 6, 0, 0, 0, time.UTC) // понедельник, 9:00 по Москве
+	noRecurrence := ""
+
+	testTable := []struct {
+		name         string
+		recurrence   string
+		occurrence   int
+		mockBehavior mockBehavior
+	}{
+		{
+			name:       "Next Occurrence",
+			recurrence: "FREQ=WEEKLY;BYDAY=MO",
+			occurrence: 1,
+			mockBehavior: func(items *mock_repository.MockTodoItem, item todo.TodoItem) {
+				items.EXPECT().Create(10, gomock.Any()).DoAndReturn(func(listId int, next todo.TodoItem) (int, error) {
+					assert.Equal(t, item.Title, next.Title)
+					assert.Equal(t, item.Recurrence, next.Recurrence)
+					assert.Equal(t, "Europe/Moscow", next.DueTimezone)
+					assert.Equal(t, 2, next.Occurrence)
+					if assert.NotNil(t, next.DueDate) {
+						assert.True(t, next.DueDate.Equal(due.AddDate(0, 0, 7)), "due date: %s", next.DueDate)
+					}
+					return 6, nil
+				})
+				items.EXPECT().Update(1, 5, todo.UpdateItemInput{Recurrence: &noRecurrence}).Return(nil)
+			},
+		},
+		{
+			name:       "Series Finished",
+			recurrence: "FREQ=WEEKLY;BYDAY=MO;COUNT=2",
+			occurrence: 2,
+			mockBehavior: func(items *mock_repository.MockTodoItem, item todo.TodoItem) {
+				items.EXPECT().Update(1, 5, todo.UpdateItemInput{Recurrence: &noRecurrence}).Return(nil)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			item := todo.TodoItem{Id: 5, ListId: 10, Title: "weekly report", DueDate: &due,
+				DueTimezone: "Europe/Moscow", Recurrence: testCase.recurrence, Occurrence: testCase.occurrence}
+			done := true
+
+			items := mock_repository.NewMockTodoItem(c)
+			lists := mock_repository.NewMockTodoList(c)
+			items.EXPECT().GetById(1, 5).Return(item, nil).Times(2)
+			lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+			items.EXPECT().Update(1, 5, todo.UpdateItemInput{Done: &done}).Return(nil)
+			testCase.mockBehavior(items, item)
+
+			err := NewTodoItemService(items, lists).Update(1, 5, todo.UpdateItemInput{Done: &done})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoItemService_Update_Viewer(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	items := mock_repository.NewMockTodoItem(c)
+	lists := mock_repository.NewMockTodoList(c)
+	items.EXPECT().GetById(1, 5).Return(todo.TodoItem{Id: 5, ListId: 10}, nil)
+	lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleViewer, nil)
+
+	title := "new title"
+	err := NewTodoItemService(items, lists).Update(1, 5, todo.UpdateItemInput{Title: &title})
+	assert.EqualError(t, err, "list role editor required")
+}
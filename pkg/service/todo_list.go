@@ -1,24 +1,38 @@
 package service
 
 import (
+	"errors"
 	"todo-app"
 	"todo-app/pkg/repository"
 )
 
 type TodoListService struct {
-	repo repository.TodoList
+	repo          repository.TodoList
+	workspaceRepo repository.Workspaces
 }
 
-func NewTodoListService(repo repository.TodoList) *TodoListService {
-	return &TodoListService{repo: repo}
+func NewTodoListService(repo repository.TodoList, workspaceRepo repository.Workspaces) *TodoListService {
+	return &TodoListService{repo: repo, workspaceRepo: workspaceRepo}
 }
 
+// Создавать списки в общем пространстве могут участники с ролью не ниже editor
 func (s *TodoListService) Create(userId int, list todo.TodoList) (int, error) {
+	if list.WorkspaceId != 0 {
+		if _, err := checkWorkspaceRole(s.workspaceRepo, userId, list.WorkspaceId, todo.ListRoleEditor); err != nil {
+			return 0, err
+		}
+	}
 	return s.repo.Create(userId, list)
 }
 
-func (s *TodoListService) GetAll(userId int) ([]todo.TodoList, error) {
-	return s.repo.GetAll(userId)
+func (s *TodoListService) GetAll(userId int, query todo.ListsQuery) ([]todo.TodoList, string, error) {
+	if err := query.Validate(); err != nil {
+		return nil, "", err
+	}
+	if query.Limit > todo.PageMaxLimit {
+		query.Limit = todo.PageMaxLimit
+	}
+	return s.repo.GetAll(userId, query)
 }
 
 func (s *TodoListService) GetById(userId, listId int) (todo.TodoList, error) {
@@ -26,6 +40,9 @@ func (s *TodoListService) GetById(userId, listId int) (todo.TodoList, error) {
 }
 
 func (s *TodoListService) DeleteById(userId, listId int) error {
+	if err := checkListRole(s.repo, userId, listId, todo.ListRoleOwner); err != nil {
+		return err
+	}
 	return s.repo.DeleteById(userId, listId)
 }
 
@@ -34,5 +51,103 @@ func (s *TodoListService) UpdateById(userId, listId int, list todo.UpdateListInp
 		var res todo.TodoList
 		return res, err
 	}
+	if err := checkListRole(s.repo, userId, listId, todo.ListRoleEditor); err != nil {
+		var res todo.TodoList
+		return res, err
+	}
 	return s.repo.UpdateById(userId, listId, list)
 }
+
+func (s *TodoListService) Move(userId, listId int, input todo.MoveInput) error {
+	if err := input.Validate(listId); err != nil {
+		return err
+	}
+	return s.repo.Move(userId, listId, input)
+}
+
+func (s *TodoListService) GetMembers(userId, listId int) ([]todo.ListMember, error) {
+	if err := checkListRole(s.repo, userId, listId, todo.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(listId)
+}
+
+// Добавлять участников и менять их роли может только владелец, свою роль владелец не меняет,
+// чтобы список не остался без владельца
+func (s *TodoListService) AddMember(userId, listId int, input todo.AddMemberInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	if err := checkListRole(s.repo, userId, listId, todo.ListRoleOwner); err != nil {
+		return 0, err
+	}
+
+	members, err := s.repo.GetMembers(listId)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, member := range members {
+		if member.UserId == userId && member.Username == input.Username {
+			return 0, errors.New("cannot change own role")
+		}
+	}
+
+	return s.repo.AddMember(listId, input.Username, input.Role)
+}
+
+// Владелец может исключить любого участника, остальные - только выйти из списка сами.
+// Последний владелец выйти из списка не может
+func (s *TodoListService) RemoveMember(userId, listId int, username string) (int, error) {
+	role, err := s.repo.GetRole(userId, listId)
+	if err != nil {
+		// list does not exists or does not belongs to user
+		return 0, err
+	}
+
+	members, err := s.repo.GetMembers(listId)
+	if err != nil {
+		return 0, err
+	}
+
+	var target *todo.ListMember
+	owners := 0
+	for i := range members {
+		if members[i].Username == username {
+			target = &members[i]
+		}
+		if members[i].Role == todo.ListRoleOwner {
+			owners++
+		}
+	}
+
+	if target == nil {
+		return 0, errors.New("member not found")
+	}
+
+	if target.UserId != userId && !role.Includes(todo.ListRoleOwner) {
+		return 0, todo.ErrListRole(todo.ListRoleOwner)
+	}
+
+	if target.Role == todo.ListRoleOwner && owners == 1 {
+		return 0, errors.New("cannot remove the last owner of the list")
+	}
+
+	return s.repo.RemoveMember(listId, username)
+}
+
+// Проверка, что пользователь участник списка с ролью не ниже required
+func checkListRole(repo repository.TodoList, userId, listId int, required todo.ListRole) error {
+	role, err := repo.GetRole(userId, listId)
+	if err != nil {
+		// list does not exists or does not belongs to user
+		return err
+	}
+
+	if !role.Includes(required) {
+		return todo.ErrListRole(required)
+	}
+
+	return nil
+}
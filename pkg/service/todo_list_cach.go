@@ -5,11 +5,13 @@ import (
 )
 
 type TodoListServiceCach struct {
-	repo repository.TodoListCach
+	repo     repository.TodoListCach
+	listRepo repository.TodoList
 }
 
-func NewTodoListServiceCach(repo repository.TodoListCach) *TodoListServiceCach {
-	return &TodoListServiceCach{repo: repo}
+// listRepo нужен, чтобы сбрасывать кэш всех участников общих списков, а не только текущего пользователя
+func NewTodoListServiceCach(repo repository.TodoListCach, listRepo repository.TodoList) *TodoListServiceCach {
+	return &TodoListServiceCach{repo: repo, listRepo: listRepo}
 }
 
 // Если listId использовать не нужно, передать -1
@@ -22,10 +24,35 @@ func (s *TodoListServiceCach) HSet(userId, listId int, data string) error {
 	return s.repo.HSet(userId, listId, data)
 }
 
+// Новый список в общем пространстве появляется и у других участников,
+// поэтому lists удаляется у пользователя и всех, с кем у него есть общие списки
 func (s *TodoListServiceCach) HDelete(userId int) error {
-	return s.repo.HDelete(userId)
+	ids, err := s.listRepo.GetCoMemberIds(userId)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.repo.HDelete(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
+// Удаляются данные пользователя и всех, с кем у него есть общие списки
 func (s *TodoListServiceCach) Delete(userId int) error {
-	return s.repo.Delete(userId)
+	ids, err := s.listRepo.GetCoMemberIds(userId)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.repo.Delete(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
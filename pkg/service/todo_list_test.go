@@ -0,0 +1,201 @@
+package service
+
+import (
+	"testing"
+	"todo-app"
+	mock_repository "todo-app/pkg/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTodoListService_DeleteById(t *testing.T) {
+	type mockBehavior func(lists *mock_repository.MockTodoList)
+
+	testTable := []struct {
+		name         string
+		mockBehavior mockBehavior
+		wantErr      string
+	}{
+		{
+			name: "Owner",
+			mockBehavior: func(lists *mock_repository.MockTodoList) {
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleOwner, nil)
+				lists.EXPECT().DeleteById(1, 10).Return(nil)
+			},
+		},
+		{
+			name: "Editor",
+			mockBehavior: func(lists *mock_repository.MockTodoList) {
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+			},
+			wantErr: "list role owner required",
+		},
+		{
+			name: "Viewer",
+			mockBehavior: func(lists *mock_repository.MockTodoList) {
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleViewer, nil)
+			},
+			wantErr: "list role owner required",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lists := mock_repository.NewMockTodoList(c)
+			testCase.mockBehavior(lists)
+
+			err := NewTodoListService(lists, nil).DeleteById(1, 10)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoListService_AddMember(t *testing.T) {
+	type mockBehavior func(lists *mock_repository.MockTodoList)
+
+	members := []todo.ListMember{
+		{UserId: 1, Username: "alex", Role: todo.ListRoleOwner},
+		{UserId: 2, Username: "bob", Role: todo.ListRoleEditor},
+	}
+
+	testTable := []struct {
+		name         string
+		input        todo.AddMemberInput
+		mockBehavior mockBehavior
+		wantErr      string
+	}{
+		{
+			name:  "OK",
+			input: todo.AddMemberInput{Username: "bob", Role: todo.ListRoleViewer},
+			mockBehavior: func(lists *mock_repository.MockTodoList) {
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleOwner, nil)
+				lists.EXPECT().GetMembers(10).Return(members, nil)
+				lists.EXPECT().AddMember(10, "bob", todo.ListRoleViewer).Return(2, nil)
+			},
+		},
+		{
+			name:  "Demote Last Owner",
+			input: todo.AddMemberInput{Username: "alex", Role: todo.ListRoleEditor},
+			mockBehavior: func(lists *mock_repository.MockTodoList) {
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleOwner, nil)
+				lists.EXPECT().GetMembers(10).Return(members, nil)
+			},
+			wantErr: "cannot change own role",
+		},
+		{
+			name:  "Not Owner",
+			input: todo.AddMemberInput{Username: "carol", Role: todo.ListRoleViewer},
+			mockBehavior: func(lists *mock_repository.MockTodoList) {
+				lists.EXPECT().GetRole(1, 10).Return(todo.ListRoleEditor, nil)
+			},
+			wantErr: "list role owner required",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lists := mock_repository.NewMockTodoList(c)
+			testCase.mockBehavior(lists)
+
+			_, err := NewTodoListService(lists, nil).AddMember(1, 10, testCase.input)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTodoListService_RemoveMember(t *testing.T) {
+	owner := todo.ListMember{UserId: 1, Username: "alex", Role: todo.ListRoleOwner}
+	editor := todo.ListMember{UserId: 2, Username: "bob", Role: todo.ListRoleEditor}
+	viewer := todo.ListMember{UserId: 3, Username: "carol", Role: todo.ListRoleViewer}
+
+	testTable := []struct {
+		name     string
+		userId   int
+		role     todo.ListRole
+		members  []todo.ListMember
+		username string
+		wantErr  string
+	}{
+		{
+			name:     "Owner Removes Editor",
+			userId:   1,
+			role:     todo.ListRoleOwner,
+			members:  []todo.ListMember{owner, editor},
+			username: "bob",
+		},
+		{
+			name:     "Editor Leaves",
+			userId:   2,
+			role:     todo.ListRoleEditor,
+			members:  []todo.ListMember{owner, editor},
+			username: "bob",
+		},
+		{
+			name:     "Editor Removes Viewer",
+			userId:   2,
+			role:     todo.ListRoleEditor,
+			members:  []todo.ListMember{owner, editor, viewer},
+			username: "carol",
+			wantErr:  "list role owner required",
+		},
+		{
+			name:     "Last Owner Leaves",
+			userId:   1,
+			role:     todo.ListRoleOwner,
+			members:  []todo.ListMember{owner, editor},
+			username: "alex",
+			wantErr:  "cannot remove the last owner of the list",
+		},
+		{
+			name:     "One Of Owners Leaves",
+			userId:   1,
+			role:     todo.ListRoleOwner,
+			members:  []todo.ListMember{owner, {UserId: 2, Username: "bob", Role: todo.ListRoleOwner}},
+			username: "alex",
+		},
+		{
+			name:     "Member Not Found",
+			userId:   1,
+			role:     todo.ListRoleOwner,
+			members:  []todo.ListMember{owner},
+			username: "dave",
+			wantErr:  "member not found",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			lists := mock_repository.NewMockTodoList(c)
+			lists.EXPECT().GetRole(testCase.userId, 10).Return(testCase.role, nil)
+			lists.EXPECT().GetMembers(10).Return(testCase.members, nil)
+			if testCase.wantErr == "" {
+				lists.EXPECT().RemoveMember(10, testCase.username).Return(1, nil)
+			}
+
+			_, err := NewTodoListService(lists, nil).RemoveMember(testCase.userId, 10, testCase.username)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
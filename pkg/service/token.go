@@ -0,0 +1,24 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const secretTokenSize = 32 // длина случайного токена (ссылки-приглашения, refresh-токена) в байтах
+
+// Случайный токен для передачи пользователю, в БД хранится только его хэш
+func generateSecretToken() (string, error) {
+	buf := make([]byte, secretTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
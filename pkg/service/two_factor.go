@@ -0,0 +1,184 @@
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+	"todo-app"
+	"todo-app/pkg/repository"
+	"todo-app/pkg/totp"
+)
+
+const (
+	recoveryCodesCount = 10
+	recoveryCodeLength = 10 // символов base32, выдается в виде "xxxxx-xxxxx"
+	totpSkew           = 1  // допуск расхождения часов устройства в шагах (по 30 секунд)
+)
+
+var (
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+)
+
+type TwoFactorService struct {
+	repo repository.TwoFactor
+	cfg  ConfigAuth
+}
+
+func NewTwoFactorService(repo repository.TwoFactor, cfg ConfigAuth) *TwoFactorService {
+	return &TwoFactorService{repo: repo, cfg: cfg.withDefaults()}
+}
+
+// Начало подключения 2FA: новый секрет сохраняется, но действует только после подтверждения кодом.
+// Повторный вызов до подтверждения заменяет секрет
+func (s *TwoFactorService) Enroll(userId int) (todo.TwoFactorEnrollment, error) {
+	tf, err := s.repo.Get(userId)
+	if err != nil {
+		return todo.TwoFactorEnrollment{}, err
+	}
+	if tf.Enabled {
+		return todo.TwoFactorEnrollment{}, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return todo.TwoFactorEnrollment{}, err
+	}
+
+	if err := s.repo.SetPendingSecret(userId, secret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return todo.TwoFactorEnrollment{}, ErrTwoFactorEnabled
+		}
+		return todo.TwoFactorEnrollment{}, err
+	}
+
+	return todo.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.cfg.TOTPIssuer, tf.Username, secret),
+	}, nil
+}
+
+// Подтверждение подключения первым кодом из приложения. Возвращает коды восстановления,
+// они показываются один раз
+func (s *TwoFactorService) Confirm(userId int, code string) ([]string, error) {
+	tf, err := s.repo.Get(userId)
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if tf.Secret == nil {
+		return nil, errors.New("two-factor enrollment not started")
+	}
+
+	step, ok, err := totp.Validate(*tf.Secret, code, time.Now(), totpSkew)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Enable(userId, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Отключение 2FA, требуется код из приложения или код восстановления
+func (s *TwoFactorService) Disable(userId int, code string) error {
+	tf, err := s.repo.Get(userId)
+	if err != nil {
+		return err
+	}
+	if !tf.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if err := verifySecondFactor(s.repo, tf, userId, code); err != nil {
+		return err
+	}
+
+	return s.repo.Disable(userId)
+}
+
+// Проверка кода из приложения (6 цифр) или одноразового кода восстановления.
+// Код из приложения принимается один раз: повтор кода того же шага отклоняется
+func verifySecondFactor(repo repository.TwoFactor, tf todo.TwoFactor, userId int, code string) error {
+	code = strings.ReplaceAll(code, " ", "")
+
+	if isTOTPCode(code) {
+		if tf.Secret == nil {
+			return ErrInvalidTwoFactorCode
+		}
+
+		step, ok, err := totp.Validate(*tf.Secret, code, time.Now(), totpSkew)
+		if err != nil {
+			return err
+		}
+		if !ok || step <= tf.LastStep {
+			return ErrInvalidTwoFactorCode
+		}
+
+		used, err := repo.UseStep(userId, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	err := repo.UseRecoveryCode(userId, hashSecretToken(normalizeRecoveryCode(code)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidTwoFactorCode
+	}
+	return err
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Коды восстановления и их хэши для БД
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodesCount)
+	hashes := make([]string, recoveryCodesCount)
+
+	buf := make([]byte, recoveryCodeLength) // с запасом: 10 байт дают 16 символов base32
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:recoveryCodeLength]
+
+		codes[i] = raw[:recoveryCodeLength/2] + "-" + raw[recoveryCodeLength/2:]
+		hashes[i] = hashSecretToken(raw)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
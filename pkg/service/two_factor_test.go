@@ -0,0 +1,169 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"todo-app"
+	"todo-app/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Состояние 2FA одного пользователя в памяти
+type twoFactorRepoStub struct {
+	tf    todo.TwoFactor
+	codes map[string]bool // хэш кода восстановления -> использован
+}
+
+func (r *twoFactorRepoStub) Get(userId int) (todo.TwoFactor, error) {
+	return r.tf, nil
+}
+
+func (r *twoFactorRepoStub) SetPendingSecret(userId int, secret string) error {
+	if r.tf.Enabled {
+		return sql.ErrNoRows
+	}
+	r.tf.Secret = &secret
+	return nil
+}
+
+func (r *twoFactorRepoStub) Enable(userId int, step int64, codeHashes []string) error {
+	r.tf.Enabled = true
+	r.tf.LastStep = step
+	r.codes = map[string]bool{}
+	for _, hash := range codeHashes {
+		r.codes[hash] = false
+	}
+	return nil
+}
+
+func (r *twoFactorRepoStub) Disable(userId int) error {
+	r.tf = todo.TwoFactor{Username: r.tf.Username}
+	r.codes = nil
+	return nil
+}
+
+func (r *twoFactorRepoStub) UseStep(userId int, step int64) (bool, error) {
+	if step <= r.tf.LastStep {
+		return false, nil
+	}
+	r.tf.LastStep = step
+	return true, nil
+}
+
+func (r *twoFactorRepoStub) UseRecoveryCode(userId int, codeHash string) error {
+	used, ok := r.codes[codeHash]
+	if !ok || used {
+		return sql.ErrNoRows
+	}
+	r.codes[codeHash] = true
+	return nil
+}
+
+func TestTwoFactor_SignIn(t *testing.T) {
+	hash, err := generatePasswordHash("qwerty")
+	assert.NoError(t, err)
+
+	users := &authRepoStub{users: map[string]todo.User{"alex": {Id: 7, Password: hash}}, updated: map[int]string{}}
+	tfRepo := &twoFactorRepoStub{tf: todo.TwoFactor{Username: "alex"}}
+	auth := NewAuthService(users, &sessionsRepoStub{sessionId: 3}, tfRepo, sessionsCachStub{}, newLoginAttemptsStub(),
+		nil, newTestKeys(t), ConfigAuth{})
+	tfService := NewTwoFactorService(tfRepo, ConfigAuth{})
+
+	// Без 2FA токены выдаются сразу
+	tokens, err := auth.GenerateToken("alex", "qwerty", todo.SessionClient{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	enrollment, err := tfService.Enroll(7)
+	assert.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/Todo%20App:alex?")
+
+	// Неверный первый код не включает 2FA
+	_, err = tfService.Confirm(7, "000000x")
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	assert.NoError(t, err)
+	recoveryCodes, err := tfService.Confirm(7, code)
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes, recoveryCodesCount)
+
+	_, err = tfService.Enroll(7)
+	assert.ErrorIs(t, err, ErrTwoFactorEnabled)
+
+	// Пароль верный - вместо токенов токен второго шага
+	_, err = auth.GenerateToken("alex", "qwerty", todo.SessionClient{DeviceName: "Pixel 6"})
+	var challenge *TwoFactorRequiredError
+	assert.True(t, errors.As(err, &challenge))
+
+	// Токен второго шага не принимается как access-токен
+	_, _, err = auth.ParseToken(challenge.ChallengeToken)
+	assert.Error(t, err)
+
+	// Код, уже использованный при подтверждении, повторно не принимается
+	_, err = auth.VerifyTwoFactor(challenge.ChallengeToken, code, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	next, err := totp.Code(enrollment.Secret, time.Now().Add(totp.Period*time.Second))
+	assert.NoError(t, err)
+	tokens, err = auth.VerifyTwoFactor(challenge.ChallengeToken, next, todo.SessionClient{})
+	assert.NoError(t, err)
+	userId, _, err := auth.ParseToken(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, userId)
+
+	// Access-токен не принимается как токен второго шага
+	_, err = auth.VerifyTwoFactor(tokens.AccessToken, next, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidChallenge)
+
+	// Токен второго шага одноразовый
+	_, err = auth.VerifyTwoFactor(challenge.ChallengeToken, recoveryCodes[0], todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidChallenge)
+
+	// Код восстановления одноразовый, регистр и дефис не важны
+	signIn := func() string {
+		_, err := auth.GenerateToken("alex", "qwerty", todo.SessionClient{})
+		var challenge *TwoFactorRequiredError
+		assert.True(t, errors.As(err, &challenge))
+		return challenge.ChallengeToken
+	}
+	_, err = auth.VerifyTwoFactor(signIn(), recoveryCodes[0], todo.SessionClient{})
+	assert.NoError(t, err)
+	_, err = auth.VerifyTwoFactor(signIn(), recoveryCodes[0], todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	assert.NoError(t, tfService.Disable(7, strings.ToUpper(recoveryCodes[1])))
+	tokens, err = auth.GenerateToken("alex", "qwerty", todo.SessionClient{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func TestAuthService_VerifyTwoFactor_Attempts(t *testing.T) {
+	secret := "JBSWY3DPEHPK3PXP"
+	users := &authRepoStub{users: map[string]todo.User{"alex": {Id: 7}}, updated: map[int]string{}}
+	tfRepo := &twoFactorRepoStub{tf: todo.TwoFactor{Username: "alex", Enabled: true, Secret: &secret}}
+	attempts := newLoginAttemptsStub()
+	auth := NewAuthService(users, &sessionsRepoStub{sessionId: 3}, tfRepo, sessionsCachStub{}, attempts,
+		nil, newTestKeys(t), ConfigAuth{})
+
+	_, err := auth.issueTokens(7, todo.SessionClient{})
+	var challenge *TwoFactorRequiredError
+	assert.True(t, errors.As(err, &challenge))
+
+	for i := 0; i < challengeMaxAttempts; i++ {
+		attempts.userDelays = map[string]time.Duration{} // задержки между попытками проверяются отдельно
+		_, err = auth.VerifyTwoFactor(challenge.ChallengeToken, "wrong-code", todo.SessionClient{})
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	}
+
+	// Попытки исчерпаны: верный код уже не принимается
+	attempts.userDelays = map[string]time.Duration{}
+	code, err := totp.Code(secret, time.Now())
+	assert.NoError(t, err)
+	_, err = auth.VerifyTwoFactor(challenge.ChallengeToken, code, todo.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidChallenge)
+}
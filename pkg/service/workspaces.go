@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"todo-app"
+	"todo-app/pkg/repository"
+)
+
+type WorkspacesService struct {
+	repo repository.Workspaces
+}
+
+func NewWorkspacesService(repo repository.Workspaces) *WorkspacesService {
+	return &WorkspacesService{repo: repo}
+}
+
+func (s *WorkspacesService) Create(userId int, workspace todo.Workspace) (int, error) {
+	return s.repo.Create(userId, workspace)
+}
+
+func (s *WorkspacesService) GetAll(userId int) ([]todo.Workspace, error) {
+	return s.repo.GetAll(userId)
+}
+
+func (s *WorkspacesService) GetLists(userId, workspaceId int) ([]todo.TodoList, error) {
+	if _, err := checkWorkspaceRole(s.repo, userId, workspaceId, todo.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLists(userId, workspaceId)
+}
+
+func (s *WorkspacesService) GetMembers(userId, workspaceId int) ([]todo.ListMember, error) {
+	if _, err := checkWorkspaceRole(s.repo, userId, workspaceId, todo.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembers(workspaceId)
+}
+
+// Добавлять участников может только владелец, личное пространство общим не делается
+// (для совместной работы есть общие списки)
+func (s *WorkspacesService) AddMember(userId, workspaceId int, input todo.AddMemberInput) (int, error) {
+	if err := input.Validate(); err != nil {
+		return 0, err
+	}
+
+	workspace, err := checkWorkspaceRole(s.repo, userId, workspaceId, todo.ListRoleOwner)
+	if err != nil {
+		return 0, err
+	}
+
+	if workspace.Personal {
+		return 0, errors.New("cannot share personal workspace")
+	}
+
+	members, err := s.repo.GetMembers(workspaceId)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, member := range members {
+		if member.UserId == userId && member.Username == input.Username {
+			return 0, errors.New("cannot change own role")
+		}
+	}
+
+	return s.repo.AddMember(workspaceId, input.Username, input.Role)
+}
+
+// Владелец может исключить любого участника, остальные - только выйти сами.
+// Последний владелец и владелец личного пространства выйти не могут
+func (s *WorkspacesService) RemoveMember(userId, workspaceId int, username string) (int, error) {
+	workspace, err := s.repo.GetById(userId, workspaceId)
+	if err != nil {
+		// workspace does not exists or user is not a member
+		return 0, err
+	}
+
+	if workspace.Personal {
+		return 0, errors.New("cannot leave personal workspace")
+	}
+
+	members, err := s.repo.GetMembers(workspaceId)
+	if err != nil {
+		return 0, err
+	}
+
+	var target *todo.ListMember
+	owners := 0
+	for i := range members {
+		if members[i].Username == username {
+			target = &members[i]
+		}
+		if members[i].Role == todo.ListRoleOwner {
+			owners++
+		}
+	}
+
+	if target == nil {
+		return 0, errors.New("member not found")
+	}
+
+	if target.UserId != userId && !workspace.Role.Includes(todo.ListRoleOwner) {
+		return 0, todo.ErrWorkspaceRole(todo.ListRoleOwner)
+	}
+
+	if target.Role == todo.ListRoleOwner && owners == 1 {
+		return 0, errors.New("cannot remove the last owner of the workspace")
+	}
+
+	return s.repo.RemoveMember(workspaceId, username)
+}
+
+// Проверка, что пользователь участник пространства с ролью не ниже required
+func checkWorkspaceRole(repo repository.Workspaces, userId, workspaceId int, required todo.ListRole) (todo.Workspace, error) {
+	workspace, err := repo.GetById(userId, workspaceId)
+	if err != nil {
+		// workspace does not exists or user is not a member
+		return workspace, err
+	}
+
+	if !workspace.Role.Includes(required) {
+		return workspace, todo.ErrWorkspaceRole(required)
+	}
+
+	return workspace, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"testing"
+	"todo-app"
+	mock_repository "todo-app/pkg/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspacesService_AddMember(t *testing.T) {
+	type mockBehavior func(workspaces *mock_repository.MockWorkspaces)
+
+	team := todo.Workspace{Id: 10, Name: "team", Role: todo.ListRoleOwner}
+	members := []todo.ListMember{
+		{UserId: 1, Username: "alex", Role: todo.ListRoleOwner},
+		{UserId: 2, Username: "bob", Role: todo.ListRoleEditor},
+	}
+
+	testTable := []struct {
+		name         string
+		input        todo.AddMemberInput
+		mockBehavior mockBehavior
+		wantErr      string
+	}{
+		{
+			name:  "OK",
+			input: todo.AddMemberInput{Username: "bob", Role: todo.ListRoleViewer},
+			mockBehavior: func(workspaces *mock_repository.MockWorkspaces) {
+				workspaces.EXPECT().GetById(1, 10).Return(team, nil)
+				workspaces.EXPECT().GetMembers(10).Return(members, nil)
+				workspaces.EXPECT().AddMember(10, "bob", todo.ListRoleViewer).Return(2, nil)
+			},
+		},
+		{
+			name:  "Personal Workspace",
+			input: todo.AddMemberInput{Username: "bob", Role: todo.ListRoleEditor},
+			mockBehavior: func(workspaces *mock_repository.MockWorkspaces) {
+				workspaces.EXPECT().GetById(1, 10).Return(todo.Workspace{Id: 10, Personal: true, Role: todo.ListRoleOwner}, nil)
+			},
+			wantErr: "cannot share personal workspace",
+		},
+		{
+			name:  "Demote Last Owner",
+			input: todo.AddMemberInput{Username: "alex", Role: todo.ListRoleViewer},
+			mockBehavior: func(workspaces *mock_repository.MockWorkspaces) {
+				workspaces.EXPECT().GetById(1, 10).Return(team, nil)
+				workspaces.EXPECT().GetMembers(10).Return(members, nil)
+			},
+			wantErr: "cannot change own role",
+		},
+		{
+			name:  "Editor",
+			input: todo.AddMemberInput{Username: "carol", Role: todo.ListRoleViewer},
+			mockBehavior: func(workspaces *mock_repository.MockWorkspaces) {
+				workspaces.EXPECT().GetById(1, 10).Return(todo.Workspace{Id: 10, Role: todo.ListRoleEditor}, nil)
+			},
+			wantErr: "workspace role owner required",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mock_repository.NewMockWorkspaces(c)
+			testCase.mockBehavior(workspaces)
+
+			_, err := NewWorkspacesService(workspaces).AddMember(1, 10, testCase.input)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWorkspacesService_RemoveMember(t *testing.T) {
+	owner := todo.ListMember{UserId: 1, Username: "alex", Role: todo.ListRoleOwner}
+	editor := todo.ListMember{UserId: 2, Username: "bob", Role: todo.ListRoleEditor}
+	viewer := todo.ListMember{UserId: 3, Username: "carol", Role: todo.ListRoleViewer}
+
+	testTable := []struct {
+		name      string
+		userId    int
+		workspace todo.Workspace
+		members   []todo.ListMember
+		username  string
+		wantErr   string
+	}{
+		{
+			name:      "Owner Removes Editor",
+			userId:    1,
+			workspace: todo.Workspace{Id: 10, Role: todo.ListRoleOwner},
+			members:   []todo.ListMember{owner, editor},
+			username:  "bob",
+		},
+		{
+			name:      "Editor Leaves",
+			userId:    2,
+			workspace: todo.Workspace{Id: 10, Role: todo.ListRoleEditor},
+			members:   []todo.ListMember{owner, editor},
+			username:  "bob",
+		},
+		{
+			name:      "Editor Removes Viewer",
+			userId:    2,
+			workspace: todo.Workspace{Id: 10, Role: todo.ListRoleEditor},
+			members:   []todo.ListMember{owner, editor, viewer},
+			username:  "carol",
+			wantErr:   "workspace role owner required",
+		},
+		{
+			name:      "Last Owner Leaves",
+			userId:    1,
+			workspace: todo.Workspace{Id: 10, Role: todo.ListRoleOwner},
+			members:   []todo.ListMember{owner, editor},
+			username:  "alex",
+			wantErr:   "cannot remove the last owner of the workspace",
+		},
+		{
+			name:      "Personal Workspace",
+			userId:    1,
+			workspace: todo.Workspace{Id: 10, Personal: true, Role: todo.ListRoleOwner},
+			username:  "alex",
+			wantErr:   "cannot leave personal workspace",
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			workspaces := mock_repository.NewMockWorkspaces(c)
+			workspaces.EXPECT().GetById(testCase.userId, 10).Return(testCase.workspace, nil)
+			if !testCase.workspace.Personal {
+				workspaces.EXPECT().GetMembers(10).Return(testCase.members, nil)
+			}
+			if testCase.wantErr == "" {
+				workspaces.EXPECT().RemoveMember(10, testCase.username).Return(1, nil)
+			}
+
+			_, err := NewWorkspacesService(workspaces).RemoveMember(testCase.userId, 10, testCase.username)
+			if testCase.wantErr != "" {
+				assert.EqualError(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package totp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Секрет тестовых векторов RFC 4226 и RFC 6238 (SHA1): "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTP_RFC4226(t *testing.T) {
+	key, err := decodeSecret(rfcSecret)
+	assert.NoError(t, err)
+
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		assert.Equal(t, code, hotp(key, uint64(counter), 6))
+	}
+}
+
+func TestTOTP_RFC6238(t *testing.T) {
+	key, err := decodeSecret(rfcSecret)
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+		{unix: 20000000000, want: "65353130"},
+	}
+
+	for _, testCase := range testTable {
+		step := Step(time.Unix(testCase.unix, 0))
+		assert.Equal(t, testCase.want, hotp(key, uint64(step), 8))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	code, err := Code(rfcSecret, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "005924", code)
+
+	previous, err := Code(rfcSecret, now.Add(-Period*time.Second))
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name     string
+		code     string
+		skew     int
+		wantStep int64
+		wantOk   bool
+	}{
+		{name: "Current", code: code, skew: 1, wantStep: Step(now), wantOk: true},
+		{name: "Spaces", code: "005 924", skew: 1, wantStep: Step(now), wantOk: true},
+		{name: "Previous Step Within Skew", code: previous, skew: 1, wantStep: Step(now) - 1, wantOk: true},
+		{name: "Previous Step Without Skew", code: previous, skew: 0},
+		{name: "Wrong Code", code: "123456", skew: 1},
+		{name: "Wrong Length", code: "05924", skew: 1},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			step, ok, err := Validate(rfcSecret, testCase.code, now, testCase.skew)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.wantOk, ok)
+			assert.Equal(t, testCase.wantStep, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32) // 20 байт в base32
+
+	_, err = Code(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	assert.Equal(t,
+		"otpauth://totp/Todo%20App:alex?algorithm=SHA1&digits=6&issuer=Todo+App&period=30&secret=JBSWY3DPEHPK3PXP",
+		URI("Todo App", "alex", "JBSWY3DPEHPK3PXP"))
+}
@@ -0,0 +1,69 @@
+package todo
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"time"
+)
+
+// Каналы доставки напоминаний
+const (
+	ReminderChannelWebhook = "webhook"
+	ReminderChannelEmail   = "email"
+)
+
+// Напоминание о задаче. Задается либо абсолютным временем RemindAt,
+// либо смещением OffsetMinutes до срока выполнения задачи
+type Reminder struct {
+	Id            int        `json:"id" db:"id"`
+	ItemId        int        `json:"item_id" db:"item_id"`
+	RemindAt      *time.Time `json:"remind_at,omitempty" db:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty" db:"offset_minutes"` // за сколько минут до срока напомнить
+	Channel       string     `json:"channel" db:"channel" binding:"required" enums:"webhook,email"`
+	Target        string     `json:"target" db:"target" binding:"required"` // URL вебхука или адрес почты
+	FiredAt       *time.Time `json:"fired_at,omitempty" db:"fired_at"`      // время отправки (nil - еще не отправлено)
+	LastError     string     `json:"last_error,omitempty" db:"last_error"`
+}
+
+// Напоминание, выбранное планировщиком для отправки, вместе с данными задачи
+type DueReminder struct {
+	Id              int        `db:"id"`
+	ItemId          int        `db:"item_id"`
+	UserId          int        `db:"user_id"`
+	Channel         string     `db:"channel"`
+	Target          string     `db:"target"`
+	Attempts        int        `db:"attempts"`
+	ItemTitle       string     `db:"title"`
+	ItemDescription string     `db:"description"`
+	DueDate         *time.Time `db:"due_date"`
+}
+
+// Проверка напоминания. Адрес почты приводится к виду без имени: "Alex <a@b.c>" -> "a@b.c"
+func (r *Reminder) Validate() error {
+	if (r.RemindAt == nil) == (r.OffsetMinutes == nil) {
+		return errors.New("reminder requires either remind_at or offset_minutes")
+	}
+
+	if r.OffsetMinutes != nil && *r.OffsetMinutes < 0 {
+		return errors.New("offset_minutes must not be negative")
+	}
+
+	switch r.Channel {
+	case ReminderChannelEmail:
+		addr, err := mail.ParseAddress(r.Target)
+		if err != nil {
+			return errors.New("invalid email target")
+		}
+		r.Target = addr.Address
+	case ReminderChannelWebhook: // адрес получателя проверяется при отправке: разрешены только публичные адреса
+		u, err := url.Parse(r.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("invalid webhook target")
+		}
+	default:
+		return errors.New("invalid reminder channel")
+	}
+
+	return nil
+}
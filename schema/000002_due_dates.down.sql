@@ -0,0 +1,6 @@
+DROP INDEX todo_items_due_date_idx;
+
+ALTER TABLE todo_items
+    DROP COLUMN due_date,
+    DROP COLUMN due_all_day,
+    DROP COLUMN due_timezone;
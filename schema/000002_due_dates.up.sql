@@ -0,0 +1,6 @@
+ALTER TABLE todo_items
+    ADD COLUMN due_date         timestamp with time zone,
+    ADD COLUMN due_all_day      boolean         not null default false,
+    ADD COLUMN due_timezone     varchar(64)     not null default '';
+
+CREATE INDEX todo_items_due_date_idx ON todo_items (due_date) WHERE done = false AND due_date IS NOT NULL;
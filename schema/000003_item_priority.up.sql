@@ -0,0 +1,5 @@
+ALTER TABLE todo_items
+    ADD COLUMN priority         smallint        not null default 0 check (priority between 0 and 4),
+    ADD COLUMN created_at       timestamp with time zone    not null default now();
+
+CREATE INDEX todo_items_priority_idx ON todo_items (priority DESC, created_at);
@@ -0,0 +1,17 @@
+CREATE TABLE tags
+(
+    id          serial                                          not null unique,
+    user_id     int references users (id) on delete cascade     not null,
+    name        varchar(64)                                     not null,
+    color       varchar(16)                                     not null default '',
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE item_tags
+(
+    item_id     int references todo_items (id) on delete cascade    not null,
+    tag_id      int references tags (id) on delete cascade          not null,
+    PRIMARY KEY (item_id, tag_id)
+);
+
+CREATE INDEX item_tags_tag_id_idx ON item_tags (tag_id);
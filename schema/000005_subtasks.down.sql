@@ -0,0 +1,4 @@
+DROP INDEX todo_items_parent_id_idx;
+
+ALTER TABLE todo_items
+    DROP COLUMN parent_id;
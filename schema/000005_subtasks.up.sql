@@ -0,0 +1,4 @@
+ALTER TABLE todo_items
+    ADD COLUMN parent_id        int references todo_items (id) on delete cascade;
+
+CREATE INDEX todo_items_parent_id_idx ON todo_items (parent_id);
@@ -0,0 +1,3 @@
+ALTER TABLE todo_items
+    DROP COLUMN recurrence,
+    DROP COLUMN occurrence;
@@ -0,0 +1,3 @@
+ALTER TABLE todo_items
+    ADD COLUMN recurrence       varchar(255)    not null default '',
+    ADD COLUMN occurrence       int             not null default 1;
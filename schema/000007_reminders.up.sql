@@ -0,0 +1,16 @@
+CREATE TABLE reminders
+(
+    id              serial                                              not null unique,
+    item_id         int references todo_items (id) on delete cascade    not null,
+    user_id         int references users (id) on delete cascade         not null,
+    remind_at       timestamp with time zone,
+    offset_minutes  int,
+    channel         varchar(16)                                         not null,
+    target          varchar(255)                                        not null,
+    fired_at        timestamp with time zone,
+    attempts        int                                                 not null default 0,
+    last_error      varchar(255)                                        not null default '',
+    CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL))
+);
+
+CREATE INDEX reminders_pending_idx ON reminders (item_id) WHERE fired_at IS NULL;
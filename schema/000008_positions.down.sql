@@ -0,0 +1,7 @@
+DROP INDEX user_lists_user_id_position_idx;
+
+ALTER TABLE todo_items
+    DROP COLUMN position;
+
+ALTER TABLE user_lists
+    DROP COLUMN position;
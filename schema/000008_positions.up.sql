@@ -0,0 +1,18 @@
+-- Ранги ручной сортировки (pkg/rank), сравниваются побайтово.
+-- Порядок списков у каждого пользователя свой, поэтому ранг списка хранится в user_lists
+ALTER TABLE user_lists
+    ADD COLUMN position         text COLLATE "C"    not null default '';
+
+ALTER TABLE todo_items
+    ADD COLUMN position         text COLLATE "C"    not null default '';
+
+-- Существующие записи сохраняют прежний порядок (по id)
+UPDATE user_lists ul SET position = lpad(r.n::text, 10, '0') || 'V'
+FROM (SELECT id, row_number() OVER (PARTITION BY user_id ORDER BY list_id) AS n FROM user_lists) r
+WHERE ul.id = r.id;
+
+UPDATE todo_items ti SET position = lpad(r.n::text, 10, '0') || 'V'
+FROM (SELECT item_id, row_number() OVER (PARTITION BY list_id ORDER BY item_id) AS n FROM lists_items) r
+WHERE ti.id = r.item_id;
+
+CREATE INDEX user_lists_user_id_position_idx ON user_lists (user_id, position);
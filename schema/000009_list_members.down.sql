@@ -0,0 +1,7 @@
+DROP INDEX user_lists_list_id_idx;
+
+ALTER TABLE user_lists
+    DROP CONSTRAINT user_lists_user_id_list_id_key;
+
+ALTER TABLE user_lists
+    DROP COLUMN role;
@@ -0,0 +1,9 @@
+ALTER TABLE user_lists
+    ADD COLUMN role             varchar(16)     not null default 'owner'
+        CHECK (role IN ('owner', 'editor', 'viewer'));
+
+-- Пользователь может быть участником списка только один раз
+ALTER TABLE user_lists
+    ADD CONSTRAINT user_lists_user_id_list_id_key UNIQUE (user_id, list_id);
+
+CREATE INDEX user_lists_list_id_idx ON user_lists (list_id);
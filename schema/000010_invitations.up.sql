@@ -0,0 +1,20 @@
+CREATE TABLE invitations
+(
+    id              serial                                              not null unique,
+    list_id         int references todo_lists (id) on delete cascade    not null,
+    inviter_id      int references users (id) on delete cascade         not null,
+    invitee_id      int references users (id) on delete cascade,
+    role            varchar(16)                                         not null
+        CHECK (role IN ('owner', 'editor', 'viewer')),
+    token_hash      varchar(64)                                         unique,
+    status          varchar(16)                                         not null default 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+    expires_at      timestamp with time zone                            not null,
+    created_at      timestamp with time zone                            not null default now(),
+    responded_at    timestamp with time zone,
+    -- Приглашение адресовано пользователю или выдано ссылкой с токеном
+    CHECK (invitee_id IS NOT NULL OR token_hash IS NOT NULL)
+);
+
+CREATE INDEX invitations_invitee_id_idx ON invitations (invitee_id) WHERE status = 'pending';
+CREATE INDEX invitations_list_id_idx ON invitations (list_id) WHERE status = 'pending';
@@ -0,0 +1,11 @@
+DELETE FROM user_lists WHERE inherited;
+
+ALTER TABLE user_lists
+    DROP COLUMN inherited;
+
+ALTER TABLE todo_lists
+    DROP COLUMN workspace_id;
+
+DROP TABLE workspace_members;
+
+DROP TABLE workspaces;
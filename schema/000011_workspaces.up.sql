@@ -0,0 +1,42 @@
+CREATE TABLE workspaces
+(
+    id                  serial                                          not null unique,
+    name                varchar(255)                                    not null,
+    personal_user_id    int references users (id) on delete cascade     unique, -- владелец личного пространства
+    created_at          timestamp with time zone                        not null default now()
+);
+
+CREATE TABLE workspace_members
+(
+    id              serial                                              not null unique,
+    workspace_id    int references workspaces (id) on delete cascade    not null,
+    user_id         int references users (id) on delete cascade         not null,
+    role            varchar(16)                                         not null
+        CHECK (role IN ('owner', 'editor', 'viewer')),
+    UNIQUE (workspace_id, user_id)
+);
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members (user_id);
+
+ALTER TABLE todo_lists
+    ADD COLUMN workspace_id     int references workspaces (id) on delete cascade;
+
+CREATE INDEX todo_lists_workspace_id_idx ON todo_lists (workspace_id);
+
+-- Доступ, полученный через участие в пространстве (снимается при выходе из пространства)
+ALTER TABLE user_lists
+    ADD COLUMN inherited        boolean         not null default false;
+
+-- Личное пространство для каждого существующего пользователя
+INSERT INTO workspaces (name, personal_user_id)
+    SELECT 'Personal', id FROM users;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+    SELECT id, personal_user_id, 'owner' FROM workspaces WHERE personal_user_id IS NOT NULL;
+
+-- Список переходит в личное пространство своего первого владельца (или первого участника).
+-- Списки без участников никому не доступны и остаются вне пространств
+UPDATE todo_lists tl SET workspace_id = w.id
+    FROM (SELECT DISTINCT ON (list_id) list_id, user_id FROM user_lists
+            ORDER BY list_id, (role = 'owner') DESC, id) first, workspaces w
+    WHERE first.list_id = tl.id AND w.personal_user_id = first.user_id;
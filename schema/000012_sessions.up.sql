@@ -0,0 +1,22 @@
+-- Сессия входа: семейство refresh-токенов, выданных по одному входу
+CREATE TABLE sessions
+(
+    id              serial                                              not null unique,
+    user_id         int references users (id) on delete cascade         not null,
+    created_at      timestamp with time zone                            not null default now(),
+    revoked_at      timestamp with time zone
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id) WHERE revoked_at IS NULL;
+
+CREATE TABLE refresh_tokens
+(
+    id              serial                                              not null unique,
+    session_id      int references sessions (id) on delete cascade      not null,
+    token_hash      varchar(64)                                         not null unique,
+    expires_at      timestamp with time zone                            not null,
+    used_at         timestamp with time zone, -- время обмена на новую пару токенов
+    created_at      timestamp with time zone                            not null default now()
+);
+
+CREATE INDEX refresh_tokens_session_id_idx ON refresh_tokens (session_id);
@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    DROP COLUMN device_name,
+    DROP COLUMN user_agent,
+    DROP COLUMN ip,
+    DROP COLUMN last_seen_at;
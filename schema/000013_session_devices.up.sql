@@ -0,0 +1,7 @@
+ALTER TABLE sessions
+    ADD COLUMN device_name  varchar(100)             not null default '',
+    ADD COLUMN user_agent   varchar(512)             not null default '',
+    ADD COLUMN ip           varchar(45)              not null default '', -- IPv4 или IPv6
+    ADD COLUMN last_seen_at timestamp with time zone not null default now();
+
+UPDATE sessions SET last_seen_at = created_at;
@@ -0,0 +1,13 @@
+CREATE TABLE access_tokens
+(
+    id              serial                                              not null unique,
+    user_id         int references users (id) on delete cascade         not null,
+    name            varchar(100)                                        not null,
+    token_hash      varchar(64)                                         not null unique,
+    scopes          text[]                                              not null,
+    created_at      timestamp with time zone                            not null default now(),
+    expires_at      timestamp with time zone, -- NULL - бессрочный токен
+    last_used_at    timestamp with time zone
+);
+
+CREATE INDEX access_tokens_user_id_idx ON access_tokens (user_id);
@@ -0,0 +1,13 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret    varchar(64), -- base32; задан, но не включен - подключение не подтверждено
+    ADD COLUMN totp_enabled   boolean not null default false,
+    ADD COLUMN totp_last_step bigint  not null default 0; -- шаг последнего принятого кода, защита от повторного использования
+
+CREATE TABLE recovery_codes
+(
+    id              serial                                              not null unique,
+    user_id         int references users (id) on delete cascade         not null,
+    code_hash       varchar(64)                                         not null,
+    used_at         timestamp with time zone,
+    UNIQUE (user_id, code_hash)
+);
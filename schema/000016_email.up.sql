@@ -0,0 +1,20 @@
+ALTER TABLE users
+    ADD COLUMN email             varchar(255), -- NULL у пользователей, зарегистрированных без почты
+    ADD COLUMN email_verified_at timestamp with time zone;
+
+CREATE UNIQUE INDEX users_email_idx ON users (lower(email));
+
+-- Одноразовые токены из писем (подтверждение почты, сброс пароля), хранится только хэш
+CREATE TABLE email_tokens
+(
+    id              serial                                              not null unique,
+    user_id         int references users (id) on delete cascade         not null,
+    purpose         varchar(16)                                         not null CHECK (purpose IN ('verify', 'reset')),
+    email           varchar(255)                                        not null, -- адрес, на который отправлено письмо
+    token_hash      varchar(64)                                         not null unique,
+    expires_at      timestamp with time zone                            not null,
+    used_at         timestamp with time zone,
+    created_at      timestamp with time zone                            not null default now()
+);
+
+CREATE INDEX email_tokens_user_id_idx ON email_tokens (user_id, purpose) WHERE used_at IS NULL;
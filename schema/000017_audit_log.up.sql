@@ -0,0 +1,13 @@
+-- Журнал событий безопасности (блокировки входа и т.п.)
+CREATE TABLE audit_log
+(
+    id          serial                                          not null unique,
+    user_id     int references users (id) on delete set null,  -- NULL, если пользователь неизвестен или удален
+    action      varchar(64)                                     not null,
+    ip          varchar(64)                                     not null default '',
+    details     jsonb                                           not null default '{}',
+    created_at  timestamp with time zone                        not null default now()
+);
+
+CREATE INDEX audit_log_user_id_idx ON audit_log (user_id, created_at);
+CREATE INDEX audit_log_action_idx ON audit_log (action, created_at);
@@ -0,0 +1,13 @@
+-- Учетные записи внешних провайдеров OIDC, связанные с пользователями
+CREATE TABLE user_identities
+(
+    id          serial                                          not null unique,
+    user_id     int references users (id) on delete cascade     not null,
+    provider    varchar(64)                                     not null, -- имя провайдера из конфигурации
+    subject     varchar(255)                                    not null, -- claim sub ID-токена
+    email       varchar(255)                                    not null default '',
+    created_at  timestamp with time zone                        not null default now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX user_identities_user_id_idx ON user_identities (user_id);
@@ -0,0 +1,4 @@
+-- Администраторы и отключенные учетные записи
+ALTER TABLE users
+    ADD COLUMN is_admin         boolean                     not null default false,
+    ADD COLUMN disabled_at      timestamp with time zone;   -- NULL - учетная запись активна
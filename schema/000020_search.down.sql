@@ -0,0 +1,8 @@
+DROP INDEX todo_items_search_idx;
+DROP INDEX todo_lists_search_idx;
+
+ALTER TABLE todo_items
+    DROP COLUMN search;
+
+ALTER TABLE todo_lists
+    DROP COLUMN search;
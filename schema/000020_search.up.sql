@@ -0,0 +1,15 @@
+-- Полнотекстовый поиск по названиям (вес A) и описаниям (вес B).
+-- Конфигурация russian стеммит кириллические слова русским стеммером, а латинские - английским,
+-- поэтому подходит для текстов на обоих языках
+ALTER TABLE todo_lists
+    ADD COLUMN search           tsvector    GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')) STORED;
+
+ALTER TABLE todo_items
+    ADD COLUMN search           tsvector    GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(description, '')), 'B')) STORED;
+
+CREATE INDEX todo_lists_search_idx ON todo_lists USING GIN (search);
+CREATE INDEX todo_items_search_idx ON todo_items USING GIN (search);
@@ -0,0 +1,32 @@
+package todo
+
+// Тип найденной записи
+const (
+	SearchTypeList = "list"
+	SearchTypeItem = "item"
+)
+
+const (
+	SearchDefaultLimit = 20
+	SearchMaxLimit     = 100
+)
+
+// Полнотекстовый поиск по спискам и задачам, доступным пользователю.
+// Query - слова для поиска, поддерживаются "фраза", OR и -исключение
+type SearchQuery struct {
+	Query  string `form:"q" binding:"required"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// Найденный список или задача, отсортированы по убыванию Rank.
+// В Title и Snippet совпадения выделены тегами <b></b>, остальной текст экранирован для HTML
+type SearchResult struct {
+	Type      string  `json:"type" db:"type" enums:"list,item"`
+	Id        int     `json:"id" db:"id"`
+	ListId    int     `json:"list_id" db:"list_id"`                 // для списка - его id, для задачи - список задачи
+	ListTitle string  `json:"list_title,omitempty" db:"list_title"` // название списка задачи
+	Title     string  `json:"title" db:"title"`                     // название с выделенными совпадениями
+	Snippet   string  `json:"snippet,omitempty" db:"snippet"`       // фрагменты описания с совпадениями
+	Rank      float64 `json:"rank" db:"rank"`
+}
@@ -0,0 +1,22 @@
+package todo
+
+import "time"
+
+// Сведения об устройстве, с которого выполнен вход
+type SessionClient struct {
+	DeviceName string // имя устройства, указанное клиентом при входе
+	UserAgent  string
+	Ip         string
+}
+
+// Сессия входа пользователя (устройство). LastSeenAt обновляется при входе и обмене refresh-токена,
+// поэтому точность - время жизни access-токена
+type Session struct {
+	Id         int       `json:"id" db:"id"`
+	DeviceName string    `json:"device_name" db:"device_name"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	Ip         string    `json:"ip" db:"ip"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+	Current    bool      `json:"current" db:"-"` // сессия, из которой выполнен запрос
+}
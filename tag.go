@@ -0,0 +1,63 @@
+package todo
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+type Tag struct {
+	Id    int    `json:"id" db:"id"`
+	Name  string `json:"name" db:"name" binding:"required,max=64"`
+	Color string `json:"color,omitempty" db:"color"` // цвет метки для клиента, например "#ff0000", до 16 символов
+}
+
+type UpdateTagInput struct {
+	Name  *string `json:"name"`
+	Color *string `json:"color"`
+}
+
+// Запятая зарезервирована как разделитель меток в фильтре GET /api/items?tag=...
+func ValidateTagName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("tag name is empty")
+	}
+	if strings.Contains(name, ",") {
+		return errors.New("tag name must not contain commas")
+	}
+	if len(name) > 64 {
+		return errors.New("tag name is too long")
+	}
+	return nil
+}
+
+// Длина ограничена полем color таблицы tags
+func ValidateTagColor(color string) error {
+	if utf8.RuneCountInString(color) > 16 {
+		return errors.New("tag color is too long")
+	}
+	return nil
+}
+
+// Проверка названия и цвета новой метки
+func (t Tag) Validate() error {
+	if err := ValidateTagName(t.Name); err != nil {
+		return err
+	}
+	return ValidateTagColor(t.Color)
+}
+
+func (i UpdateTagInput) Validate() error {
+	if i.Name == nil && i.Color == nil {
+		return errors.New("update structure has no values")
+	}
+	if i.Name != nil {
+		if err := ValidateTagName(*i.Name); err != nil {
+			return err
+		}
+	}
+	if i.Color != nil {
+		return ValidateTagColor(*i.Color)
+	}
+	return nil
+}
@@ -1,11 +1,18 @@
 package todo
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 type TodoList struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title" binding:"required"`
-	Description string ` json:"description" db:"description"`
+	Id          int      `json:"id" db:"id"`
+	Title       string   `json:"title" db:"title" binding:"required"`
+	Description string   ` json:"description" db:"description"`
+	Position    string   `json:"position,omitempty" db:"position"`                                          // ранг в ручной сортировке списков пользователя
+	Role        ListRole `json:"role,omitempty" db:"role" swaggertype:"string" enums:"owner,editor,viewer"` // роль пользователя в списке
+	WorkspaceId int      `json:"workspace_id,omitempty" db:"workspace_id"`                                  // пространство списка (0 при создании - личное)
 }
 
 type UserList struct {
@@ -15,10 +22,59 @@ type UserList struct {
 }
 
 type TodoItem struct {
-	Id          int    `json:"id" db:"id"`
-	Title       string `json:"title" db:"title" binding:"required"`
-	Description string `json:"description" db:"description"`
-	Done        bool   `json:"done" db:"done"`
+	Id          int        `json:"id" db:"id"`
+	Title       string     `json:"title" db:"title" binding:"required"`
+	Description string     `json:"description" db:"description"`
+	Done        bool       `json:"done" db:"done"`
+	DueDate     *time.Time `json:"due_date,omitempty" db:"due_date"`         // срок выполнения (nil - без срока)
+	DueAllDay   bool       `json:"due_all_day,omitempty" db:"due_all_day"`   // срок задан только датой, без времени
+	DueTimezone string     `json:"due_timezone,omitempty" db:"due_timezone"` // часовой пояс срока (IANA, например "Europe/Moscow")
+	Priority    Priority   `json:"priority,omitempty" db:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	ListId      int        `json:"list_id,omitempty" db:"list_id"`       // заполняется для отдельной задачи и в выборках по нескольким спискам
+	ParentId    *int       `json:"parent_id,omitempty" db:"parent_id"`   // родительская задача того же списка (nil - задача верхнего уровня)
+	Children    []TodoItem `json:"children,omitempty" db:"-"`            // подзадачи, заполняются при выдаче дерева
+	Recurrence  string     `json:"recurrence,omitempty" db:"recurrence"` // правило повторения RRULE, например "FREQ=WEEKLY;BYDAY=MO"
+	Occurrence  int        `json:"occurrence,omitempty" db:"occurrence"` // номер повторения в серии (с 1)
+	Position    string     `json:"position,omitempty" db:"position"`     // ранг в ручной сортировке задач списка
+}
+
+// Порядок выдачи задач списка
+const (
+	ItemSortDefault  = ""         // порядок по умолчанию
+	ItemSortPriority = "priority" // сначала более важные, при равном приоритете - более ранние
+	ItemSortTitle    = "title"    // по названию
+	ItemSortCreated  = "created"  // по времени создания
+)
+
+func ValidateItemSort(sort string) error {
+	if sort != ItemSortDefault && sort != ItemSortPriority && sort != ItemSortTitle && sort != ItemSortCreated {
+		return fmt.Errorf("invalid sort param: %s", sort)
+	}
+	return nil
+}
+
+// Перемещение списка или задачи при ручной сортировке.
+// Элемент ставится перед BeforeId или после AfterId (указывается что-то одно)
+type MoveInput struct {
+	BeforeId *int `json:"before_id"`
+	AfterId  *int `json:"after_id"`
+}
+
+func (i MoveInput) Validate(id int) error {
+	if (i.BeforeId == nil) == (i.AfterId == nil) {
+		return errors.New("move requires either before_id or after_id")
+	}
+
+	if (i.BeforeId != nil && *i.BeforeId == id) || (i.AfterId != nil && *i.AfterId == id) {
+		return errors.New("cannot move relative to itself")
+	}
+
+	return nil
+}
+
+// Список назначения при переносе или копировании задачи
+type TransferItemInput struct {
+	ListId int `json:"list_id" binding:"required"`
 }
 
 type ListsItem struct {
@@ -40,15 +96,98 @@ func (i UpdateListInput) Validate() error { // если нет обновляе
 }
 
 type UpdateItemInput struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Done        *bool   `json:"done"`
+	Title         *string    `json:"title"`
+	Description   *string    `json:"description"`
+	Done          *bool      `json:"done"`
+	DueDate       *time.Time `json:"due_date"`
+	DueAllDay     *bool      `json:"due_all_day"`
+	DueTimezone   *string    `json:"due_timezone"`
+	RemoveDueDate bool       `json:"remove_due_date"` // снять срок выполнения с задачи
+	Priority      *Priority  `json:"priority" swaggertype:"string" enums:"none,low,medium,high,urgent"`
+	ParentId      *int       `json:"parent_id"`    // 0 - сделать задачей верхнего уровня
+	CascadeDone   bool       `json:"cascade_done"` // применить done ко всем подзадачам
+	Recurrence    *string    `json:"recurrence"`   // "" - отменить повторение
 }
 
 func (i UpdateItemInput) Validate() error {
-	if i.Title == nil && i.Description == nil && i.Done == nil {
+	if i.Title == nil && i.Description == nil && i.Done == nil &&
+		i.DueDate == nil && i.DueAllDay == nil && i.DueTimezone == nil && !i.RemoveDueDate &&
+		i.Priority == nil && i.ParentId == nil && i.Recurrence == nil {
 		return errors.New("update structure has no values")
 	}
 
+	if i.CascadeDone && i.Done == nil {
+		return errors.New("cascade_done requires done value")
+	}
+
+	if i.ParentId != nil && *i.ParentId < 0 {
+		return errors.New("invalid parent_id")
+	}
+
+	if i.Priority != nil && !i.Priority.IsValid() {
+		return fmt.Errorf("invalid priority: %d", int(*i.Priority))
+	}
+
+	if i.RemoveDueDate && (i.DueDate != nil || i.DueAllDay != nil || i.DueTimezone != nil) {
+		return errors.New("remove_due_date conflicts with due date fields")
+	}
+
 	return nil
 }
+
+// Построение дерева подзадач из плоской выборки с сохранением порядка.
+// Задачи, родитель которых не попал в выборку, становятся корневыми
+func BuildItemTree(items []TodoItem) []TodoItem {
+	present := make(map[int]bool, len(items))
+	for _, item := range items {
+		present[item.Id] = true
+	}
+
+	children := make(map[int][]TodoItem)
+	roots := make([]TodoItem, 0)
+	for _, item := range items {
+		if item.ParentId != nil && present[*item.ParentId] && *item.ParentId != item.Id {
+			children[*item.ParentId] = append(children[*item.ParentId], item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+
+	var attach func(nodes []TodoItem, visited map[int]bool) []TodoItem
+	attach = func(nodes []TodoItem, visited map[int]bool) []TodoItem {
+		for i := range nodes {
+			if visited[nodes[i].Id] { // защита от циклов в поврежденных данных
+				continue
+			}
+			visited[nodes[i].Id] = true
+			if sub, ok := children[nodes[i].Id]; ok {
+				nodes[i].Children = attach(sub, visited)
+			}
+		}
+		return nodes
+	}
+
+	return attach(roots, make(map[int]bool))
+}
+
+// Приведение срока выполнения к часовому поясу задачи.
+// Для задач со сроком "на весь день" берется календарная дата в том виде, в каком ее передал клиент,
+// а время отбрасывается (полночь этих суток в поясе задачи).
+// Если пояс не указан, используется смещение, переданное в самой дате.
+func NormalizeDueDate(due time.Time, allDay bool, timezone string) (time.Time, error) {
+	loc := due.Location()
+	if timezone != "" {
+		l, err := time.LoadLocation(timezone)
+		if err != nil {
+			return due, fmt.Errorf("invalid due timezone: %s", timezone)
+		}
+		loc = l
+	}
+
+	if allDay {
+		year, month, day := due.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, loc), nil
+	}
+
+	return due.In(loc), nil
+}
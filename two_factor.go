@@ -0,0 +1,26 @@
+package todo
+
+// Состояние двухфакторной аутентификации пользователя
+type TwoFactor struct {
+	Username string  `db:"username"`
+	Secret   *string `db:"totp_secret"` // nil - 2FA не подключалась
+	Enabled  bool    `db:"totp_enabled"`
+	LastStep int64   `db:"totp_last_step"`
+}
+
+// Подключение 2FA: секрет и ссылка otpauth:// для приложения-аутентификатора
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Код из приложения-аутентификатора или код восстановления
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Второй шаг входа
+type TwoFactorVerifyInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // код из приложения или код восстановления
+}
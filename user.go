@@ -1,8 +1,84 @@
 package todo
 
+import (
+	"errors"
+	"strings"
+)
+
 type User struct {
 	Id       int    `json:"-" db:"id"`
 	Name     string `json:"name" binding:"required"`
 	Username string `json:"username" binding:"required"`
+	Password string `json:"password" db:"password_hash" binding:"required"`
+	Email    string `json:"email,omitempty" db:"email" binding:"omitempty,email"` // необязательный, для сброса пароля
+	// Почта подтверждена (ссылкой из письма или провайдером OIDC), при регистрации игнорируется
+	EmailVerified bool `json:"email_verified,omitempty" db:"email_verified"`
+	IsAdmin       bool `json:"-" db:"is_admin"`
+	Disabled      bool `json:"-" db:"disabled"` // вход и запросы с любыми токенами запрещены
+}
+
+// Данные пользователя для него самого (без пароля)
+type Profile struct {
+	Id            int    `json:"id"`
+	Name          string `json:"name"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+type UpdateUserInput struct {
+	Name     *string `json:"name"`
+	Username *string `json:"username"`
+	// Новый адрес требует повторного подтверждения
+	Email *string `json:"email" binding:"omitempty,email"`
+}
+
+func (i UpdateUserInput) Validate() error {
+	if i.Name == nil && i.Username == nil && i.Email == nil {
+		return errors.New("update structure has no values")
+	}
+	if (i.Name != nil && strings.TrimSpace(*i.Name) == "") || (i.Username != nil && strings.TrimSpace(*i.Username) == "") {
+		return errors.New("name and username must not be empty")
+	}
+	return nil
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Пароль не нужен только пользователям без пароля (созданным при входе через OIDC)
+type DeleteUserInput struct {
+	Password string `json:"password"`
+}
+
+// Пара токенов, выдаваемая при входе и обновлении
+type Tokens struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // время жизни access-токена в секундах
+}
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Назначение одноразовых токенов из писем
+const (
+	EmailTokenVerify = "verify"
+	EmailTokenReset  = "reset"
+)
+
+type VerifyEmailInput struct {
+	Token string `form:"token" binding:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
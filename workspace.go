@@ -0,0 +1,18 @@
+package todo
+
+import "fmt"
+
+// Рабочее пространство (команда). Списки пространства доступны всем его участникам
+// с ролью участника пространства. Роли те же, что и у участников списков.
+// Личное пространство создается для каждого пользователя и не может быть общим
+type Workspace struct {
+	Id       int      `json:"id" db:"id"`
+	Name     string   `json:"name" db:"name" binding:"required"`
+	Personal bool     `json:"personal" db:"personal"`
+	Role     ListRole `json:"role,omitempty" db:"role" swaggertype:"string" enums:"owner,editor,viewer"` // роль пользователя в пространстве
+}
+
+// Ошибка недостаточных прав в пространстве
+func ErrWorkspaceRole(required ListRole) error {
+	return fmt.Errorf("workspace role %s required", required)
+}